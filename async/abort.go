@@ -0,0 +1,187 @@
+package async
+
+import (
+	"sync"
+	"time"
+
+	"typescript-golang/timers"
+	"typescript-golang/types"
+)
+
+// AbortListener is called with the abort reason when a signal is aborted. It is an alias so
+// that *AbortSignal satisfies types.Signal.
+type AbortListener = func(reason error)
+
+// AbortSignal represents TypeScript's AbortSignal
+type AbortSignal struct {
+	mu        sync.Mutex
+	aborted   bool
+	reason    error
+	done      chan struct{}
+	listeners map[int]AbortListener
+	nextID    int
+}
+
+func newAbortSignal() *AbortSignal {
+	return &AbortSignal{
+		done:      make(chan struct{}),
+		listeners: make(map[int]AbortListener),
+	}
+}
+
+// Aborted reports whether the signal has been aborted (like signal.aborted in TypeScript)
+func (s *AbortSignal) Aborted() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.aborted
+}
+
+// Reason returns the abort reason, or nil if not aborted (like signal.reason in TypeScript)
+func (s *AbortSignal) Reason() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.reason
+}
+
+// Done returns a channel that is closed when the signal is aborted
+func (s *AbortSignal) Done() <-chan struct{} {
+	return s.done
+}
+
+// ThrowIfAborted returns the abort reason if the signal has been aborted
+// (like signal.throwIfAborted() in TypeScript)
+func (s *AbortSignal) ThrowIfAborted() error {
+	return s.Reason()
+}
+
+// OnAbort registers a listener for the abort event (like signal.addEventListener('abort') in TypeScript).
+// If the signal is already aborted the listener is invoked immediately.
+// The returned function removes the listener.
+func (s *AbortSignal) OnAbort(listener AbortListener) func() {
+	s.mu.Lock()
+	if s.aborted {
+		reason := s.reason
+		s.mu.Unlock()
+		listener(reason)
+		return func() {}
+	}
+
+	id := s.nextID
+	s.nextID++
+	s.listeners[id] = listener
+	s.mu.Unlock()
+
+	return func() {
+		s.mu.Lock()
+		delete(s.listeners, id)
+		s.mu.Unlock()
+	}
+}
+
+// abort marks the signal as aborted and notifies listeners exactly once
+func (s *AbortSignal) abort(reason error) bool {
+	s.mu.Lock()
+	if s.aborted {
+		s.mu.Unlock()
+		return false
+	}
+
+	if reason == nil {
+		reason = types.NewError("This operation was aborted", types.CancelledError)
+	}
+	s.aborted = true
+	s.reason = reason
+	listeners := make([]AbortListener, 0, len(s.listeners))
+	for id := 0; id < s.nextID; id++ {
+		if listener, exists := s.listeners[id]; exists {
+			listeners = append(listeners, listener)
+		}
+	}
+	s.listeners = nil
+	close(s.done)
+	s.mu.Unlock()
+
+	// Listeners run synchronously in registration order like DOM event dispatch
+	for _, listener := range listeners {
+		listener(reason)
+	}
+	return true
+}
+
+// AbortController represents TypeScript's AbortController
+type AbortController struct {
+	signal *AbortSignal
+}
+
+// NewAbortController creates a new AbortController (like new AbortController() in TypeScript)
+func NewAbortController() *AbortController {
+	return &AbortController{signal: newAbortSignal()}
+}
+
+// Signal returns the controller's signal (like controller.signal in TypeScript)
+func (c *AbortController) Signal() *AbortSignal {
+	return c.signal
+}
+
+// Abort aborts the signal with an optional reason (like controller.abort(reason) in TypeScript).
+// Without a reason a CancelledError is used.
+func (c *AbortController) Abort(reason ...error) {
+	var r error
+	if len(reason) > 0 {
+		r = reason[0]
+	}
+	c.signal.abort(r)
+}
+
+// AbortedSignal returns an already aborted signal (like AbortSignal.abort() in TypeScript)
+func AbortedSignal(reason ...error) *AbortSignal {
+	controller := NewAbortController()
+	controller.Abort(reason...)
+	return controller.Signal()
+}
+
+// TimeoutSignal returns a signal that aborts after a duration (like AbortSignal.timeout() in TypeScript)
+func TimeoutSignal(duration time.Duration, clock ...timers.Clock) *AbortSignal {
+	controller := NewAbortController()
+	timers.Resolve(clock...).AfterFunc(duration, func() {
+		controller.Abort(types.NewTimeoutError("signal timed out after " + duration.String()))
+	})
+	return controller.Signal()
+}
+
+// AnySignal returns a signal that aborts when any of the given signals aborts
+// (like AbortSignal.any() in TypeScript)
+func AnySignal(signals ...*AbortSignal) *AbortSignal {
+	controller := NewAbortController()
+	removers := make([]func(), 0, len(signals))
+
+	var once sync.Once
+	cleanup := func() {
+		once.Do(func() {
+			for _, remove := range removers {
+				remove()
+			}
+		})
+	}
+
+	for _, signal := range signals {
+		if signal == nil {
+			continue
+		}
+		if signal.Aborted() {
+			controller.Abort(signal.Reason())
+			cleanup()
+			return controller.Signal()
+		}
+		removers = append(removers, signal.OnAbort(func(reason error) {
+			controller.Abort(reason)
+		}))
+	}
+
+	// Detach from the source signals once this one fires so they don't keep it alive
+	controller.Signal().OnAbort(func(error) {
+		cleanup()
+	})
+
+	return controller.Signal()
+}
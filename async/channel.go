@@ -0,0 +1,90 @@
+package async
+
+import (
+	"errors"
+	"reflect"
+
+	"typescript-golang/types"
+)
+
+// ErrChannelClosed rejects a FromChannel promise whose channel closed without sending a value
+var ErrChannelClosed = errors.New("channel closed before a value was received")
+
+// Settleable is implemented by every *Promise[T], so promises of different types can be
+// combined with Select
+type Settleable interface {
+	// Done returns a channel that is closed once the promise settles
+	Done() <-chan struct{}
+	// GetState returns the current state of the promise
+	GetState() PromiseState
+}
+
+// Done returns a channel that is closed once the promise settles. Any number of goroutines
+// may wait on it, which makes promises usable in select statements:
+//
+//	select {
+//	case <-promise.Done():
+//		value, err := promise.Await() // returns immediately
+//	case job := <-jobs:
+//	}
+func (p *Promise[T]) Done() <-chan struct{} {
+	p.trigger()
+	return p.done
+}
+
+// FromChannel creates a promise that resolves with the next value received from ch. It
+// rejects with ErrChannelClosed if ch is closed first, or with the abort reason if one of
+// the optional signals aborts first.
+func FromChannel[T any](ch <-chan T, signals ...*AbortSignal) *Promise[T] {
+	return NewPromiseWithSignal[T](func(signal *AbortSignal) (T, error) {
+		select {
+		case value, ok := <-ch:
+			if !ok {
+				var zero T
+				return zero, ErrChannelClosed
+			}
+			return value, nil
+		case <-signal.Done():
+			var zero T
+			return zero, signal.Reason()
+		}
+	}, signals...)
+}
+
+// ToChannel returns a channel that receives the promise's outcome once it settles and is then
+// closed. The channel is buffered, so the promise never waits for a receiver.
+func ToChannel[T any](p *Promise[T]) <-chan types.Result[T, error] {
+	ch := make(chan types.Result[T, error], 1)
+	p.trigger()
+	p.onSettle(func() {
+		value, err := p.settledResult()
+		if err != nil {
+			ch <- types.Err[T, error](err)
+		} else {
+			ch <- types.Ok[T, error](value)
+		}
+		close(ch)
+	})
+	return ch
+}
+
+// Select resolves with the index of the first promise to settle, fulfilled or rejected
+// (like a select statement over promises of different types). Await the winning promise to
+// get its value. With no promises the returned promise never settles, like Race.
+func Select(promises ...Settleable) *Promise[int] {
+	if len(promises) == 0 {
+		return newPromise[int]()
+	}
+
+	cases := make([]reflect.SelectCase, len(promises))
+	for i, promise := range promises {
+		cases[i] = reflect.SelectCase{
+			Dir:  reflect.SelectRecv,
+			Chan: reflect.ValueOf(promise.Done()),
+		}
+	}
+	return NewPromise[int](func() (int, error) {
+		chosen, _, _ := reflect.Select(cases)
+		return chosen, nil
+	})
+}
@@ -0,0 +1,100 @@
+package async
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDoneReleasesEveryWaiter(t *testing.T) {
+	deferred := WithResolvers[int]()
+	const waiters = 8
+	var ready, finished sync.WaitGroup
+	ready.Add(waiters)
+	finished.Add(waiters)
+	values := make(chan int, waiters)
+	for i := 0; i < waiters; i++ {
+		go func() {
+			defer finished.Done()
+			done := deferred.Promise.Done()
+			ready.Done()
+			<-done
+			// Await returns immediately once Done is closed
+			value, _ := deferred.Promise.AwaitWithTimeout(time.Millisecond)
+			values <- value
+		}()
+	}
+
+	ready.Wait()
+	select {
+	case <-deferred.Promise.Done():
+		t.Fatal("Done closed before the promise settled")
+	default:
+	}
+	deferred.Resolve(7)
+	finished.Wait()
+	close(values)
+	for value := range values {
+		if value != 7 {
+			t.Fatalf("a waiter got %d, want 7", value)
+		}
+	}
+}
+
+func TestSelectReportsFirstSettled(t *testing.T) {
+	first, second, third := WithResolvers[int](), WithResolvers[string](), WithResolvers[bool]()
+	selected := Select(first.Promise, second.Promise, third.Promise)
+
+	second.Resolve("second")
+	if index, err := selected.AwaitWithTimeout(time.Second); err != nil || index != 1 {
+		t.Fatalf("Select = %d, %v, want 1", index, err)
+	}
+	if value, _ := second.Promise.Await(); value != "second" {
+		t.Fatalf("the winner resolved with %q", value)
+	}
+
+	// A rejection settles a promise too
+	rejected := Select(first.Promise, third.Promise)
+	third.Reject(errors.New("third"))
+	if index, err := rejected.AwaitWithTimeout(time.Second); err != nil || index != 1 {
+		t.Fatalf("Select = %d, %v, want 1", index, err)
+	}
+	if first.Promise.GetState() != Pending {
+		t.Fatal("Select settled a losing promise")
+	}
+}
+
+func TestFromChannel(t *testing.T) {
+	ch := make(chan int, 1)
+	ch <- 5
+	if value, err := FromChannel(ch).AwaitWithTimeout(time.Second); err != nil || value != 5 {
+		t.Fatalf("got %d, %v, want 5", value, err)
+	}
+
+	close(ch)
+	if _, err := FromChannel(ch).AwaitWithTimeout(time.Second); err != ErrChannelClosed {
+		t.Fatalf("got %v, want ErrChannelClosed", err)
+	}
+
+	reason := errors.New("stop")
+	if _, err := FromChannel(make(chan int), AbortedSignal(reason)).AwaitWithTimeout(time.Second); err != reason {
+		t.Fatalf("got %v, want the abort reason", err)
+	}
+}
+
+func TestToChannel(t *testing.T) {
+	result, ok := <-ToChannel(Resolve(3))
+	if !ok || !result.IsOk() || result.Unwrap() != 3 {
+		t.Fatalf("got %v, want Ok(3)", result)
+	}
+
+	reason := errors.New("failed")
+	ch := ToChannel(Reject[int](reason))
+	if result := <-ch; !result.IsErr() || result.UnwrapErr() != reason {
+		t.Fatalf("got %v, want the rejection", result)
+	}
+	if _, ok := <-ch; ok {
+		t.Fatal("the channel was not closed after the outcome")
+	}
+}
@@ -0,0 +1,85 @@
+package async
+
+import (
+	"sync"
+
+	"typescript-golang/types"
+)
+
+// PromiseWithResolvers represents TypeScript's PromiseWithResolvers<T>
+type PromiseWithResolvers[T any] struct {
+	Promise *Promise[T]
+	// Resolve fulfills the promise; calls after the promise settled are ignored
+	Resolve func(value T)
+	// Reject rejects the promise; calls after the promise settled are ignored
+	Reject func(err error)
+}
+
+// WithResolvers creates a pending promise that is settled from the outside
+// (like Promise.withResolvers() in TypeScript). Resolve and Reject are safe to call
+// from any goroutine and only the first call has any effect.
+func WithResolvers[T any]() PromiseWithResolvers[T] {
+	p := newPromise[T]()
+	return PromiseWithResolvers[T]{
+		Promise: p,
+		Resolve: func(value T) {
+			p.settle(value, nil)
+		},
+		Reject: func(err error) {
+			if err == nil {
+				err = types.NewError("promise rejected with nil error")
+			}
+			var zero T
+			p.settle(zero, err)
+		},
+	}
+}
+
+// Lazy creates a promise whose executor does not run until the promise is first
+// awaited or chained (like p-lazy in TypeScript)
+func Lazy[T any](executor Executor[T]) *Promise[T] {
+	p := newPromise[T]()
+	p.lazy = &sync.Once{}
+	p.start = func() {
+		p.run(executor)
+	}
+	return p
+}
+
+// trigger is called whenever the promise is awaited or chained. It starts a lazy promise's
+// executor and marks the promise as handled for unhandled-rejection tracking.
+func (p *Promise[T]) trigger() {
+	p.markHandled()
+	if p.lazy != nil {
+		p.lazy.Do(p.start)
+	}
+}
+
+// Once returns a promise that resolves with the next emission of an event
+// (like events.once(emitter, name) in Node.js). The promise rejects with the
+// abort reason if any of the optional signals abort first, e.g. a TimeoutSignal.
+func Once[T any](emitter *types.EventEmitter[T], event string, signals ...*AbortSignal) *Promise[T] {
+	deferred := WithResolvers[T]()
+
+	// Settling the promise either way removes the listener, so aborted waits do not leak
+	listening := NewAbortController()
+	emitter.Once(event, func(data T) {
+		deferred.Resolve(data)
+	}, types.ListenerOptions{Signal: listening.Signal()})
+	deferred.Promise.onSettle(func() {
+		listening.Abort()
+	})
+
+	for _, signal := range signals {
+		if signal == nil {
+			continue
+		}
+		remove := signal.OnAbort(func(reason error) {
+			deferred.Reject(reason)
+		})
+		// Stop listening for the abort once the event has arrived
+		deferred.Promise.onSettle(remove)
+	}
+
+	return deferred.Promise
+}
@@ -0,0 +1,40 @@
+package async
+
+import (
+	"testing"
+	"time"
+
+	"typescript-golang/types"
+)
+
+func TestOnceRemovesListenerOnAbort(t *testing.T) {
+	emitter := types.NewEventEmitter[int]()
+	for i := 0; i < 20; i++ {
+		controller := NewAbortController()
+		p := Once(emitter, "ready", controller.Signal())
+		controller.Abort()
+		if _, err := p.AwaitWithTimeout(time.Second); !types.IsErrorCode(err, types.CancelledError) {
+			t.Fatalf("expected the abort reason, got %v", err)
+		}
+	}
+	if count := emitter.ListenerCount("ready"); count != 0 {
+		t.Fatalf("aborted waits left %d listeners", count)
+	}
+}
+
+func TestOnceResolvesWithEvent(t *testing.T) {
+	emitter := types.NewEventEmitter[int]()
+	signal := TimeoutSignal(time.Minute)
+	first := Once(emitter, "ready", signal)
+	second := Once(emitter, "ready")
+
+	emitter.EmitSync("ready", 7)
+	for _, p := range []*Promise[int]{first, second} {
+		if v, err := p.AwaitWithTimeout(time.Second); err != nil || v != 7 {
+			t.Fatalf("got %v, %v", v, err)
+		}
+	}
+	if count := emitter.ListenerCount("ready"); count != 0 {
+		t.Fatalf("resolved waits left %d listeners", count)
+	}
+}
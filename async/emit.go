@@ -0,0 +1,22 @@
+package async
+
+import "typescript-golang/types"
+
+// EmitAsync emits an event and returns a promise that resolves once every listener has
+// finished, with whether the event had listeners (like awaiting every listener of an async
+// emit). Listeners are matched before EmitAsync returns. The promise rejects with the
+// listeners' recovered panics, aggregated as by EventEmitter.EmitWait.
+func EmitAsync[T any](emitter *types.EventEmitter[T], event string, data T) *Promise[bool] {
+	called, wait := emitter.StartEmit(event, data)
+	return NewPromise(func() (bool, error) {
+		return called, wait()
+	})
+}
+
+// EmitKeyAsync is EmitAsync for a keyed event of a TypedEventEmitter
+func EmitKeyAsync[T any](emitter *types.TypedEventEmitter, key types.EventKey[T], payload T) *Promise[bool] {
+	called, wait := types.StartEmit(emitter, key, payload)
+	return NewPromise(func() (bool, error) {
+		return called, wait()
+	})
+}
@@ -0,0 +1,386 @@
+package async
+
+import (
+	"container/heap"
+	"fmt"
+	"sync"
+	"time"
+
+	"typescript-golang/timers"
+)
+
+// loopTask is a macrotask: an immediate, a timeout or an interval
+type loopTask struct {
+	id       int
+	fn       func()
+	when     time.Time
+	interval time.Duration
+	seq      uint64
+	index    int
+	cleared  bool
+}
+
+type loopTimerHeap []*loopTask
+
+func (h loopTimerHeap) Len() int { return len(h) }
+func (h loopTimerHeap) Less(i, j int) bool {
+	if !h[i].when.Equal(h[j].when) {
+		return h[i].when.Before(h[j].when)
+	}
+	return h[i].seq < h[j].seq
+}
+func (h loopTimerHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+func (h *loopTimerHeap) Push(x interface{}) {
+	task := x.(*loopTask)
+	task.index = len(*h)
+	*h = append(*h, task)
+}
+func (h *loopTimerHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	task := old[n-1]
+	old[n-1] = nil
+	task.index = -1
+	*h = old[:n-1]
+	return task
+}
+
+// EventLoop runs callbacks on a single goroutine in JavaScript order: after every
+// macrotask (timeouts, intervals, immediates) the microtask queue is drained completely.
+// Promises created with NewPromiseOn, ResolveOn, RejectOn or WithResolversOn are bound to
+// the loop, and their Then/Catch/Finally reactions run as microtasks instead of on new
+// goroutines, so callback order is deterministic.
+type EventLoop struct {
+	clock      timers.Clock
+	mu         sync.Mutex
+	microtasks []func()
+	macrotasks []*loopTask
+	timers     loopTimerHeap
+	tasks      map[int]*loopTask
+	nextID     int
+	seq        uint64
+	wake       chan struct{}
+	running    bool
+	stopped    bool
+}
+
+// NewEventLoop creates a new EventLoop. Timers use the optional clock or the default one;
+// with a timers.FakeClock the loop waits for the clock to be advanced.
+func NewEventLoop(clock ...timers.Clock) *EventLoop {
+	return &EventLoop{
+		clock: timers.Resolve(clock...),
+		tasks: make(map[int]*loopTask),
+		wake:  make(chan struct{}, 1),
+	}
+}
+
+// signal wakes the loop if it is waiting for work
+func (l *EventLoop) signal() {
+	select {
+	case l.wake <- struct{}{}:
+	default:
+	}
+}
+
+// QueueMicrotask schedules fn to run before the next macrotask (like queueMicrotask() in TypeScript).
+// It is safe to call from any goroutine.
+func (l *EventLoop) QueueMicrotask(fn func()) {
+	l.mu.Lock()
+	l.microtasks = append(l.microtasks, fn)
+	l.mu.Unlock()
+	l.signal()
+}
+
+// addTimer registers a timeout or interval and returns its ID
+func (l *EventLoop) addTimer(fn func(), delay, interval time.Duration) int {
+	if delay < 0 {
+		delay = 0
+	}
+
+	l.mu.Lock()
+	l.nextID++
+	task := &loopTask{
+		id:       l.nextID,
+		fn:       fn,
+		when:     l.clock.Now().Add(delay),
+		interval: interval,
+		seq:      l.seq,
+	}
+	l.seq++
+	l.tasks[task.id] = task
+	heap.Push(&l.timers, task)
+	l.mu.Unlock()
+
+	l.signal()
+	return task.id
+}
+
+// SetTimeout runs fn once after delay (like setTimeout() in TypeScript) and returns its ID
+func (l *EventLoop) SetTimeout(fn func(), delay time.Duration) int {
+	return l.addTimer(fn, delay, 0)
+}
+
+// SetInterval runs fn every interval (like setInterval() in TypeScript) and returns its ID
+func (l *EventLoop) SetInterval(fn func(), interval time.Duration) int {
+	if interval <= 0 {
+		interval = time.Millisecond
+	}
+	return l.addTimer(fn, interval, interval)
+}
+
+// SetImmediate runs fn as the next macrotask (like setImmediate() in Node.js) and returns its ID
+func (l *EventLoop) SetImmediate(fn func()) int {
+	l.mu.Lock()
+	l.nextID++
+	task := &loopTask{id: l.nextID, fn: fn, index: -1}
+	l.tasks[task.id] = task
+	l.macrotasks = append(l.macrotasks, task)
+	l.mu.Unlock()
+
+	l.signal()
+	return task.id
+}
+
+// clear cancels a scheduled macrotask by ID
+func (l *EventLoop) clear(id int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	task, exists := l.tasks[id]
+	if !exists {
+		return
+	}
+	task.cleared = true
+	delete(l.tasks, id)
+	if task.index >= 0 && task.index < l.timers.Len() && l.timers[task.index] == task {
+		heap.Remove(&l.timers, task.index)
+	}
+}
+
+// ClearTimeout cancels a timeout (like clearTimeout() in TypeScript)
+func (l *EventLoop) ClearTimeout(id int) {
+	l.clear(id)
+}
+
+// ClearInterval cancels an interval (like clearInterval() in TypeScript)
+func (l *EventLoop) ClearInterval(id int) {
+	l.clear(id)
+}
+
+// ClearImmediate cancels an immediate (like clearImmediate() in Node.js)
+func (l *EventLoop) ClearImmediate(id int) {
+	l.clear(id)
+}
+
+// drainMicrotasks runs microtasks until the queue is empty, including ones queued meanwhile
+func (l *EventLoop) drainMicrotasks() bool {
+	ran := false
+	for {
+		l.mu.Lock()
+		if len(l.microtasks) == 0 {
+			l.mu.Unlock()
+			return ran
+		}
+		task := l.microtasks[0]
+		l.microtasks[0] = nil
+		l.microtasks = l.microtasks[1:]
+		l.mu.Unlock()
+
+		task()
+		ran = true
+	}
+}
+
+// tick runs pending microtasks and at most one macrotask. It returns false if there was nothing to do.
+func (l *EventLoop) tick() bool {
+	ran := l.drainMicrotasks()
+
+	l.mu.Lock()
+	now := l.clock.Now()
+	for l.timers.Len() > 0 && !l.timers[0].when.After(now) {
+		l.macrotasks = append(l.macrotasks, heap.Pop(&l.timers).(*loopTask))
+	}
+	if len(l.macrotasks) == 0 {
+		l.mu.Unlock()
+		return ran
+	}
+	task := l.macrotasks[0]
+	l.macrotasks[0] = nil
+	l.macrotasks = l.macrotasks[1:]
+	if task.cleared {
+		l.mu.Unlock()
+		return true
+	}
+	if task.interval == 0 {
+		delete(l.tasks, task.id)
+	}
+	l.mu.Unlock()
+
+	task.fn()
+
+	if task.interval > 0 {
+		l.mu.Lock()
+		if !task.cleared {
+			task.when = task.when.Add(task.interval)
+			if now := l.clock.Now(); task.when.Before(now) {
+				task.when = now
+			}
+			task.seq = l.seq
+			l.seq++
+			heap.Push(&l.timers, task)
+		}
+		l.mu.Unlock()
+	}
+
+	l.drainMicrotasks()
+	return true
+}
+
+// loop processes tasks until stopped, or until idle when untilIdle is set
+func (l *EventLoop) loop(untilIdle bool) error {
+	l.mu.Lock()
+	if l.running {
+		l.mu.Unlock()
+		return fmt.Errorf("event loop is already running")
+	}
+	l.running = true
+	l.stopped = false
+	l.mu.Unlock()
+
+	defer func() {
+		l.mu.Lock()
+		l.running = false
+		l.mu.Unlock()
+	}()
+
+	for {
+		if l.tick() {
+			continue
+		}
+
+		l.mu.Lock()
+		if l.stopped {
+			l.mu.Unlock()
+			return nil
+		}
+		var wait <-chan time.Time
+		var timer timers.Timer
+		if l.timers.Len() > 0 {
+			timer = l.clock.NewTimer(l.timers[0].when.Sub(l.clock.Now()))
+			wait = timer.C()
+		} else if untilIdle {
+			l.mu.Unlock()
+			return nil
+		}
+		l.mu.Unlock()
+
+		select {
+		case <-l.wake:
+		case <-wait:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}
+}
+
+// Run processes tasks on the calling goroutine until Stop is called
+func (l *EventLoop) Run() error {
+	return l.loop(false)
+}
+
+// RunUntilIdle processes tasks on the calling goroutine until no microtasks, macrotasks
+// or timers remain, waiting for pending timers to fire
+func (l *EventLoop) RunUntilIdle() error {
+	return l.loop(true)
+}
+
+// Stop makes Run return once the current task finishes
+func (l *EventLoop) Stop() {
+	l.mu.Lock()
+	l.stopped = true
+	l.mu.Unlock()
+	l.signal()
+}
+
+// Pending returns the number of queued microtasks, macrotasks and timers
+func (l *EventLoop) Pending() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.microtasks) + len(l.macrotasks) + l.timers.Len()
+}
+
+// NewPromiseOn creates a promise bound to the loop. Like the Promise constructor in
+// TypeScript the executor runs synchronously on the calling goroutine, so it should not block;
+// use WithResolversOn for work that completes later.
+func NewPromiseOn[T any](loop *EventLoop, executor Executor[T]) *Promise[T] {
+	p := newPromise[T]()
+	p.loop = loop
+	p.execute(executor)
+	return p
+}
+
+// ResolveOn creates a fulfilled promise bound to the loop (like Promise.resolve() in TypeScript)
+func ResolveOn[T any](loop *EventLoop, value T) *Promise[T] {
+	p := newPromise[T]()
+	p.loop = loop
+	p.settle(value, nil)
+	return p
+}
+
+// RejectOn creates a rejected promise bound to the loop (like Promise.reject() in TypeScript)
+func RejectOn[T any](loop *EventLoop, err error) *Promise[T] {
+	p := newPromise[T]()
+	p.loop = loop
+	var zero T
+	p.settle(zero, err)
+	return p
+}
+
+// WithResolversOn creates a pending promise bound to the loop that is settled from the
+// outside. Resolve and Reject may be called from any goroutine; reactions still run on the loop.
+func WithResolversOn[T any](loop *EventLoop) PromiseWithResolvers[T] {
+	deferred := WithResolvers[T]()
+	deferred.Promise.loop = loop
+	return deferred
+}
+
+// settledResult returns the value and error of a settled promise
+func (p *Promise[T]) settledResult() (T, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.state == Fulfilled {
+		return p.value, nil
+	}
+	var zero T
+	return zero, p.error
+}
+
+// react schedules a reaction as a microtask on p's loop once p settles. The reaction's
+// result settles the returned child promise, which is bound to the same loop.
+func react[T, U any](p *Promise[T], reaction func(value T, err error) (U, error)) *Promise[U] {
+	child := newPromise[U]()
+	child.loop = p.loop
+	p.onSettle(func() {
+		p.loop.QueueMicrotask(func() {
+			child.execute(func() (U, error) {
+				return reaction(p.settledResult())
+			})
+		})
+	})
+	return child
+}
+
+// adopt settles child with the outcome of next, scheduling the settlement on child's loop
+func adopt[T any](child *Promise[T], next *Promise[T]) {
+	next.trigger()
+	next.onSettle(func() {
+		child.loop.QueueMicrotask(func() {
+			child.settle(next.settledResult())
+		})
+	})
+}
@@ -0,0 +1,96 @@
+package async
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"typescript-golang/timers"
+)
+
+// runUntilIdle runs the loop until it is idle, moving the fake clock forward whenever the
+// loop waits for a timer
+func runUntilIdle(t *testing.T, loop *EventLoop, clock *timers.FakeClock) {
+	t.Helper()
+	done := make(chan error, 1)
+	go func() { done <- loop.RunUntilIdle() }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatal(err)
+			}
+			return
+		default:
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the event loop did not become idle")
+		}
+		if clock.TimerCount() > 0 {
+			clock.Advance(time.Millisecond)
+		} else {
+			time.Sleep(time.Millisecond)
+		}
+	}
+}
+
+func TestEventLoopOrdering(t *testing.T) {
+	clock := timers.NewFakeClock()
+	loop := NewEventLoop(clock)
+	var calls []string
+	log := func(name string) func() {
+		return func() { calls = append(calls, name) }
+	}
+
+	loop.SetTimeout(func() {
+		calls = append(calls, "timeout 10ms")
+		loop.QueueMicrotask(log("microtask from timeout"))
+	}, 10*time.Millisecond)
+	ticks := 0
+	var interval int
+	interval = loop.SetInterval(func() {
+		ticks++
+		calls = append(calls, "interval")
+		if ticks == 2 {
+			loop.ClearInterval(interval)
+		}
+	}, 10*time.Millisecond)
+	loop.SetImmediate(func() {
+		calls = append(calls, "immediate")
+		Then(ResolveOn(loop, 0), func(int) int {
+			calls = append(calls, "then from immediate")
+			return 0
+		}, nil)
+	})
+	loop.SetTimeout(log("timeout 0"), 0)
+	loop.QueueMicrotask(log("microtask"))
+	first := Then(ResolveOn(loop, 1), func(v int) int {
+		calls = append(calls, "then")
+		return v + 1
+	}, nil)
+	Then(first, func(int) int {
+		calls = append(calls, "chained then")
+		return 0
+	}, nil)
+
+	runUntilIdle(t, loop, clock)
+
+	want := []string{
+		// Microtasks, including ones queued while draining, run before the first macrotask
+		"microtask", "then", "chained then",
+		"immediate", "then from immediate",
+		"timeout 0",
+		// Timers due together run in scheduling order, each followed by its microtasks
+		"timeout 10ms", "microtask from timeout",
+		"interval",
+		"interval",
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Fatalf("calls = %q\nwant    %q", calls, want)
+	}
+	if loop.Pending() != 0 {
+		t.Fatalf("%d tasks still pending", loop.Pending())
+	}
+}
@@ -0,0 +1,192 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"typescript-golang/types"
+)
+
+// TaskGroup ties a set of tasks to a shared lifetime (like Python's asyncio.TaskGroup or Go's
+// errgroup). Every task receives a signal derived from the group's signal; the first task to
+// fail aborts the group, cancelling its siblings. Nested groups created from a task's signal
+// are cancelled along with their parent.
+type TaskGroup struct {
+	controller *AbortController
+	ctx        context.Context
+	release    func()
+	finished   chan struct{}
+	waitOnce   sync.Once
+	wg         sync.WaitGroup
+
+	mu     sync.Mutex
+	errors []error
+}
+
+// NewTaskGroup creates a TaskGroup that is also cancelled when any of the parent signals abort
+func NewTaskGroup(parents ...*AbortSignal) *TaskGroup {
+	return NewTaskGroupWithContext(context.Background(), parents...)
+}
+
+// NewTaskGroupWithContext creates a TaskGroup that is cancelled when ctx is done or any of the
+// parent signals abort
+func NewTaskGroupWithContext(ctx context.Context, parents ...*AbortSignal) *TaskGroup {
+	g := &TaskGroup{
+		controller: NewAbortController(),
+		finished:   make(chan struct{}),
+	}
+
+	var detach []func()
+	for _, parent := range parents {
+		if parent == nil {
+			continue
+		}
+		detach = append(detach, parent.OnAbort(func(reason error) {
+			g.controller.Abort(reason)
+		}))
+	}
+	g.release = func() {
+		for _, remove := range detach {
+			remove()
+		}
+	}
+
+	groupCtx, cancel := context.WithCancel(ctx)
+	g.ctx = groupCtx
+	g.controller.Signal().OnAbort(func(reason error) {
+		cancel()
+	})
+	if ctx.Done() != nil {
+		go func() {
+			select {
+			case <-ctx.Done():
+				g.controller.Abort(ctx.Err())
+			case <-g.finished:
+			}
+		}()
+	}
+
+	return g
+}
+
+// Signal returns the group's signal, which aborts when a task fails or the group is cancelled
+func (g *TaskGroup) Signal() *AbortSignal {
+	return g.controller.Signal()
+}
+
+// Context returns a context that is cancelled together with the group's signal, e.g. for
+// AwaitWithContext or APIs that take a context
+func (g *TaskGroup) Context() context.Context {
+	return g.ctx
+}
+
+// Cancel aborts the group and every running task with the reason (a CancelledError by default)
+func (g *TaskGroup) Cancel(reason ...error) {
+	g.controller.Abort(reason...)
+}
+
+// Go starts fn in the group. The returned promise resolves with nil when fn succeeds.
+func (g *TaskGroup) Go(fn func(signal *AbortSignal) error) *Promise[interface{}] {
+	return Go(g, func(signal *AbortSignal) (interface{}, error) {
+		return nil, fn(signal)
+	})
+}
+
+// Go starts executor in the group and returns its promise. The promise rejects immediately
+// when the group is cancelled, but Wait still waits for the executor to return. A panic in
+// the executor rejects the promise with an InternalError carrying the panic's stack trace
+// and fails the group.
+func Go[T any](g *TaskGroup, executor SignalExecutor[T]) *Promise[T] {
+	g.mu.Lock()
+	index := len(g.errors)
+	g.errors = append(g.errors, nil)
+	g.mu.Unlock()
+
+	p, release := newSignalPromise[T]([]*AbortSignal{g.Signal()})
+	signal := p.controller.Signal()
+	if signal.Aborted() {
+		// The group is already cancelled; the promise has been rejected with its reason
+		release()
+		return p
+	}
+
+	g.wg.Add(1)
+	p.run(func() (T, error) {
+		defer g.wg.Done()
+		defer release()
+
+		value, err := runTask(executor, signal)
+		// Settle before recording so a failing task rejects with its own error rather than
+		// with the group cancellation it triggers
+		p.settle(value, err)
+		g.record(index, err)
+		return value, err
+	})
+	return p
+}
+
+// runTask calls executor, converting a panic into an EnhancedError with the panic's stack
+func runTask[T any](executor SignalExecutor[T], signal *AbortSignal) (value T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = types.NewError(fmt.Sprintf("panic: %v", r), types.InternalError).WithData("panic", r)
+		}
+	}()
+	return executor(signal)
+}
+
+// record stores a task's failure and cancels the siblings. Errors caused by the group's own
+// cancellation are not failures.
+func (g *TaskGroup) record(index int, err error) {
+	if err == nil || g.cancelledBy(err) {
+		return
+	}
+
+	g.mu.Lock()
+	g.errors[index] = err
+	g.mu.Unlock()
+
+	g.controller.Abort(types.NewErrorWithCause("task group cancelled after a task failed", err, types.CancelledError))
+}
+
+// cancelledBy reports whether err is the result of the group having been cancelled
+func (g *TaskGroup) cancelledBy(err error) bool {
+	signal := g.Signal()
+	if !signal.Aborted() {
+		return false
+	}
+	if errors.Is(err, signal.Reason()) || errors.Is(err, context.Canceled) {
+		return true
+	}
+	var enhanced *types.EnhancedError
+	return errors.As(err, &enhanced) && enhanced.Code() == types.CancelledError
+}
+
+// Wait blocks until every task has returned. It returns nil if all tasks succeeded, a
+// *types.AggregateError holding each failure at its task's index (in Go call order), or the
+// cancellation reason if the group was cancelled without any task failing. The group is
+// detached from its parents afterwards and should not be reused.
+func (g *TaskGroup) Wait() error {
+	g.wg.Wait()
+	g.waitOnce.Do(func() {
+		close(g.finished)
+		g.release()
+	})
+
+	g.mu.Lock()
+	errs := make([]error, len(g.errors))
+	copy(errs, g.errors)
+	g.mu.Unlock()
+
+	for _, err := range errs {
+		if err != nil {
+			return types.NewAggregateError("task group failed", errs)
+		}
+	}
+	if signal := g.Signal(); signal.Aborted() {
+		return signal.Reason()
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"typescript-golang/types"
+)
+
+// waitForAbort blocks a task until its signal aborts and returns the abort reason
+func waitForAbort(signal *AbortSignal) error {
+	select {
+	case <-signal.Done():
+		return signal.Reason()
+	case <-time.After(time.Second):
+		return errors.New("the task was not cancelled")
+	}
+}
+
+// explode panics inside a task so the captured stack can be checked for it
+func explode(signal *AbortSignal) error {
+	panic("boom")
+}
+
+func TestTaskGroupFirstFailureAbortsSiblings(t *testing.T) {
+	g := NewTaskGroup()
+	sibling := make(chan error, 1)
+	g.Go(func(signal *AbortSignal) error {
+		err := waitForAbort(signal)
+		sibling <- err
+		return err
+	})
+	failure := errors.New("failed")
+	failed := g.Go(func(signal *AbortSignal) error { return failure })
+
+	if err := <-sibling; !types.IsErrorCode(err, types.CancelledError) || !errors.Is(err, failure) {
+		t.Fatalf("the sibling was cancelled with %v, want a CancelledError caused by the failure", err)
+	}
+	if _, err := failed.AwaitWithTimeout(time.Second); err != failure {
+		t.Fatalf("the failing task rejected with %v, want its own error", err)
+	}
+	if !g.Signal().Aborted() || g.Context().Err() == nil {
+		t.Fatal("the group signal and context were not cancelled")
+	}
+	if err := g.Wait(); !errors.Is(err, failure) {
+		t.Fatalf("Wait() = %v, want the failure", err)
+	}
+}
+
+func TestTaskGroupWaitAggregatesFailures(t *testing.T) {
+	g := NewTaskGroup()
+	first, second := errors.New("first"), errors.New("second")
+	started := make(chan struct{})
+	g.Go(func(signal *AbortSignal) error { return nil })
+	g.Go(func(signal *AbortSignal) error {
+		<-started
+		return first
+	})
+	// This task fails as well, ignoring the cancellation caused by its sibling
+	g.Go(func(signal *AbortSignal) error {
+		close(started)
+		<-signal.Done()
+		return second
+	})
+
+	err := g.Wait()
+	var aggregate *types.AggregateError
+	if !errors.As(err, &aggregate) {
+		t.Fatalf("Wait() = %v, want an AggregateError", err)
+	}
+	indexed := aggregate.Errors()
+	if len(indexed) != 2 || indexed[0].Index != 1 || indexed[0].Err != first || indexed[1].Index != 2 || indexed[1].Err != second {
+		t.Fatalf("errors = %v, want first at 1 and second at 2", indexed)
+	}
+}
+
+func TestTaskGroupSucceeds(t *testing.T) {
+	g := NewTaskGroup()
+	value := Go(g, func(signal *AbortSignal) (int, error) { return 4, nil })
+	if err := g.Wait(); err != nil {
+		t.Fatalf("Wait() = %v", err)
+	}
+	if v, err := value.AwaitWithTimeout(time.Second); err != nil || v != 4 {
+		t.Fatalf("got %v, %v", v, err)
+	}
+	if g.Signal().Aborted() {
+		t.Fatal("a successful group was aborted")
+	}
+}
+
+func TestTaskGroupCapturesPanic(t *testing.T) {
+	g := NewTaskGroup()
+	task := g.Go(explode)
+
+	_, err := task.AwaitWithTimeout(time.Second)
+	var enhanced *types.EnhancedError
+	if !errors.As(err, &enhanced) || enhanced.Code() != types.InternalError || enhanced.Data()["panic"] != "boom" {
+		t.Fatalf("the task rejected with %v, want an InternalError for the panic", err)
+	}
+	found := false
+	for _, frame := range enhanced.Stack() {
+		if strings.HasSuffix(frame.Function, ".explode") {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("the stack does not include the panicking function:\n%v", enhanced.Stack())
+	}
+	if err := g.Wait(); !errors.Is(err, enhanced) {
+		t.Fatalf("Wait() = %v, want the panic error", err)
+	}
+}
+
+func TestNestedTaskGroupCancelledWithParent(t *testing.T) {
+	parent := NewTaskGroup()
+	nested := make(chan error, 1)
+	parent.Go(func(signal *AbortSignal) error {
+		child := NewTaskGroup(signal)
+		child.Go(waitForAbort)
+		err := child.Wait()
+		nested <- err
+		return err
+	})
+
+	reason := errors.New("shutdown")
+	parent.Cancel(reason)
+	if err := <-nested; err != reason {
+		t.Fatalf("the nested group ended with %v, want the parent's reason", err)
+	}
+	if err := parent.Wait(); err != reason {
+		t.Fatalf("Wait() = %v, want the cancellation reason", err)
+	}
+}
+
+func TestTaskGroupCancelledByContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	g := NewTaskGroupWithContext(ctx)
+	task := g.Go(waitForAbort)
+
+	cancel()
+	if _, err := task.AwaitWithTimeout(time.Second); !errors.Is(err, context.Canceled) {
+		t.Fatalf("the task rejected with %v, want context.Canceled", err)
+	}
+	if err := g.Wait(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Wait() = %v, want context.Canceled", err)
+	}
+
+	// Tasks started after the cancellation are rejected without running
+	ran := false
+	late := g.Go(func(signal *AbortSignal) error {
+		ran = true
+		return nil
+	})
+	if _, err := late.AwaitWithTimeout(time.Second); !errors.Is(err, context.Canceled) || ran {
+		t.Fatalf("a late task ran = %v and rejected with %v", ran, err)
+	}
+}
@@ -0,0 +1,270 @@
+package async
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"typescript-golang/types"
+)
+
+// PromiseInfo describes a promise for instrumentation hooks and DumpPending
+type PromiseInfo struct {
+	ID uint64
+	// ParentID is the promise this one was chained from with Then/Catch/Finally, or 0
+	ParentID uint64
+	Label    string
+	State    PromiseState
+	// Err is the rejection reason of a rejected promise
+	Err     error
+	Created time.Time
+	// Duration is the time from creation to settlement, or the time spent pending so far
+	Duration time.Duration
+	// Stack is the creation stack, captured only when Instrumentation.TrackPending is set
+	Stack types.StackTrace
+}
+
+// String returns a one-line description of the promise
+func (pi PromiseInfo) String() string {
+	name := fmt.Sprintf("Promise#%d", pi.ID)
+	if pi.Label != "" {
+		name += fmt.Sprintf(" %q", pi.Label)
+	}
+	if pi.ParentID != 0 {
+		name += fmt.Sprintf(" (from #%d)", pi.ParentID)
+	}
+	return fmt.Sprintf("%s %s for %v", name, pi.State, pi.Duration)
+}
+
+// Instrumentation receives promise lifecycle events. Hooks run synchronously on the goroutine
+// that creates, chains or settles the promise, so they should be fast. Promises created
+// before SetInstrumentation is called are not traced.
+type Instrumentation struct {
+	// OnCreate is called when a promise is created. Labels are usually set afterwards, so
+	// info.Label is empty here.
+	OnCreate func(info PromiseInfo)
+	// OnSettle is called when a promise fulfills or rejects, with its lifetime as Duration
+	OnSettle func(info PromiseInfo)
+	// OnChain is called when Then, ThenPromise, Catch or Finally derives child from parent
+	OnChain func(parent, child PromiseInfo, operation string)
+	// OnUnhandledRejection is called when a rejected promise that was never awaited or
+	// chained is garbage collected (like process.on('unhandledRejection') in Node.js)
+	OnUnhandledRejection func(info PromiseInfo)
+	// TrackPending records pending promises with their creation stacks for DumpPending
+	TrackPending bool
+}
+
+var (
+	instrumentation atomic.Pointer[Instrumentation]
+	lastPromiseID   uint64
+
+	pendingMu sync.Mutex
+	pending   = make(map[uint64]*promiseTrace)
+)
+
+// SetInstrumentation installs promise lifecycle hooks; nil turns instrumentation off
+func SetInstrumentation(instr *Instrumentation) {
+	instrumentation.Store(instr)
+}
+
+// promiseTrace holds the instrumentation state of one promise. It does not reference the
+// promise itself, so its finalizer can detect when the promise has been collected.
+type promiseTrace struct {
+	mu          sync.Mutex
+	info        PromiseInfo
+	handled     bool
+	tracked     bool
+	onUnhandled func(info PromiseInfo)
+}
+
+// startTrace returns the trace for a new promise, or nil when instrumentation is off
+func startTrace(id uint64) *promiseTrace {
+	instr := instrumentation.Load()
+	if instr == nil {
+		return nil
+	}
+
+	t := &promiseTrace{info: PromiseInfo{ID: id, State: Pending, Created: time.Now()}}
+	if instr.TrackPending {
+		t.info.Stack = callerStack()
+		t.tracked = true
+		pendingMu.Lock()
+		pending[id] = t
+		pendingMu.Unlock()
+	}
+	if instr.OnUnhandledRejection != nil {
+		t.onUnhandled = instr.OnUnhandledRejection
+		runtime.SetFinalizer(t, (*promiseTrace).finalize)
+	}
+	if instr.OnCreate != nil {
+		instr.OnCreate(t.snapshot())
+	}
+	return t
+}
+
+// asyncPackagePrefix is the prefix of function names in this package
+var asyncPackagePrefix = reflect.TypeOf(PromiseInfo{}).PkgPath() + "."
+
+// callerStack captures the stack of the code that created a promise, dropping the frames
+// inside this package
+func callerStack() types.StackTrace {
+	stack := types.CaptureStackTrace(1)
+	for i, frame := range stack {
+		if !strings.HasPrefix(frame.Function, asyncPackagePrefix) {
+			return stack[i:]
+		}
+	}
+	return stack
+}
+
+// snapshot returns a copy of the trace's info
+func (t *promiseTrace) snapshot() PromiseInfo {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	info := t.info
+	if info.State == Pending {
+		info.Duration = time.Since(info.Created)
+	}
+	return info
+}
+
+// settled records the outcome of the promise and fires OnSettle
+func (t *promiseTrace) settled(err error) {
+	t.mu.Lock()
+	if err != nil {
+		t.info.State = Rejected
+		t.info.Err = err
+	} else {
+		t.info.State = Fulfilled
+	}
+	t.info.Duration = time.Since(t.info.Created)
+	info := t.info
+	tracked := t.tracked
+	t.mu.Unlock()
+
+	if tracked {
+		pendingMu.Lock()
+		delete(pending, info.ID)
+		pendingMu.Unlock()
+	}
+	if instr := instrumentation.Load(); instr != nil && instr.OnSettle != nil {
+		instr.OnSettle(info)
+	}
+}
+
+// finalize reports a rejection nobody handled once the promise has been collected
+func (t *promiseTrace) finalize() {
+	t.mu.Lock()
+	unhandled := t.info.State == Rejected && !t.handled
+	info := t.info
+	t.mu.Unlock()
+
+	if unhandled {
+		t.onUnhandled(info)
+	}
+}
+
+// nextPromiseID returns a process-wide unique promise ID
+func nextPromiseID() uint64 {
+	return atomic.AddUint64(&lastPromiseID, 1)
+}
+
+// markHandled records that the promise has been awaited or chained
+func (p *Promise[T]) markHandled() {
+	if p.trace == nil {
+		return
+	}
+	p.trace.mu.Lock()
+	p.trace.handled = true
+	p.trace.mu.Unlock()
+}
+
+// chained links child to the promise it was derived from and fires OnChain
+func chained[T, U any](parent *Promise[T], child *Promise[U], operation string) *Promise[U] {
+	if child.trace == nil {
+		return child
+	}
+	child.trace.mu.Lock()
+	child.trace.info.ParentID = parent.id
+	child.trace.mu.Unlock()
+
+	if instr := instrumentation.Load(); instr != nil && instr.OnChain != nil {
+		var parentInfo PromiseInfo
+		if parent.trace != nil {
+			parentInfo = parent.trace.snapshot()
+		} else {
+			parentInfo = PromiseInfo{ID: parent.id, Label: parent.Label(), State: parent.GetState()}
+		}
+		instr.OnChain(parentInfo, child.trace.snapshot(), operation)
+	}
+	return child
+}
+
+// ID returns the promise's process-wide unique ID
+func (p *Promise[T]) ID() uint64 {
+	return p.id
+}
+
+// Label returns the promise's label
+func (p *Promise[T]) Label() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.label
+}
+
+// WithLabel names the promise for instrumentation and DumpPending (fluent interface)
+func (p *Promise[T]) WithLabel(label string) *Promise[T] {
+	p.mu.Lock()
+	p.label = label
+	p.mu.Unlock()
+
+	if p.trace != nil {
+		p.trace.mu.Lock()
+		p.trace.info.Label = label
+		p.trace.mu.Unlock()
+	}
+	return p
+}
+
+// PendingPromises returns the unsettled promises recorded while Instrumentation.TrackPending
+// was set, oldest first
+func PendingPromises() []PromiseInfo {
+	pendingMu.Lock()
+	traces := make([]*promiseTrace, 0, len(pending))
+	for _, t := range pending {
+		traces = append(traces, t)
+	}
+	pendingMu.Unlock()
+
+	infos := make([]PromiseInfo, 0, len(traces))
+	for _, t := range traces {
+		if info := t.snapshot(); info.State == Pending {
+			infos = append(infos, info)
+		}
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ID < infos[j].ID
+	})
+	return infos
+}
+
+// DumpPending returns a report of every pending promise with its creation stack, for
+// diagnosing hangs. It requires Instrumentation.TrackPending.
+func DumpPending() string {
+	infos := PendingPromises()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d pending promise(s)\n", len(infos))
+	for _, info := range infos {
+		fmt.Fprintf(&b, "\n%s\n", info)
+		if len(info.Stack) > 0 {
+			fmt.Fprintf(&b, "%s\n", info.Stack)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,537 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"typescript-golang/types"
+)
+
+// ErrIteratorClosed is returned from a generator's yield once the consumer has called Return
+// or stopped iterating; the generator should clean up and return
+var ErrIteratorClosed = errors.New("async iterator closed")
+
+// ErrBreak can be returned from a ForAwait callback to stop iterating without an error
+// (like break inside for await in TypeScript)
+var ErrBreak = errors.New("break")
+
+// IteratorResult represents TypeScript's IteratorResult<T>
+type IteratorResult[T any] struct {
+	Value T    `json:"value"`
+	Done  bool `json:"done"`
+}
+
+// AsyncIterator represents TypeScript's AsyncIterator<T>
+type AsyncIterator[T any] interface {
+	// Next resolves with the next value, or with Done set once the iterator is exhausted
+	Next(ctx context.Context) *Promise[IteratorResult[T]]
+	// Return stops the iterator early and releases its producer
+	Return() *Promise[IteratorResult[T]]
+}
+
+// asyncIterator adapts plain pull/close functions to the AsyncIterator interface. Pulls run
+// one at a time without holding mu, so Return can cancel a pull in flight through ctx while
+// close runs.
+type asyncIterator[T any] struct {
+	mu      sync.Mutex
+	pulling chan struct{}
+	ctx     context.Context
+	cancel  context.CancelFunc
+	pull    func(ctx context.Context) (IteratorResult[T], error)
+	close   func() error
+	done    bool
+}
+
+func newAsyncIterator[T any](pull func(ctx context.Context) (IteratorResult[T], error), close func() error) *asyncIterator[T] {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &asyncIterator[T]{pulling: make(chan struct{}, 1), ctx: ctx, cancel: cancel, pull: pull, close: close}
+}
+
+// isDone reports whether the iterator is exhausted, failed or returned
+func (it *asyncIterator[T]) isDone() bool {
+	it.mu.Lock()
+	defer it.mu.Unlock()
+	return it.done
+}
+
+func (it *asyncIterator[T]) Next(ctx context.Context) *Promise[IteratorResult[T]] {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return NewPromise(func() (IteratorResult[T], error) {
+		// Calls to Next are served in order, like queued next() calls in TypeScript
+		select {
+		case it.pulling <- struct{}{}:
+		case <-it.ctx.Done():
+			return IteratorResult[T]{Done: true}, nil
+		case <-ctx.Done():
+			return IteratorResult[T]{}, ctx.Err()
+		}
+		defer func() { <-it.pulling }()
+
+		if it.isDone() {
+			return IteratorResult[T]{Done: true}, nil
+		}
+		pullCtx, cancel := mergeContext(ctx, it.ctx)
+		defer cancel()
+		result, err := it.pull(pullCtx)
+
+		it.mu.Lock()
+		defer it.mu.Unlock()
+
+		if it.done {
+			// Return was called while the pull was in flight
+			return IteratorResult[T]{Done: true}, nil
+		}
+		if err != nil || result.Done {
+			// A failed or exhausted iterator stays done; context errors leave it resumable
+			if err == nil || (!errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)) {
+				it.done = true
+			}
+		}
+		return result, err
+	})
+}
+
+func (it *asyncIterator[T]) Return() *Promise[IteratorResult[T]] {
+	return NewPromise(func() (IteratorResult[T], error) {
+		it.mu.Lock()
+		if it.done {
+			it.mu.Unlock()
+			return IteratorResult[T]{Done: true}, nil
+		}
+		it.done = true
+		it.mu.Unlock()
+
+		it.cancel()
+		if it.close != nil {
+			if err := it.close(); err != nil {
+				return IteratorResult[T]{Done: true}, err
+			}
+		}
+		return IteratorResult[T]{Done: true}, nil
+	})
+}
+
+// mergeContext returns a context that is cancelled when ctx or other is done
+func mergeContext(ctx, other context.Context) (context.Context, context.CancelFunc) {
+	merged, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-other.Done():
+			cancel()
+		case <-merged.Done():
+		}
+	}()
+	return merged, cancel
+}
+
+// nextValue awaits the next result of an iterator
+func nextValue[T any](ctx context.Context, it AsyncIterator[T]) (IteratorResult[T], error) {
+	return it.Next(ctx).Await()
+}
+
+// IterateChannel creates an AsyncIterator over a channel; it is done when the channel is closed
+func IterateChannel[T any](ch <-chan T) AsyncIterator[T] {
+	return newAsyncIterator(func(ctx context.Context) (IteratorResult[T], error) {
+		select {
+		case value, ok := <-ch:
+			if !ok {
+				return IteratorResult[T]{Done: true}, nil
+			}
+			return IteratorResult[T]{Value: value}, nil
+		case <-ctx.Done():
+			return IteratorResult[T]{}, ctx.Err()
+		}
+	}, nil)
+}
+
+// DefaultObservableBuffer is the number of values IterateObservable buffers by default
+const DefaultObservableBuffer = 256
+
+// IterateObservable creates an AsyncIterator over the values emitted by an Observable. It
+// subscribes on its own goroutine when first pulled, and buffers up to bufferSize values
+// (DefaultObservableBuffer by default); while the buffer is full, the observable's Next blocks
+// until the consumer catches up. The iterator finishes when the observable completes and fails
+// with its error. Return unsubscribes from the observable.
+func IterateObservable[T any](observable *types.Observable[T], bufferSize ...int) AsyncIterator[T] {
+	size := DefaultObservableBuffer
+	if len(bufferSize) > 0 && bufferSize[0] > 0 {
+		size = bufferSize[0]
+	}
+
+	var (
+		mu        sync.Mutex
+		buffer    []T
+		finished  bool
+		closed    bool
+		finalErr  error
+		startOnce sync.Once
+	)
+	space := sync.NewCond(&mu)
+	notify := make(chan struct{}, 1)
+	subscribed := make(chan *types.Subscription, 1)
+
+	wake := func() {
+		select {
+		case notify <- struct{}{}:
+		default:
+		}
+	}
+	finish := func(err error) {
+		mu.Lock()
+		finished, finalErr = true, err
+		mu.Unlock()
+		wake()
+	}
+	start := func() {
+		startOnce.Do(func() {
+			go func() {
+				subscribed <- observable.SubscribeObserver(types.Observer[T]{
+					Next: func(value T) {
+						mu.Lock()
+						for len(buffer) >= size && !closed {
+							space.Wait()
+						}
+						if !closed {
+							buffer = append(buffer, value)
+						}
+						mu.Unlock()
+						wake()
+					},
+					Error:    finish,
+					Complete: func() { finish(nil) },
+				})
+			}()
+		})
+	}
+
+	return newAsyncIterator(func(ctx context.Context) (IteratorResult[T], error) {
+		start()
+		for {
+			mu.Lock()
+			if len(buffer) > 0 {
+				value := buffer[0]
+				var zero T
+				buffer[0] = zero
+				buffer = buffer[1:]
+				space.Signal()
+				mu.Unlock()
+				return IteratorResult[T]{Value: value}, nil
+			}
+			if finished {
+				mu.Unlock()
+				return IteratorResult[T]{Done: true}, finalErr
+			}
+			mu.Unlock()
+
+			select {
+			case <-notify:
+			case <-ctx.Done():
+				return IteratorResult[T]{}, ctx.Err()
+			}
+		}
+	}, func() error {
+		mu.Lock()
+		closed = true
+		buffer = nil
+		space.Broadcast()
+		mu.Unlock()
+
+		started := true
+		startOnce.Do(func() { started = false })
+		if started {
+			// A blocked Next returns once closed is set, so the subscribe call finishes
+			(<-subscribed).Unsubscribe()
+		}
+		return nil
+	})
+}
+
+// AsyncGenerator is a producer function that yields values to an AsyncIterator
+// (like an async function* in TypeScript). yield blocks until the consumer asks for
+// the value and returns ErrIteratorClosed once the consumer has stopped.
+type AsyncGenerator[T any] func(yield func(value T) error) error
+
+// NewAsyncGenerator creates a pull-based AsyncIterator from a generator function.
+// The generator does not start until the first call to Next and only runs ahead of
+// the consumer by the value it is currently yielding.
+func NewAsyncGenerator[T any](generator AsyncGenerator[T]) AsyncIterator[T] {
+	requests := make(chan struct{})
+	values := make(chan T)
+	finished := make(chan struct{})
+	stop := make(chan struct{})
+	var genErr error
+	var startOnce, stopOnce sync.Once
+
+	start := func() {
+		startOnce.Do(func() {
+			go func() {
+				defer close(finished)
+				defer func() {
+					if r := recover(); r != nil {
+						genErr = types.NewError("async generator panicked").WithData("panic", r)
+					}
+				}()
+				genErr = generator(func(value T) error {
+					select {
+					case <-requests:
+					case <-stop:
+						return ErrIteratorClosed
+					}
+					select {
+					case values <- value:
+						return nil
+					case <-stop:
+						return ErrIteratorClosed
+					}
+				})
+			}()
+		})
+	}
+
+	finish := func() (IteratorResult[T], error) {
+		if genErr != nil && !errors.Is(genErr, ErrIteratorClosed) {
+			return IteratorResult[T]{Done: true}, genErr
+		}
+		return IteratorResult[T]{Done: true}, nil
+	}
+
+	// requested is set while a value was asked for but not received; pulls never overlap
+	requested := false
+	return newAsyncIterator(func(ctx context.Context) (IteratorResult[T], error) {
+		start()
+		if !requested {
+			select {
+			case requests <- struct{}{}:
+				requested = true
+			case <-finished:
+				return finish()
+			case <-ctx.Done():
+				return IteratorResult[T]{}, ctx.Err()
+			}
+		}
+		select {
+		case value := <-values:
+			requested = false
+			return IteratorResult[T]{Value: value}, nil
+		case <-finished:
+			return finish()
+		case <-ctx.Done():
+			// The generator keeps the value it is yielding for the next call
+			return IteratorResult[T]{}, ctx.Err()
+		}
+	}, func() error {
+		stopOnce.Do(func() { close(stop) })
+		// Return may run during the first pull, so it claims the start to find out whether the
+		// generator runs; a pull that starts afterwards finds it finished
+		started := true
+		startOnce.Do(func() {
+			started = false
+			close(finished)
+		})
+		if !started {
+			return nil
+		}
+		// Wait for the generator to run its cleanup, like return() resuming finally blocks
+		<-finished
+		if genErr != nil && !errors.Is(genErr, ErrIteratorClosed) {
+			return genErr
+		}
+		return nil
+	})
+}
+
+// ForAwait calls fn for every value of the iterator (like for await...of in TypeScript).
+// Iteration stops when the iterator is done, ctx is cancelled or fn returns an error;
+// on early exit the iterator's Return is called so the producer is cleaned up.
+// Returning ErrBreak from fn stops iteration without an error.
+func ForAwait[T any](ctx context.Context, it AsyncIterator[T], fn func(value T) error) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	for {
+		result, err := nextValue(ctx, it)
+		if err != nil {
+			it.Return().Await()
+			return err
+		}
+		if result.Done {
+			return nil
+		}
+		if err := fn(result.Value); err != nil {
+			it.Return().Await()
+			if errors.Is(err, ErrBreak) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// CollectAsync drains an iterator into a slice (like Array.fromAsync() in TypeScript)
+func CollectAsync[T any](ctx context.Context, it AsyncIterator[T]) ([]T, error) {
+	var values []T
+	err := ForAwait(ctx, it, func(value T) error {
+		values = append(values, value)
+		return nil
+	})
+	return values, err
+}
+
+// MapAsync transforms each value of an iterator
+func MapAsync[T, U any](it AsyncIterator[T], fn func(value T) (U, error)) AsyncIterator[U] {
+	return newAsyncIterator(func(ctx context.Context) (IteratorResult[U], error) {
+		result, err := nextValue(ctx, it)
+		if err != nil || result.Done {
+			return IteratorResult[U]{Done: result.Done}, err
+		}
+		mapped, err := fn(result.Value)
+		if err != nil {
+			it.Return().Await()
+			return IteratorResult[U]{Done: true}, err
+		}
+		return IteratorResult[U]{Value: mapped}, nil
+	}, func() error {
+		_, err := it.Return().Await()
+		return err
+	})
+}
+
+// FilterAsync yields only the values that pass the predicate
+func FilterAsync[T any](it AsyncIterator[T], predicate func(value T) bool) AsyncIterator[T] {
+	return newAsyncIterator(func(ctx context.Context) (IteratorResult[T], error) {
+		for {
+			result, err := nextValue(ctx, it)
+			if err != nil || result.Done {
+				return result, err
+			}
+			if predicate(result.Value) {
+				return result, nil
+			}
+		}
+	}, func() error {
+		_, err := it.Return().Await()
+		return err
+	})
+}
+
+// TakeAsync yields at most n values, then returns the source iterator
+func TakeAsync[T any](it AsyncIterator[T], n int) AsyncIterator[T] {
+	taken := 0
+	return newAsyncIterator(func(ctx context.Context) (IteratorResult[T], error) {
+		if taken >= n {
+			_, err := it.Return().Await()
+			return IteratorResult[T]{Done: true}, err
+		}
+		result, err := nextValue(ctx, it)
+		if err != nil || result.Done {
+			return result, err
+		}
+		taken++
+		return result, nil
+	}, func() error {
+		_, err := it.Return().Await()
+		return err
+	})
+}
+
+// BufferAsync groups values into slices of up to size elements; the final slice may be shorter
+func BufferAsync[T any](it AsyncIterator[T], size int) AsyncIterator[[]T] {
+	if size < 1 {
+		size = 1
+	}
+	return newAsyncIterator(func(ctx context.Context) (IteratorResult[[]T], error) {
+		batch := make([]T, 0, size)
+		for len(batch) < size {
+			result, err := nextValue(ctx, it)
+			if err != nil {
+				return IteratorResult[[]T]{}, err
+			}
+			if result.Done {
+				break
+			}
+			batch = append(batch, result.Value)
+		}
+		if len(batch) == 0 {
+			return IteratorResult[[]T]{Done: true}, nil
+		}
+		return IteratorResult[[]T]{Value: batch}, nil
+	}, func() error {
+		_, err := it.Return().Await()
+		return err
+	})
+}
+
+// MergeAsync interleaves values from several iterators in the order they arrive.
+// It is done when every source is done; the first source error is reported and
+// all remaining sources are returned.
+func MergeAsync[T any](iterators ...AsyncIterator[T]) AsyncIterator[T] {
+	type item struct {
+		value T
+		err   error
+	}
+	items := make(chan item)
+	ctx, cancel := context.WithCancel(context.Background())
+	var startOnce sync.Once
+	var wg sync.WaitGroup
+
+	start := func() {
+		startOnce.Do(func() {
+			for _, source := range iterators {
+				wg.Add(1)
+				go func(source AsyncIterator[T]) {
+					defer wg.Done()
+					for {
+						result, err := nextValue(ctx, source)
+						if err == nil && result.Done {
+							return
+						}
+						select {
+						case items <- item{value: result.Value, err: err}:
+						case <-ctx.Done():
+							return
+						}
+						if err != nil {
+							return
+						}
+					}
+				}(source)
+			}
+			go func() {
+				wg.Wait()
+				close(items)
+			}()
+		})
+	}
+
+	closeAll := func() error {
+		cancel()
+		var firstErr error
+		for _, source := range iterators {
+			if _, err := source.Return().Await(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		return firstErr
+	}
+
+	return newAsyncIterator(func(callerCtx context.Context) (IteratorResult[T], error) {
+		start()
+		select {
+		case it, ok := <-items:
+			if !ok {
+				cancel()
+				return IteratorResult[T]{Done: true}, nil
+			}
+			if it.err != nil {
+				closeAll()
+				return IteratorResult[T]{Done: true}, it.err
+			}
+			return IteratorResult[T]{Value: it.value}, nil
+		case <-callerCtx.Done():
+			return IteratorResult[T]{}, callerCtx.Err()
+		}
+	}, closeAll)
+}
@@ -0,0 +1,145 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"typescript-golang/types"
+)
+
+func TestReturnCancelsPendingNext(t *testing.T) {
+	it := IterateChannel(make(chan int))
+	next := it.Next(context.Background())
+	time.Sleep(10 * time.Millisecond)
+
+	if _, err := it.Return().AwaitWithTimeout(time.Second); err != nil {
+		t.Fatalf("Return blocked behind Next: %v", err)
+	}
+	result, err := next.AwaitWithTimeout(time.Second)
+	if err != nil || !result.Done {
+		t.Fatalf("pending Next: %+v, %v", result, err)
+	}
+}
+
+func TestGeneratorNextHonorsContext(t *testing.T) {
+	release := make(chan struct{})
+	cleaned := make(chan struct{})
+	it := NewAsyncGenerator(func(yield func(int) error) error {
+		defer close(cleaned)
+		for i := 0; ; i++ {
+			if i == 1 {
+				<-release
+			}
+			if err := yield(i); err != nil {
+				return err
+			}
+		}
+	})
+
+	if result, err := it.Next(context.Background()).Await(); err != nil || result.Value != 0 {
+		t.Fatalf("first value: %+v, %v", result, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := it.Next(ctx).AwaitWithTimeout(time.Second); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the context error, got %v", err)
+	}
+
+	// The value yielded after the cancelled call is delivered to the next one
+	close(release)
+	if result, err := it.Next(context.Background()).AwaitWithTimeout(time.Second); err != nil || result.Value != 1 {
+		t.Fatalf("value after cancel: %+v, %v", result, err)
+	}
+	if _, err := it.Return().AwaitWithTimeout(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-cleaned:
+	case <-time.After(time.Second):
+		t.Fatal("generator not released")
+	}
+}
+
+func TestGeneratorReturnDuringFirstNext(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		var started, cleaned atomic.Int32
+		it := NewAsyncGenerator(func(yield func(int) error) error {
+			started.Add(1)
+			defer cleaned.Add(1)
+			for v := 0; ; v++ {
+				if err := yield(v); err != nil {
+					return err
+				}
+			}
+		})
+
+		next := it.Next(context.Background())
+		if _, err := it.Return().AwaitWithTimeout(time.Second); err != nil {
+			t.Fatal(err)
+		}
+		// Return waits for a generator that started to run its cleanup
+		if started.Load() != cleaned.Load() {
+			t.Fatal("Return resolved before the generator finished")
+		}
+		if _, err := next.AwaitWithTimeout(time.Second); err != nil {
+			t.Fatalf("first Next: %v", err)
+		}
+	}
+}
+
+func TestIterateObservableAppliesBackpressure(t *testing.T) {
+	produced := make(chan int, 100)
+	source := types.NewObservableFrom(func(s types.Subscriber[int]) types.Teardown {
+		for i := 0; i < 10; i++ {
+			s.Next(i)
+			produced <- i
+		}
+		s.Complete()
+		return nil
+	})
+
+	it := IterateObservable(source, 2)
+	first, err := it.Next(context.Background()).AwaitWithTimeout(time.Second)
+	if err != nil || first.Value != 0 {
+		t.Fatalf("first value: %+v, %v", first, err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if n := len(produced); n > 3 {
+		t.Fatalf("source ran %d values ahead of a buffer of 2", n)
+	}
+
+	values, err := CollectAsync(context.Background(), it)
+	if err != nil || len(values) != 9 || values[8] != 9 {
+		t.Fatalf("remaining values: %v, %v", values, err)
+	}
+}
+
+func TestIterateObservableReturnReleasesSource(t *testing.T) {
+	subject := types.NewSubject[int]()
+	it := IterateObservable(subject.AsObservable(), 1)
+	next := it.Next(context.Background())
+	time.Sleep(10 * time.Millisecond)
+
+	blocked := make(chan struct{})
+	go func() {
+		subject.Next(1)
+		subject.Next(2)
+		subject.Next(3)
+		close(blocked)
+	}()
+	if result, err := next.AwaitWithTimeout(time.Second); err != nil || result.Value != 1 {
+		t.Fatalf("first value: %+v, %v", result, err)
+	}
+	if _, err := it.Return().AwaitWithTimeout(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-blocked:
+	case <-time.After(time.Second):
+		t.Fatal("Return left the source blocked on a full buffer")
+	}
+}
@@ -5,17 +5,37 @@ import (
 	"fmt"
 	"sync"
 	"time"
+
+	"typescript-golang/timers"
+	"typescript-golang/types"
 )
 
 // Promise represents TypeScript's Promise<T>
 type Promise[T any] struct {
-	result chan T
-	err    chan error
-	done   chan bool
-	mu     sync.RWMutex
-	state  PromiseState
-	value  T
-	error  error
+	// done is closed when the promise settles, so any number of waiters can observe it
+	done  chan struct{}
+	mu    sync.RWMutex
+	state PromiseState
+	value T
+	error error
+
+	// controller aborts the executor of promises created with NewPromiseWithSignal
+	controller *AbortController
+
+	// lazy defers start until the promise is first awaited or chained
+	lazy  *sync.Once
+	start func()
+
+	// callbacks run once when the promise settles
+	callbacks []func()
+
+	// loop runs Then/Catch/Finally reactions as microtasks when set
+	loop *EventLoop
+
+	// id, label and trace support instrumentation
+	id    uint64
+	label string
+	trace *promiseTrace
 }
 
 // PromiseState represents the state of a Promise
@@ -43,97 +63,221 @@ func (s PromiseState) String() string {
 // Executor function type for Promise constructor
 type Executor[T any] func() (T, error)
 
+// SignalExecutor is an executor that receives an AbortSignal for cooperative cancellation
+type SignalExecutor[T any] func(signal *AbortSignal) (T, error)
+
+// newPromise creates a pending Promise with no executor attached
+func newPromise[T any]() *Promise[T] {
+	id := nextPromiseID()
+	return &Promise[T]{
+		done:  make(chan struct{}),
+		state: Pending,
+		id:    id,
+		trace: startTrace(id),
+	}
+}
+
+// settle transitions a pending promise to its final state. Only the first call has any effect.
+func (p *Promise[T]) settle(value T, err error) bool {
+	p.mu.Lock()
+	if p.state != Pending {
+		p.mu.Unlock()
+		return false
+	}
+	if err != nil {
+		p.state = Rejected
+		p.error = err
+	} else {
+		p.state = Fulfilled
+		p.value = value
+	}
+	callbacks := p.callbacks
+	p.callbacks = nil
+	p.mu.Unlock()
+
+	if p.trace != nil {
+		p.trace.settled(err)
+	}
+
+	close(p.done)
+
+	for _, callback := range callbacks {
+		callback()
+	}
+	return true
+}
+
+// onSettle registers a callback that runs once the promise settles, or immediately if it already has
+func (p *Promise[T]) onSettle(callback func()) {
+	p.mu.Lock()
+	if p.state == Pending {
+		p.callbacks = append(p.callbacks, callback)
+		p.mu.Unlock()
+		return
+	}
+	p.mu.Unlock()
+	callback()
+}
+
+// execute runs the executor on the calling goroutine, converting panics into rejections
+func (p *Promise[T]) execute(executor Executor[T]) {
+	defer func() {
+		if r := recover(); r != nil {
+			var zero T
+			p.settle(zero, fmt.Errorf("panic: %v", r))
+		}
+	}()
+
+	result, err := executor()
+	p.settle(result, err)
+}
+
+// run executes the executor on a new goroutine
+func (p *Promise[T]) run(executor Executor[T]) {
+	go p.execute(executor)
+}
+
 // NewPromise creates a new Promise (like new Promise() in TypeScript)
 func NewPromise[T any](executor Executor[T]) *Promise[T] {
-	p := &Promise[T]{
-		result: make(chan T, 1),
-		err:    make(chan error, 1),
-		done:   make(chan bool, 1),
-		state:  Pending,
+	p := newPromise[T]()
+	p.run(executor)
+	return p
+}
+
+// newSignalPromise creates a pending cancellable promise linked to the parent signals.
+// Aborting its signal rejects it immediately; release detaches it from the parents.
+func newSignalPromise[T any](parents []*AbortSignal) (p *Promise[T], release func()) {
+	p = newPromise[T]()
+	p.controller = NewAbortController()
+
+	var detach []func()
+	for _, parent := range parents {
+		if parent == nil {
+			continue
+		}
+		detach = append(detach, parent.OnAbort(func(reason error) {
+			p.controller.Abort(reason)
+		}))
 	}
 
-	go func() {
-		defer func() {
-			if r := recover(); r != nil {
-				p.mu.Lock()
-				p.state = Rejected
-				p.error = fmt.Errorf("panic: %v", r)
-				p.mu.Unlock()
-				p.err <- p.error
-				p.done <- true
-			}
-		}()
+	p.controller.Signal().OnAbort(func(reason error) {
+		var zero T
+		p.settle(zero, reason)
+	})
 
-		result, err := executor()
-		p.mu.Lock()
-		if err != nil {
-			p.state = Rejected
-			p.error = err
-			p.mu.Unlock()
-			p.err <- err
-		} else {
-			p.state = Fulfilled
-			p.value = result
-			p.mu.Unlock()
-			p.result <- result
+	return p, func() {
+		// Release parent listeners so long-lived parents don't accumulate settled promises
+		for _, remove := range detach {
+			remove()
 		}
-		p.done <- true
-	}()
+	}
+}
 
+// NewPromiseWithSignal creates a cancellable Promise whose executor receives an AbortSignal.
+// The signal is aborted when Abort is called on the promise, when any of the optional
+// parent signals abort, or when the promise loses a Race/Any/Timeout. An aborted promise
+// rejects immediately with the abort reason; the executor is expected to observe the
+// signal and return early.
+func NewPromiseWithSignal[T any](executor SignalExecutor[T], parents ...*AbortSignal) *Promise[T] {
+	p, release := newSignalPromise[T](parents)
+	signal := p.controller.Signal()
+	p.run(func() (T, error) {
+		defer release()
+		return executor(signal)
+	})
 	return p
 }
 
+// Abort cancels a promise created with NewPromiseWithSignal, rejecting it with the reason
+// (a CancelledError by default). It returns false if the promise is not cancellable or
+// has already settled.
+func (p *Promise[T]) Abort(reason ...error) bool {
+	if p.controller == nil || !p.IsPending() {
+		return false
+	}
+	p.controller.Abort(reason...)
+	return true
+}
+
 // Resolve creates a resolved Promise (like Promise.resolve() in TypeScript)
 func Resolve[T any](value T) *Promise[T] {
-	p := &Promise[T]{
-		result: make(chan T, 1),
-		err:    make(chan error, 1),
-		done:   make(chan bool, 1),
-		state:  Fulfilled,
-		value:  value,
-	}
-	p.result <- value
-	p.done <- true
+	p := newPromise[T]()
+	p.settle(value, nil)
 	return p
 }
 
 // Reject creates a rejected Promise (like Promise.reject() in TypeScript)
 func Reject[T any](err error) *Promise[T] {
-	p := &Promise[T]{
-		result: make(chan T, 1),
-		err:    make(chan error, 1),
-		done:   make(chan bool, 1),
-		state:  Rejected,
-		error:  err,
-	}
-	p.err <- err
-	p.done <- true
+	p := newPromise[T]()
+	var zero T
+	p.settle(zero, err)
 	return p
 }
 
 // Then chains promises (like .then() in TypeScript)
 func Then[T, U any](p *Promise[T], onFulfilled func(T) U, onRejected func(error) U) *Promise[U] {
-	return NewPromise[U](func() (U, error) {
-		select {
-		case result := <-p.result:
+	p.trigger()
+	if p.loop != nil {
+		return chained(p, react(p, func(value T, err error) (U, error) {
+			var zero U
+			if err != nil {
+				if onRejected != nil {
+					return onRejected(err), nil
+				}
+				return zero, err
+			}
 			if onFulfilled != nil {
-				return onFulfilled(result), nil
+				return onFulfilled(value), nil
 			}
-			var zero U
 			return zero, nil
-		case err := <-p.err:
+		}), "then")
+	}
+	child := chained(p, newPromise[U](), "then")
+	child.run(func() (U, error) {
+		result, err := p.Await()
+		if err != nil {
 			if onRejected != nil {
 				return onRejected(err), nil
 			}
 			var zero U
 			return zero, err
 		}
+		if onFulfilled != nil {
+			return onFulfilled(result), nil
+		}
+		var zero U
+		return zero, nil
 	})
+	return child
 }
 
 // ThenPromise chains promises that return promises (like .then() returning Promise)
 func ThenPromise[T, U any](p *Promise[T], onFulfilled func(T) *Promise[U]) *Promise[U] {
-	return NewPromise[U](func() (U, error) {
+	p.trigger()
+	if p.loop != nil {
+		child := chained(p, newPromise[U](), "then")
+		child.loop = p.loop
+		p.onSettle(func() {
+			p.loop.QueueMicrotask(func() {
+				var zero U
+				defer func() {
+					if r := recover(); r != nil {
+						child.settle(zero, fmt.Errorf("panic: %v", r))
+					}
+				}()
+				
+				result, err := p.settledResult()
+				if err != nil || onFulfilled == nil {
+					child.settle(zero, err)
+					return
+				}
+				adopt(child, onFulfilled(result))
+			})
+		})
+		return child
+	}
+	child := chained(p, newPromise[U](), "then")
+	child.run(func() (U, error) {
 		result, err := p.Await()
 		if err != nil {
 			var zero U
@@ -146,27 +290,44 @@ func ThenPromise[T, U any](p *Promise[T], onFulfilled func(T) *Promise[U]) *Prom
 		var zero U
 		return zero, nil
 	})
+	return child
 }
 
 // Catch handles promise rejection (like .catch() in TypeScript)
 func Catch[T any](p *Promise[T], onRejected func(error) T) *Promise[T] {
-	return NewPromise[T](func() (T, error) {
-		select {
-		case result := <-p.result:
-			return result, nil
-		case err := <-p.err:
-			if onRejected != nil {
+	p.trigger()
+	if p.loop != nil {
+		return chained(p, react(p, func(value T, err error) (T, error) {
+			if err != nil && onRejected != nil {
 				return onRejected(err), nil
 			}
-			var zero T
-			return zero, err
+			return value, err
+		}), "catch")
+	}
+	child := chained(p, newPromise[T](), "catch")
+	child.run(func() (T, error) {
+		result, err := p.Await()
+		if err != nil && onRejected != nil {
+			return onRejected(err), nil
 		}
+		return result, err
 	})
+	return child
 }
 
 // Finally executes code regardless of promise outcome (like .finally() in TypeScript)
 func Finally[T any](p *Promise[T], onFinally func()) *Promise[T] {
-	return NewPromise[T](func() (T, error) {
+	p.trigger()
+	if p.loop != nil {
+		return chained(p, react(p, func(value T, err error) (T, error) {
+			if onFinally != nil {
+				onFinally()
+			}
+			return value, err
+		}), "finally")
+	}
+	child := chained(p, newPromise[T](), "finally")
+	child.run(func() (T, error) {
 		defer func() {
 			if onFinally != nil {
 				onFinally()
@@ -174,26 +335,23 @@ func Finally[T any](p *Promise[T], onFinally func()) *Promise[T] {
 		}()
 		return p.Await()
 	})
+	return child
 }
 
-// Await waits for the promise to resolve (like await in TypeScript)
+// Await waits for the promise to resolve (like await in TypeScript).
+// It may be called any number of times, from any number of goroutines.
 func (p *Promise[T]) Await() (T, error) {
+	p.trigger()
 	<-p.done
-	p.mu.RLock()
-	defer p.mu.RUnlock()
-	
-	if p.state == Fulfilled {
-		return p.value, nil
-	}
-	var zero T
-	return zero, p.error
+	return p.settledResult()
 }
 
 // AwaitWithTimeout waits for promise with timeout
 func (p *Promise[T]) AwaitWithTimeout(timeout time.Duration) (T, error) {
+	p.trigger()
 	select {
 	case <-p.done:
-		return p.Await()
+		return p.settledResult()
 	case <-time.After(timeout):
 		var zero T
 		return zero, fmt.Errorf("promise timeout after %v", timeout)
@@ -202,9 +360,10 @@ func (p *Promise[T]) AwaitWithTimeout(timeout time.Duration) (T, error) {
 
 // AwaitWithContext waits for promise with context cancellation
 func (p *Promise[T]) AwaitWithContext(ctx context.Context) (T, error) {
+	p.trigger()
 	select {
 	case <-p.done:
-		return p.Await()
+		return p.settledResult()
 	case <-ctx.Done():
 		var zero T
 		return zero, ctx.Err()
@@ -296,6 +455,56 @@ func AllSettled[T any](promises ...*Promise[T]) *Promise[[]PromiseResult[T]] {
 	})
 }
 
+// AllWithErrors waits for all promises to settle like All, but instead of the first error
+// it rejects with a *types.AggregateError holding every rejection at its promise's index
+func AllWithErrors[T any](promises ...*Promise[T]) *Promise[[]T] {
+	return NewPromise[[]T](func() ([]T, error) {
+		settled, _ := AllSettledTyped(promises...).Await()
+		
+		results := make([]T, len(settled))
+		errors := make([]error, len(settled))
+		failed := false
+		for i, result := range settled {
+			if result.IsOk() {
+				results[i] = result.Unwrap()
+			} else {
+				errors[i] = result.UnwrapErr()
+				failed = true
+			}
+		}
+		
+		if failed {
+			return nil, types.NewAggregateError("one or more promises were rejected", errors)
+		}
+		return results, nil
+	})
+}
+
+// AllSettledTyped waits for all promises to settle like AllSettled, returning a
+// types.Result per promise instead of status strings. It never rejects.
+func AllSettledTyped[T any](promises ...*Promise[T]) *Promise[[]types.Result[T, error]] {
+	return NewPromise[[]types.Result[T, error]](func() ([]types.Result[T, error], error) {
+		results := make([]types.Result[T, error], len(promises))
+		var wg sync.WaitGroup
+		
+		for i, promise := range promises {
+			wg.Add(1)
+			go func(index int, p *Promise[T]) {
+				defer wg.Done()
+				result, err := p.Await()
+				if err != nil {
+					results[index] = types.Err[T, error](err)
+				} else {
+					results[index] = types.Ok[T, error](result)
+				}
+			}(i, promise)
+		}
+		
+		wg.Wait()
+		return results, nil
+	})
+}
+
 // PromiseResult represents the result of a settled promise
 type PromiseResult[T any] struct {
 	Status string `json:"status"` // "fulfilled" or "rejected"
@@ -303,97 +512,143 @@ type PromiseResult[T any] struct {
 	Reason error  `json:"reason,omitempty"`
 }
 
-// Race returns the first promise to settle (like Promise.race() in TypeScript)
+// abortLosers aborts every cancellable promise except the winner once a combinator has settled
+func abortLosers[T any](promises []*Promise[T], winner *Promise[T], reason error) {
+	for _, p := range promises {
+		if p != winner {
+			p.Abort(reason)
+		}
+	}
+}
+
+// Race returns the first promise to settle (like Promise.race() in TypeScript).
+// Losing promises created with NewPromiseWithSignal are aborted.
 func Race[T any](promises ...*Promise[T]) *Promise[T] {
-	return NewPromise[T](func() (T, error) {
-		result := make(chan T, 1)
-		err := make(chan error, 1)
+	return NewPromiseWithSignal[T](func(signal *AbortSignal) (T, error) {
+		type settled struct {
+			promise *Promise[T]
+			value   T
+			err     error
+		}
+		first := make(chan settled, 1)
 		
 		for _, promise := range promises {
 			go func(p *Promise[T]) {
 				res, e := p.Await()
-				if e != nil {
-					select {
-					case err <- e:
-					default:
-					}
-				} else {
-					select {
-					case result <- res:
-					default:
-					}
+				select {
+				case first <- settled{promise: p, value: res, err: e}:
+				default:
 				}
 			}(promise)
 		}
 		
 		select {
-		case res := <-result:
-			return res, nil
-		case e := <-err:
+		case s := <-first:
+			abortLosers(promises, s.promise, types.NewErrorWithCause("promise lost the race", s.err, types.CancelledError))
+			return s.value, s.err
+		case <-signal.Done():
+			abortLosers(promises, nil, signal.Reason())
 			var zero T
-			return zero, e
+			return zero, signal.Reason()
 		}
 	})
 }
 
-// Any returns the first fulfilled promise (like Promise.any() in TypeScript)
+// Any returns the first fulfilled promise (like Promise.any() in TypeScript).
+// Remaining promises created with NewPromiseWithSignal are aborted once one fulfills.
+// If every promise rejects, it rejects with a *types.AggregateError holding each rejection
+// at its promise's index.
 func Any[T any](promises ...*Promise[T]) *Promise[T] {
-	return NewPromise[T](func() (T, error) {
-		result := make(chan T, 1)
-		errors := make([]error, 0, len(promises))
-		var mu sync.Mutex
+	return NewPromiseWithSignal[T](func(signal *AbortSignal) (T, error) {
+		type fulfilled struct {
+			promise *Promise[T]
+			value   T
+		}
+		result := make(chan fulfilled, 1)
+		errors := make([]error, len(promises))
 		var wg sync.WaitGroup
 		
-		for _, promise := range promises {
+		for i, promise := range promises {
 			wg.Add(1)
-			go func(p *Promise[T]) {
+			go func(index int, p *Promise[T]) {
 				defer wg.Done()
 				res, err := p.Await()
 				if err != nil {
-					mu.Lock()
-					errors = append(errors, err)
-					mu.Unlock()
+					errors[index] = err
 				} else {
 					select {
-					case result <- res:
+					case result <- fulfilled{promise: p, value: res}:
 					default:
 					}
 				}
-			}(promise)
+			}(i, promise)
 		}
 		
+		allDone := make(chan struct{})
 		go func() {
 			wg.Wait()
-			close(result)
+			close(allDone)
 		}()
 		
-		if res, ok := <-result; ok {
-			return res, nil
-		}
-		
 		var zero T
-		return zero, fmt.Errorf("all promises rejected: %v", errors)
+		select {
+		case f := <-result:
+			abortLosers(promises, f.promise, types.NewError("another promise fulfilled first", types.CancelledError))
+			return f.value, nil
+		case <-allDone:
+			// A fulfillment may have raced with the last rejection
+			select {
+			case f := <-result:
+				return f.value, nil
+			default:
+			}
+			return zero, types.NewAggregateError("all promises were rejected", errors)
+		case <-signal.Done():
+			abortLosers(promises, nil, signal.Reason())
+			return zero, signal.Reason()
+		}
 	})
 }
 
-// Sleep creates a promise that resolves after a duration (like setTimeout in TypeScript)
-func Sleep[T any](duration time.Duration, value T) *Promise[T] {
-	return NewPromise[T](func() (T, error) {
-		time.Sleep(duration)
-		return value, nil
+// Sleep creates a promise that resolves after a duration (like setTimeout in TypeScript).
+// Aborting the returned promise stops the underlying timer. An optional clock replaces
+// the default one, e.g. a timers.FakeClock in tests.
+func Sleep[T any](duration time.Duration, value T, clock ...timers.Clock) *Promise[T] {
+	c := timers.Resolve(clock...)
+	return NewPromiseWithSignal[T](func(signal *AbortSignal) (T, error) {
+		timer := c.NewTimer(duration)
+		defer timer.Stop()
+		
+		select {
+		case <-timer.C():
+			return value, nil
+		case <-signal.Done():
+			var zero T
+			return zero, signal.Reason()
+		}
 	})
 }
 
 // Delay creates a promise that resolves with void after a duration
-func Delay(duration time.Duration) *Promise[interface{}] {
-	return Sleep[interface{}](duration, nil)
+func Delay(duration time.Duration, clock ...timers.Clock) *Promise[interface{}] {
+	return Sleep[interface{}](duration, nil, clock...)
 }
 
-// Timeout wraps a promise with a timeout
-func Timeout[T any](promise *Promise[T], duration time.Duration) *Promise[T] {
-	return Race(promise, NewPromise[T](func() (T, error) {
-		time.Sleep(duration)
+// Timeout wraps a promise with a timeout. If the timeout wins, a promise created with
+// NewPromiseWithSignal is aborted with a CancelledError caused by the TimeoutError, and the
+// returned promise rejects with the TimeoutError; if the promise wins, the timer is stopped.
+func Timeout[T any](promise *Promise[T], duration time.Duration, clock ...timers.Clock) *Promise[T] {
+	c := timers.Resolve(clock...)
+	return Race(promise, NewPromiseWithSignal[T](func(signal *AbortSignal) (T, error) {
+		timer := c.NewTimer(duration)
+		defer timer.Stop()
+		
 		var zero T
-		return zero, fmt.Errorf("operation timed out after %v", duration)
+		select {
+		case <-timer.C():
+			return zero, types.NewTimeoutError(fmt.Sprintf("operation timed out after %v", duration))
+		case <-signal.Done():
+			return zero, signal.Reason()
+		}
 	}))
-}
\ No newline at end of file
+}
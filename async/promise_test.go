@@ -0,0 +1,122 @@
+package async
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"typescript-golang/timers"
+	"typescript-golang/types"
+)
+
+// blockingPromise returns a promise whose executor waits for its signal, and a channel that
+// receives whether the signal was aborted when the executor returns
+func blockingPromise() (*Promise[int], <-chan bool) {
+	aborted := make(chan bool, 1)
+	p := NewPromiseWithSignal[int](func(signal *AbortSignal) (int, error) {
+		select {
+		case <-signal.Done():
+		case <-time.After(time.Second):
+		}
+		aborted <- signal.Aborted()
+		return 0, signal.Reason()
+	})
+	return p, aborted
+}
+
+// expectAborted fails unless the losing executor saw its signal aborted
+func expectAborted(t *testing.T, aborted <-chan bool) {
+	t.Helper()
+	if !<-aborted {
+		t.Fatal("the losing executor was not aborted")
+	}
+}
+
+func TestRaceAbortsLosers(t *testing.T) {
+	loser, aborted := blockingPromise()
+	v, err := Race(Resolve(1), loser).AwaitWithTimeout(time.Second)
+	if err != nil || v != 1 {
+		t.Fatalf("got %v, %v", v, err)
+	}
+	expectAborted(t, aborted)
+	if _, err := loser.AwaitWithTimeout(time.Second); !types.IsErrorCode(err, types.CancelledError) {
+		t.Fatalf("loser rejected with %v, want a CancelledError", err)
+	}
+}
+
+func TestAnyAbortsRemainingPromises(t *testing.T) {
+	loser, aborted := blockingPromise()
+	v, err := Any(Reject[int](errors.New("first")), Resolve(2), loser).AwaitWithTimeout(time.Second)
+	if err != nil || v != 2 {
+		t.Fatalf("got %v, %v", v, err)
+	}
+	expectAborted(t, aborted)
+}
+
+func TestAnyRejectsWithEveryError(t *testing.T) {
+	_, err := Any(Reject[int](errors.New("a")), Reject[int](errors.New("b"))).AwaitWithTimeout(time.Second)
+	var aggregate *types.AggregateError
+	if !errors.As(err, &aggregate) || len(aggregate.Errors()) != 2 {
+		t.Fatalf("expected an AggregateError of 2 errors, got %v", err)
+	}
+}
+
+func TestTimeoutAbortsSlowPromise(t *testing.T) {
+	clock := timers.NewFakeClock()
+	slow, aborted := blockingPromise()
+	result := Timeout(slow, time.Minute, clock)
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+	if _, err := result.AwaitWithTimeout(time.Second); !types.IsErrorCode(err, types.TimeoutError) {
+		t.Fatalf("expected a TimeoutError, got %v", err)
+	}
+	expectAborted(t, aborted)
+}
+
+func TestTimeoutStopsTimerWhenPromiseWins(t *testing.T) {
+	clock := timers.NewFakeClock()
+	v, err := Timeout(Resolve(3), time.Minute, clock).AwaitWithTimeout(time.Second)
+	if err != nil || v != 3 {
+		t.Fatalf("got %v, %v", v, err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for clock.TimerCount() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("the timeout timer was not stopped")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestAnySignal(t *testing.T) {
+	first, second := NewAbortController(), NewAbortController()
+	combined := AnySignal(first.Signal(), second.Signal())
+	if combined.Aborted() {
+		t.Fatal("aborted before any source")
+	}
+	reason := errors.New("second")
+	second.Abort(reason)
+	if !combined.Aborted() || combined.Reason() != reason {
+		t.Fatalf("aborted = %v with %v, want the second reason", combined.Aborted(), combined.Reason())
+	}
+
+	already := AnySignal(NewAbortController().Signal(), AbortedSignal(reason))
+	if !already.Aborted() || already.Reason() != reason {
+		t.Fatal("an already aborted source did not abort the signal")
+	}
+}
+
+func TestTimeoutSignal(t *testing.T) {
+	clock := timers.NewFakeClock()
+	signal := TimeoutSignal(time.Second, clock)
+
+	clock.Advance(999 * time.Millisecond)
+	if signal.Aborted() {
+		t.Fatal("aborted before the timeout")
+	}
+	clock.Advance(time.Millisecond)
+	if !signal.Aborted() || !types.IsErrorCode(signal.Reason(), types.TimeoutError) {
+		t.Fatalf("aborted = %v with %v, want a TimeoutError", signal.Aborted(), signal.Reason())
+	}
+}
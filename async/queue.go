@@ -0,0 +1,370 @@
+package async
+
+import (
+	"container/heap"
+	"fmt"
+	"sync"
+	"time"
+
+	"typescript-golang/timers"
+	"typescript-golang/types"
+)
+
+// QueueOptions configures a Queue (like the options object of p-queue in TypeScript)
+type QueueOptions struct {
+	// Concurrency is the maximum number of tasks running at once (0 means unlimited)
+	Concurrency int
+	// Timeout aborts each task that runs longer than this duration (0 means no timeout)
+	Timeout time.Duration
+	// IntervalCap is the maximum number of tasks started per Interval (0 means unlimited)
+	IntervalCap int
+	// Interval is the window used by IntervalCap
+	Interval time.Duration
+	// Paused creates the queue without starting it (like autoStart: false)
+	Paused bool
+	// Clock times task timeouts and intervals (default timers.Default())
+	Clock timers.Clock
+}
+
+// TaskOptions configures a single enqueued task
+type TaskOptions struct {
+	// Priority orders waiting tasks; higher priorities run first
+	Priority int
+	// Timeout overrides the queue's per-task timeout when non-zero
+	Timeout time.Duration
+}
+
+// queueTask is a waiting task ordered by priority, then insertion order
+type queueTask struct {
+	priority int
+	seq      uint64
+	index    int
+	run      func()
+	cancel   func(error)
+}
+
+type taskHeap []*queueTask
+
+func (h taskHeap) Len() int { return len(h) }
+func (h taskHeap) Less(i, j int) bool {
+	if h[i].priority != h[j].priority {
+		return h[i].priority > h[j].priority
+	}
+	return h[i].seq < h[j].seq
+}
+func (h taskHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+func (h *taskHeap) Push(x interface{}) {
+	task := x.(*queueTask)
+	task.index = len(*h)
+	*h = append(*h, task)
+}
+func (h *taskHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	task := old[n-1]
+	old[n-1] = nil
+	task.index = -1
+	*h = old[:n-1]
+	return task
+}
+
+// Queue runs promise-producing tasks with bounded concurrency (like p-queue in TypeScript).
+// Tasks do not start a goroutine until a concurrency slot is free, so enqueueing
+// thousands of tasks only creates as many goroutines as the concurrency limit.
+type Queue struct {
+	mu      sync.Mutex
+	options QueueOptions
+	waiting taskHeap
+	pending int
+	paused  bool
+	seq     uint64
+
+	intervalStart time.Time
+	intervalCount int
+	intervalTimer timers.Timer
+
+	emptyWaiters []*Promise[interface{}]
+	idleWaiters  []*Promise[interface{}]
+}
+
+// NewQueue creates a new Queue (like new PQueue() in TypeScript)
+func NewQueue(options ...QueueOptions) *Queue {
+	q := &Queue{}
+	if len(options) > 0 {
+		q.options = options[0]
+	}
+	q.options.Clock = timers.Resolve(q.options.Clock)
+	q.paused = q.options.Paused
+	return q
+}
+
+// Enqueue adds a task to the queue and returns a promise for its result (like queue.add() in TypeScript)
+func Enqueue[T any](q *Queue, executor Executor[T], options ...TaskOptions) *Promise[T] {
+	return EnqueueWithSignal(q, func(*AbortSignal) (T, error) {
+		return executor()
+	}, options...)
+}
+
+// EnqueueWithSignal adds a cancellable task to the queue. The signal is aborted when the
+// task times out or when the returned promise is aborted. Aborting a task that is still
+// waiting removes it from the queue without running it.
+func EnqueueWithSignal[T any](q *Queue, executor SignalExecutor[T], options ...TaskOptions) *Promise[T] {
+	var opts TaskOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+
+	p, release := newSignalPromise[T](nil)
+	signal := p.controller.Signal()
+
+	q.mu.Lock()
+	timeout := q.options.Timeout
+	if opts.Timeout > 0 {
+		timeout = opts.Timeout
+	}
+	clock := q.options.Clock
+	q.mu.Unlock()
+
+	task := &queueTask{
+		priority: opts.Priority,
+		cancel: func(reason error) {
+			p.controller.Abort(reason)
+		},
+	}
+	task.run = func() {
+		defer release()
+		if signal.Aborted() {
+			return
+		}
+		if timeout > 0 {
+			timer := clock.AfterFunc(timeout, func() {
+				p.controller.Abort(types.NewTimeoutError(fmt.Sprintf("task timed out after %v", timeout)))
+			})
+			defer timer.Stop()
+		}
+		p.execute(func() (T, error) {
+			return executor(signal)
+		})
+	}
+
+	q.mu.Lock()
+	task.seq = q.seq
+	q.seq++
+	heap.Push(&q.waiting, task)
+	q.mu.Unlock()
+
+	// Aborting a waiting task frees its place in the queue immediately
+	signal.OnAbort(func(error) {
+		q.remove(task)
+	})
+
+	q.dispatch()
+	return p
+}
+
+// remove drops a task that is still waiting
+func (q *Queue) remove(task *queueTask) {
+	q.mu.Lock()
+	var ready []*Promise[interface{}]
+	if task.index >= 0 && task.index < q.waiting.Len() && q.waiting[task.index] == task {
+		heap.Remove(&q.waiting, task.index)
+		ready = q.readyWaiters()
+	}
+	q.mu.Unlock()
+
+	resolveWaiters(ready)
+}
+
+// dispatch starts as many waiting tasks as the concurrency and interval limits allow
+func (q *Queue) dispatch() {
+	q.mu.Lock()
+	for !q.paused && q.waiting.Len() > 0 {
+		if q.options.Concurrency > 0 && q.pending >= q.options.Concurrency {
+			break
+		}
+		if !q.intervalAllows() {
+			break
+		}
+
+		task := heap.Pop(&q.waiting).(*queueTask)
+		q.pending++
+		q.intervalCount++
+		go func() {
+			// The slot is held until the executor returns, keeping goroutines bounded
+			defer q.finish()
+			task.run()
+		}()
+	}
+	ready := q.readyWaiters()
+	q.mu.Unlock()
+
+	resolveWaiters(ready)
+}
+
+// intervalAllows reports whether another task may start in the current interval.
+// Must be called with q.mu held.
+func (q *Queue) intervalAllows() bool {
+	if q.options.IntervalCap <= 0 || q.options.Interval <= 0 {
+		return true
+	}
+
+	now := q.options.Clock.Now()
+	if now.Sub(q.intervalStart) >= q.options.Interval {
+		q.intervalStart = now
+		q.intervalCount = 0
+	}
+	if q.intervalCount < q.options.IntervalCap {
+		return true
+	}
+
+	if q.intervalTimer == nil {
+		q.intervalTimer = q.options.Clock.AfterFunc(q.intervalStart.Add(q.options.Interval).Sub(now), func() {
+			q.mu.Lock()
+			q.intervalTimer = nil
+			q.mu.Unlock()
+			q.dispatch()
+		})
+	}
+	return false
+}
+
+// finish releases a concurrency slot and starts the next task
+func (q *Queue) finish() {
+	q.mu.Lock()
+	q.pending--
+	q.mu.Unlock()
+	q.dispatch()
+}
+
+// readyWaiters takes the OnEmpty/OnIdle promises whose condition now holds. Must be called
+// with q.mu held; the promises are resolved with resolveWaiters after unlocking, since
+// settling runs callbacks that may use the queue.
+func (q *Queue) readyWaiters() []*Promise[interface{}] {
+	if q.waiting.Len() > 0 {
+		return nil
+	}
+	ready := q.emptyWaiters
+	q.emptyWaiters = nil
+
+	if q.pending > 0 {
+		return ready
+	}
+	ready = append(ready, q.idleWaiters...)
+	q.idleWaiters = nil
+	return ready
+}
+
+// resolveWaiters resolves promises taken with readyWaiters
+func resolveWaiters(ready []*Promise[interface{}]) {
+	for _, p := range ready {
+		p.settle(nil, nil)
+	}
+}
+
+// Pause stops starting new tasks; running tasks continue (like queue.pause() in TypeScript)
+func (q *Queue) Pause() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.paused = true
+}
+
+// Start resumes a paused queue (like queue.start() in TypeScript)
+func (q *Queue) Start() *Queue {
+	q.mu.Lock()
+	q.paused = false
+	q.mu.Unlock()
+	q.dispatch()
+	return q
+}
+
+// IsPaused returns true if the queue is paused (like queue.isPaused in TypeScript)
+func (q *Queue) IsPaused() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.paused
+}
+
+// Clear removes all waiting tasks, rejecting their promises with a CancelledError
+// (like queue.clear() in TypeScript)
+func (q *Queue) Clear() {
+	q.mu.Lock()
+	waiting := q.waiting
+	q.waiting = nil
+	for _, task := range waiting {
+		task.index = -1
+	}
+	ready := q.readyWaiters()
+	q.mu.Unlock()
+
+	resolveWaiters(ready)
+	for _, task := range waiting {
+		task.cancel(types.NewError("task removed from queue", types.CancelledError))
+	}
+}
+
+// Size returns the number of tasks waiting to start (like queue.size in TypeScript)
+func (q *Queue) Size() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.waiting.Len()
+}
+
+// Pending returns the number of running tasks (like queue.pending in TypeScript)
+func (q *Queue) Pending() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.pending
+}
+
+// Concurrency returns the configured concurrency limit (0 means unlimited)
+func (q *Queue) Concurrency() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.options.Concurrency
+}
+
+// SetConcurrency changes the concurrency limit, starting waiting tasks if it grew
+func (q *Queue) SetConcurrency(concurrency int) *Queue {
+	q.mu.Lock()
+	q.options.Concurrency = concurrency
+	q.mu.Unlock()
+	q.dispatch()
+	return q
+}
+
+// OnEmpty returns a promise that resolves when no tasks are waiting (like queue.onEmpty() in TypeScript)
+func (q *Queue) OnEmpty() *Promise[interface{}] {
+	p := newPromise[interface{}]()
+	q.mu.Lock()
+	empty := q.waiting.Len() == 0
+	if !empty {
+		q.emptyWaiters = append(q.emptyWaiters, p)
+	}
+	q.mu.Unlock()
+
+	if empty {
+		p.settle(nil, nil)
+	}
+	return p
+}
+
+// OnIdle returns a promise that resolves when no tasks are waiting or running
+// (like queue.onIdle() in TypeScript)
+func (q *Queue) OnIdle() *Promise[interface{}] {
+	p := newPromise[interface{}]()
+	q.mu.Lock()
+	idle := q.waiting.Len() == 0 && q.pending == 0
+	if !idle {
+		q.idleWaiters = append(q.idleWaiters, p)
+	}
+	q.mu.Unlock()
+
+	if idle {
+		p.settle(nil, nil)
+	}
+	return p
+}
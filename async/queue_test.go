@@ -0,0 +1,74 @@
+package async
+
+import (
+	"testing"
+	"time"
+
+	"typescript-golang/timers"
+	"typescript-golang/types"
+)
+
+func TestQueueWaiterCallbacksCanUseQueue(t *testing.T) {
+	q := NewQueue(QueueOptions{Concurrency: 1})
+	release := make(chan struct{})
+	Enqueue(q, func() (int, error) {
+		<-release
+		return 1, nil
+	})
+	Enqueue(q, func() (int, error) { return 2, nil })
+
+	sizes := make(chan int, 2)
+	q.OnEmpty().onSettle(func() { sizes <- q.Size() })
+	q.OnIdle().onSettle(func() {
+		Enqueue(q, func() (int, error) { return 3, nil })
+		sizes <- q.Pending()
+	})
+	close(release)
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-sizes:
+		case <-time.After(time.Second):
+			t.Fatal("queue deadlocked in an OnEmpty/OnIdle callback")
+		}
+	}
+	if _, err := q.OnIdle().AwaitWithTimeout(time.Second); err != nil {
+		t.Fatalf("queue did not become idle: %v", err)
+	}
+}
+
+func TestQueueTimeoutUsesClock(t *testing.T) {
+	clock := timers.NewFakeClock()
+	q := NewQueue(QueueOptions{Timeout: time.Minute, Clock: clock})
+
+	p := EnqueueWithSignal(q, func(signal *AbortSignal) (int, error) {
+		<-signal.Done()
+		return 0, signal.Reason()
+	})
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+
+	_, err := p.AwaitWithTimeout(time.Second)
+	if !types.IsErrorCode(err, types.TimeoutError) {
+		t.Fatalf("expected a TimeoutError, got %v", err)
+	}
+}
+
+func TestQueueIntervalCapUsesClock(t *testing.T) {
+	clock := timers.NewFakeClock()
+	q := NewQueue(QueueOptions{IntervalCap: 1, Interval: time.Second, Clock: clock})
+
+	first := Enqueue(q, func() (int, error) { return 1, nil })
+	second := Enqueue(q, func() (int, error) { return 2, nil })
+	if _, err := first.AwaitWithTimeout(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if !second.IsPending() || q.Size() != 1 {
+		t.Fatal("second task started before the interval elapsed")
+	}
+
+	clock.Advance(time.Second)
+	if v, err := second.AwaitWithTimeout(time.Second); err != nil || v != 2 {
+		t.Fatalf("second task: %v, %v", v, err)
+	}
+}
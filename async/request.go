@@ -0,0 +1,45 @@
+package async
+
+import (
+	"fmt"
+
+	"typescript-golang/types"
+)
+
+// Request publishes a request on an EventBus and returns a promise for the first reply (like
+// a request/reply round trip over a message bus). Handlers answer with BusMessage.Reply. The
+// promise rejects with a ValidationError if the reply is not an R, with the handlers' error
+// if none replies, or with the abort reason if any of the optional signals abort first.
+func Request[R any](bus *types.EventBus, topic string, payload interface{}, signals ...*AbortSignal) *Promise[R] {
+	deferred := WithResolvers[R]()
+	for _, signal := range signals {
+		if signal == nil {
+			continue
+		}
+		remove := signal.OnAbort(func(reason error) {
+			deferred.Reject(reason)
+		})
+		deferred.Promise.onSettle(remove)
+	}
+
+	bus.RequestWith(topic, payload, func(value interface{}, err error) {
+		if err != nil {
+			deferred.Reject(err)
+			return
+		}
+		reply, ok := value.(R)
+		if !ok && value != nil {
+			deferred.Reject(types.NewError(fmt.Sprintf("reply to '%s' is a %T, not a %T", topic, value, reply), types.ValidationError).
+				WithData("topic", topic))
+			return
+		}
+		deferred.Resolve(reply)
+	})
+	return deferred.Promise
+}
+
+// RequestTopic is Request for a typed topic
+func RequestTopic[T, R any](bus *types.EventBus, key types.EventKey[T], payload T, signals ...*AbortSignal) *Promise[R] {
+	types.RegisterTopic(bus, key)
+	return Request[R](bus, key.Name(), payload, signals...)
+}
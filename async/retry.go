@@ -0,0 +1,277 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"typescript-golang/timers"
+	"typescript-golang/types"
+)
+
+// JitterStrategy selects how retry delays are randomized
+type JitterStrategy int
+
+const (
+	// NoJitter uses the plain exponential delay
+	NoJitter JitterStrategy = iota
+	// FullJitter picks a delay in [0, exponential delay)
+	FullJitter
+	// EqualJitter keeps half the exponential delay and randomizes the other half
+	EqualJitter
+	// DecorrelatedJitter picks a delay in [InitialDelay, previous delay * 3)
+	DecorrelatedJitter
+)
+
+func (j JitterStrategy) String() string {
+	switch j {
+	case NoJitter:
+		return "none"
+	case FullJitter:
+		return "full"
+	case EqualJitter:
+		return "equal"
+	case DecorrelatedJitter:
+		return "decorrelated"
+	default:
+		return "unknown"
+	}
+}
+
+// RetryPolicy configures RetryPromise
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one. When zero it
+	// defaults to 3, or to unlimited if MaxElapsedTime is set.
+	MaxAttempts int
+	// InitialDelay is the delay before the first retry (default 100ms)
+	InitialDelay time.Duration
+	// MaxDelay caps any single delay (default 30s)
+	MaxDelay time.Duration
+	// Multiplier grows the delay after each attempt (default 2)
+	Multiplier float64
+	// Jitter randomizes delays to avoid thundering herds
+	Jitter JitterStrategy
+	// MaxElapsedTime stops retrying once the next attempt would start after this budget (0 means no limit)
+	MaxElapsedTime time.Duration
+	// Retryable decides whether an error is worth retrying (default IsRetryable)
+	Retryable func(err error) bool
+	// OnAttempt is called before every attempt, starting at 1
+	OnAttempt func(attempt int)
+	// OnRetry is called after a failed attempt, before waiting delay
+	OnRetry func(attempt int, err error, delay time.Duration)
+	// Signal cancels the retry loop and the running attempt
+	Signal *AbortSignal
+	// Context cancels the retry loop and the running attempt
+	Context context.Context
+	// Clock times the backoff delays (default timers.Default())
+	Clock timers.Clock
+}
+
+// DefaultRetryPolicy returns a policy with three attempts and exponential backoff with full jitter
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:  3,
+		InitialDelay: 100 * time.Millisecond,
+		MaxDelay:     30 * time.Second,
+		Multiplier:   2,
+		Jitter:       FullJitter,
+	}
+}
+
+// withDefaults fills unset fields of the policy
+func (rp RetryPolicy) withDefaults() RetryPolicy {
+	defaults := DefaultRetryPolicy()
+	if rp.MaxAttempts == 0 && rp.MaxElapsedTime <= 0 {
+		rp.MaxAttempts = defaults.MaxAttempts
+	}
+	if rp.InitialDelay <= 0 {
+		rp.InitialDelay = defaults.InitialDelay
+	}
+	if rp.MaxDelay <= 0 {
+		rp.MaxDelay = defaults.MaxDelay
+	}
+	if rp.Multiplier < 1 {
+		rp.Multiplier = defaults.Multiplier
+	}
+	if rp.Retryable == nil {
+		rp.Retryable = IsRetryable
+	}
+	rp.Clock = timers.Resolve(rp.Clock)
+	return rp
+}
+
+// delay computes the wait before the next attempt given the previous delay
+func (rp RetryPolicy) delay(attempt int, previous time.Duration) time.Duration {
+	base := float64(rp.InitialDelay) * math.Pow(rp.Multiplier, float64(attempt-1))
+	if base > float64(rp.MaxDelay) {
+		base = float64(rp.MaxDelay)
+	}
+
+	var d time.Duration
+	switch rp.Jitter {
+	case FullJitter:
+		d = time.Duration(rand.Int63n(int64(base) + 1))
+	case EqualJitter:
+		half := int64(base) / 2
+		d = time.Duration(half + rand.Int63n(half+1))
+	case DecorrelatedJitter:
+		if previous < rp.InitialDelay {
+			previous = rp.InitialDelay
+		}
+		upper := int64(previous) * 3
+		d = rp.InitialDelay + time.Duration(rand.Int63n(upper-int64(rp.InitialDelay)+1))
+	default:
+		d = time.Duration(base)
+	}
+
+	if d > rp.MaxDelay {
+		d = rp.MaxDelay
+	}
+	return d
+}
+
+// IsRetryable is the default retry predicate. Errors carrying a NetworkError or TimeoutError
+// code are retried, as are plain errors; validation, auth, not-found and cancellation
+// errors, and context cancellation, are treated as permanent.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var enhanced *types.EnhancedError
+	if errors.As(err, &enhanced) {
+		switch enhanced.Code() {
+		case types.ValidationError, types.AuthError, types.NotFoundError, types.CancelledError:
+			return false
+		}
+	}
+	return true
+}
+
+// RetryOnCodes returns a retry predicate that only retries EnhancedErrors with one of the given codes
+func RetryOnCodes(codes ...types.ErrorCode) func(err error) bool {
+	return func(err error) bool {
+		var enhanced *types.EnhancedError
+		if !errors.As(err, &enhanced) {
+			return false
+		}
+		for _, code := range codes {
+			if enhanced.Code() == code {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// RetryError is the rejection of a RetryPromise that gave up. It keeps every attempt's error.
+type RetryError struct {
+	Attempts []error
+	// Reason explains why retrying stopped, e.g. attempts exhausted or a permanent error
+	Reason string
+}
+
+// Error implements the error interface
+func (e *RetryError) Error() string {
+	messages := make([]string, len(e.Attempts))
+	for i, err := range e.Attempts {
+		messages[i] = fmt.Sprintf("attempt %d: %v", i+1, err)
+	}
+	return fmt.Sprintf("retry failed after %d attempt(s) (%s): %s", len(e.Attempts), e.Reason, strings.Join(messages, "; "))
+}
+
+// Unwrap returns all attempt errors so errors.Is/As match any of them
+func (e *RetryError) Unwrap() []error {
+	return e.Attempts
+}
+
+// Last returns the error of the final attempt
+func (e *RetryError) Last() error {
+	if len(e.Attempts) == 0 {
+		return nil
+	}
+	return e.Attempts[len(e.Attempts)-1]
+}
+
+// RetryPromise runs fn until it succeeds, backing off between attempts according to the
+// policy. Each attempt receives the promise's AbortSignal, which is aborted when the policy's
+// Signal or Context is cancelled or when the returned promise is aborted; an aborted retry
+// rejects with the abort reason. When retrying gives up the promise rejects with a
+// *RetryError holding every attempt's error.
+func RetryPromise[T any](fn SignalExecutor[T], policy RetryPolicy) *Promise[T] {
+	policy = policy.withDefaults()
+
+	p, release := newSignalPromise[T]([]*AbortSignal{policy.Signal})
+	signal := p.controller.Signal()
+
+	finished := make(chan struct{})
+	if policy.Context != nil && policy.Context.Done() != nil {
+		ctx := policy.Context
+		go func() {
+			select {
+			case <-ctx.Done():
+				p.controller.Abort(ctx.Err())
+			case <-finished:
+			}
+		}()
+	}
+
+	p.run(func() (T, error) {
+		defer release()
+		defer close(finished)
+
+		var zero T
+		var attempts []error
+		var delay time.Duration
+		start := policy.Clock.Now()
+
+		for attempt := 1; ; attempt++ {
+			if signal.Aborted() {
+				return zero, signal.Reason()
+			}
+			if policy.OnAttempt != nil {
+				policy.OnAttempt(attempt)
+			}
+
+			value, err := fn(signal)
+			if err == nil {
+				return value, nil
+			}
+			attempts = append(attempts, err)
+
+			if signal.Aborted() {
+				return zero, &RetryError{Attempts: attempts, Reason: "aborted"}
+			}
+			if !policy.Retryable(err) {
+				return zero, &RetryError{Attempts: attempts, Reason: "permanent error"}
+			}
+			if policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts {
+				return zero, &RetryError{Attempts: attempts, Reason: "attempts exhausted"}
+			}
+
+			delay = policy.delay(attempt, delay)
+			if policy.MaxElapsedTime > 0 && policy.Clock.Since(start)+delay > policy.MaxElapsedTime {
+				return zero, &RetryError{Attempts: attempts, Reason: "max elapsed time exceeded"}
+			}
+			if policy.OnRetry != nil {
+				policy.OnRetry(attempt, err, delay)
+			}
+
+			timer := policy.Clock.NewTimer(delay)
+			select {
+			case <-timer.C():
+			case <-signal.Done():
+				timer.Stop()
+				return zero, &RetryError{Attempts: attempts, Reason: "aborted"}
+			}
+		}
+	})
+	return p
+}
@@ -0,0 +1,195 @@
+package async
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"typescript-golang/timers"
+	"typescript-golang/types"
+)
+
+// failing returns an executor that rejects every attempt with a NetworkError and records it
+func failing(errs *[]error) SignalExecutor[int] {
+	return func(signal *AbortSignal) (int, error) {
+		err := types.NewNetworkError("attempt failed")
+		*errs = append(*errs, err)
+		return 0, err
+	}
+}
+
+func TestRetryBackoffOnClock(t *testing.T) {
+	clock := timers.NewFakeClock()
+	delays := make(chan time.Duration, 4)
+	var errs []error
+	p := RetryPromise(failing(&errs), RetryPolicy{
+		MaxAttempts:  4,
+		InitialDelay: time.Second,
+		MaxDelay:     3 * time.Second,
+		Multiplier:   2,
+		Clock:        clock,
+		OnRetry:      func(attempt int, err error, delay time.Duration) { delays <- delay },
+	})
+
+	// Each retry waits on the clock until the exact exponential delay, capped at MaxDelay
+	for _, want := range []time.Duration{time.Second, 2 * time.Second, 3 * time.Second} {
+		clock.BlockUntil(1)
+		if got := <-delays; got != want {
+			t.Fatalf("delay = %v, want %v", got, want)
+		}
+		clock.Advance(want - time.Millisecond)
+		if !p.IsPending() || clock.TimerCount() != 1 {
+			t.Fatal("retried before the delay elapsed")
+		}
+		clock.Advance(time.Millisecond)
+	}
+	if _, err := p.AwaitWithTimeout(time.Second); err == nil {
+		t.Fatal("expected the retry to give up")
+	}
+}
+
+func TestRetryJitterBounds(t *testing.T) {
+	base := RetryPolicy{InitialDelay: 100 * time.Millisecond, MaxDelay: time.Second, Multiplier: 2}
+	cases := []struct {
+		jitter   JitterStrategy
+		attempt  int
+		previous time.Duration
+		min, max time.Duration
+	}{
+		{FullJitter, 3, 0, 0, 400 * time.Millisecond},
+		{EqualJitter, 3, 0, 200 * time.Millisecond, 400 * time.Millisecond},
+		{DecorrelatedJitter, 3, 200 * time.Millisecond, 100 * time.Millisecond, 600 * time.Millisecond},
+		{FullJitter, 10, 0, 0, time.Second},
+		{DecorrelatedJitter, 10, 900 * time.Millisecond, 100 * time.Millisecond, time.Second},
+	}
+	for _, c := range cases {
+		policy := base
+		policy.Jitter = c.jitter
+		policy = policy.withDefaults()
+		for i := 0; i < 1000; i++ {
+			if d := policy.delay(c.attempt, c.previous); d < c.min || d > c.max {
+				t.Fatalf("%v jitter on attempt %d gave %v, want [%v, %v]", c.jitter, c.attempt, d, c.min, c.max)
+			}
+		}
+	}
+}
+
+func TestRetryMaxElapsedTime(t *testing.T) {
+	clock := timers.NewFakeClock()
+	var errs []error
+	p := RetryPromise(failing(&errs), RetryPolicy{
+		InitialDelay:   time.Second,
+		Multiplier:     2,
+		MaxElapsedTime: 5 * time.Second,
+		Clock:          clock,
+	})
+
+	// Retries wait 1s and 2s; the next 4s delay would end after the 5s budget
+	for _, delay := range []time.Duration{time.Second, 2 * time.Second} {
+		clock.BlockUntil(1)
+		clock.Advance(delay)
+	}
+	_, err := p.AwaitWithTimeout(time.Second)
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) || retryErr.Reason != "max elapsed time exceeded" {
+		t.Fatalf("expected the elapsed time cutoff, got %v", err)
+	}
+	if len(retryErr.Attempts) != 3 {
+		t.Fatalf("made %d attempts, want 3", len(retryErr.Attempts))
+	}
+}
+
+func TestRetryablePredicate(t *testing.T) {
+	retryable := RetryOnCodes(types.NetworkError, types.TimeoutError)
+	cases := []struct {
+		err          error
+		custom, base bool
+	}{
+		{types.NewNetworkError("down"), true, true},
+		{types.NewTimeoutError("slow"), true, true},
+		{types.NewError("bad input", types.ValidationError), false, false},
+		{types.NewError("gone", types.NotFoundError), false, false},
+		{errors.New("plain"), false, true},
+	}
+	for _, c := range cases {
+		if got := retryable(c.err); got != c.custom {
+			t.Errorf("RetryOnCodes(%v) = %v, want %v", c.err, got, c.custom)
+		}
+		if got := IsRetryable(c.err); got != c.base {
+			t.Errorf("IsRetryable(%v) = %v, want %v", c.err, got, c.base)
+		}
+	}
+
+	// A permanent error stops retrying at once
+	clock := timers.NewFakeClock()
+	codes := []types.ErrorCode{types.NetworkError, types.TimeoutError, types.ValidationError}
+	attempts := 0
+	p := RetryPromise(func(signal *AbortSignal) (int, error) {
+		code := codes[attempts]
+		attempts++
+		return 0, types.NewError("failed", code)
+	}, RetryPolicy{MaxAttempts: 5, InitialDelay: time.Second, Retryable: retryable, Clock: clock})
+
+	for _, delay := range []time.Duration{time.Second, 2 * time.Second} {
+		clock.BlockUntil(1)
+		clock.Advance(delay)
+	}
+	_, err := p.AwaitWithTimeout(time.Second)
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) || retryErr.Reason != "permanent error" || attempts != 3 {
+		t.Fatalf("got %v after %d attempts, want a permanent error after 3", err, attempts)
+	}
+}
+
+func TestRetryAbortDuringBackoff(t *testing.T) {
+	clock := timers.NewFakeClock()
+	controller := NewAbortController()
+	var errs []error
+	p := RetryPromise(failing(&errs), RetryPolicy{
+		MaxAttempts:  3,
+		InitialDelay: time.Minute,
+		Signal:       controller.Signal(),
+		Clock:        clock,
+	})
+
+	clock.BlockUntil(1)
+	reason := errors.New("stop")
+	controller.Abort(reason)
+	if _, err := p.AwaitWithTimeout(time.Second); err != reason {
+		t.Fatalf("rejected with %v, want the abort reason", err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for clock.TimerCount() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("the backoff timer was not stopped")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if len(errs) != 1 {
+		t.Fatalf("made %d attempts, want 1", len(errs))
+	}
+}
+
+func TestRetryErrorHoldsEveryAttempt(t *testing.T) {
+	clock := timers.NewFakeClock()
+	var errs []error
+	p := RetryPromise(failing(&errs), RetryPolicy{MaxAttempts: 3, InitialDelay: time.Second, Clock: clock})
+
+	for _, delay := range []time.Duration{time.Second, 2 * time.Second} {
+		clock.BlockUntil(1)
+		clock.Advance(delay)
+	}
+	_, err := p.AwaitWithTimeout(time.Second)
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) || retryErr.Reason != "attempts exhausted" {
+		t.Fatalf("expected exhausted attempts, got %v", err)
+	}
+	if len(retryErr.Attempts) != 3 || retryErr.Last() != errs[2] {
+		t.Fatalf("attempts = %v, want the 3 attempt errors", retryErr.Attempts)
+	}
+	for i, attemptErr := range errs {
+		if retryErr.Attempts[i] != attemptErr || !errors.Is(err, attemptErr) {
+			t.Fatalf("attempt %d error %v is missing from %v", i+1, attemptErr, err)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package eventstore
+
+import (
+	"fmt"
+	"strconv"
+
+	"typescript-golang/types"
+)
+
+// Bus message headers set on published events
+const (
+	AggregateHeader = "x-aggregate-id"
+	VersionHeader   = "x-aggregate-version"
+	PositionHeader  = "x-position"
+	// ReplayHeader is "true" on events that were stored before publishing started
+	ReplayHeader = "x-replay"
+)
+
+// PublishTo publishes the events after a position to an EventBus, each on the topic named by
+// its type and decoded into the topic's registered payload type: first the stored history,
+// marked with ReplayHeader, then every new event. Call it on startup so bus subscribers rebuild
+// their state. It returns a function that stops publishing.
+func (s *Store) PublishTo(bus *types.EventBus, afterPosition int64) func() {
+	replayUntil := s.Position()
+	return s.Subscribe(afterPosition, func(record Record) {
+		if err := publishRecord(bus, record, record.Position <= replayUntil); err != nil {
+			fmt.Printf("Warning: failed to publish event %d of type '%s': %v\n", record.Position, record.Type, err)
+		}
+	})
+}
+
+// ReplayTo publishes the stored events after a position to an EventBus, marked with
+// ReplayHeader, stopping at the first error
+func (s *Store) ReplayTo(bus *types.EventBus, afterPosition int64) error {
+	for _, record := range s.ReadAll(afterPosition) {
+		if err := publishRecord(bus, record, true); err != nil {
+			return types.WrapError(err, fmt.Sprintf("failed to replay event %d of type '%s'", record.Position, record.Type), types.InternalError).
+				WithData("position", record.Position)
+		}
+	}
+	return nil
+}
+
+// publishRecord publishes one record with its store headers
+func publishRecord(bus *types.EventBus, record Record, replay bool) error {
+	payload, err := bus.DecodePayload(record.Type, record.Data)
+	if err != nil {
+		return err
+	}
+	headers := map[string]string{
+		AggregateHeader: record.AggregateID,
+		VersionHeader:   strconv.Itoa(record.Version),
+		PositionHeader:  strconv.FormatInt(record.Position, 10),
+	}
+	if replay {
+		headers[ReplayHeader] = "true"
+	}
+	return bus.PublishMessage(&types.BusMessage{
+		Topic:     record.Type,
+		Payload:   payload,
+		Headers:   headers,
+		Timestamp: record.Timestamp,
+	})
+}
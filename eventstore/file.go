@@ -0,0 +1,435 @@
+package eventstore
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"typescript-golang/types"
+	"typescript-golang/utils"
+)
+
+// DefaultSegmentSize is the size at which a FileBackend starts a new segment file
+const DefaultSegmentSize = 16 << 20
+
+const (
+	segmentPrefix = "events-"
+	segmentSuffix = ".jsonl"
+	snapshotsFile = "snapshots.jsonl"
+	tmpSuffix     = ".tmp"
+)
+
+// FileOptions configures a FileBackend
+type FileOptions struct {
+	// SegmentSize is the size at which a new segment file is started (default DefaultSegmentSize)
+	SegmentSize int64
+	// NoSync skips the fsync after each append, trading durability for speed
+	NoSync bool
+}
+
+// FileBackend stores records as JSON lines in numbered segment files (events-00000001.jsonl,
+// ...) and snapshots in snapshots.jsonl. Every append is fsynced, and a line torn by a crash
+// at the end of the last file is truncated on load.
+type FileBackend struct {
+	mu         sync.Mutex
+	dir        string
+	options    FileOptions
+	segments   []int
+	active     *os.File
+	activeSize int64
+	snapshots  *os.File
+	closed     bool
+}
+
+// NewFileBackend creates a backend in dir, creating the directory if needed
+func NewFileBackend(dir string, options ...FileOptions) (*FileBackend, error) {
+	var opts FileOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+	if opts.SegmentSize <= 0 {
+		opts.SegmentSize = DefaultSegmentSize
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, types.WrapError(err, fmt.Sprintf("failed to create event store directory %s", dir), types.InternalError)
+	}
+	return &FileBackend{dir: dir, options: opts}, nil
+}
+
+// segmentPath returns the path of a segment file
+func (b *FileBackend) segmentPath(n int) string {
+	return filepath.Join(b.dir, fmt.Sprintf("%s%08d%s", segmentPrefix, n, segmentSuffix))
+}
+
+// listSegments returns the segment numbers in ascending order, removing temporary files left
+// by an interrupted compaction
+func (b *FileBackend) listSegments() ([]int, error) {
+	entries, err := os.ReadDir(b.dir)
+	if err != nil {
+		return nil, err
+	}
+	var segments []int
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasSuffix(name, tmpSuffix) {
+			os.Remove(filepath.Join(b.dir, name))
+			continue
+		}
+		if !strings.HasPrefix(name, segmentPrefix) || !strings.HasSuffix(name, segmentSuffix) {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, segmentPrefix), segmentSuffix))
+		if err == nil {
+			segments = append(segments, n)
+		}
+	}
+	sort.Ints(segments)
+	return segments, nil
+}
+
+// Load reads all segments and snapshots and opens the last segment for appending
+func (b *FileBackend) Load() ([]Record, []Snapshot, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil, nil, ErrClosed
+	}
+	b.closeFiles()
+
+	segments, err := b.listSegments()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var records []Record
+	var last int64
+	for i, n := range segments {
+		err := readLines(b.segmentPath(n), i == len(segments)-1, func(line []byte) error {
+			record, err := utils.ParseTo[Record](string(line))
+			if err != nil {
+				return err
+			}
+			// Compaction writes its segments before removing the old ones, so a crash in
+			// between leaves the same positions in two segments
+			if record.Position > last {
+				records = append(records, record)
+				last = record.Position
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var snapshots []Snapshot
+	err = readLines(filepath.Join(b.dir, snapshotsFile), true, func(line []byte) error {
+		snapshot, err := utils.ParseTo[Snapshot](string(line))
+		if err == nil {
+			snapshots = append(snapshots, snapshot)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(segments) == 0 {
+		segments = []int{1}
+	}
+	b.segments = segments
+	if err := b.openFiles(); err != nil {
+		return nil, nil, err
+	}
+	return records, snapshots, nil
+}
+
+// readLines calls fn with each line of a file. With repair, an unterminated last line, left by
+// a crash during a write, is truncated instead of failing the load.
+func readLines(path string, repair bool, fn func(line []byte) error) error {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(file)
+	var offset int64
+	torn := false
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		if readErr == io.EOF {
+			torn = len(line) > 0
+			break
+		}
+		if readErr != nil {
+			file.Close()
+			return readErr
+		}
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			if err := fn(trimmed); err != nil {
+				file.Close()
+				return types.WrapError(err, fmt.Sprintf("corrupt line at offset %d of %s", offset, path), types.InternalError)
+			}
+		}
+		offset += int64(len(line))
+	}
+	file.Close()
+
+	if torn {
+		if !repair {
+			return types.NewError(fmt.Sprintf("unterminated line at offset %d of %s", offset, path), types.InternalError)
+		}
+		fmt.Printf("Warning: truncating torn write at offset %d of %s\n", offset, path)
+		return os.Truncate(path, offset)
+	}
+	return nil
+}
+
+// openFiles opens the last segment and the snapshots file for appending
+func (b *FileBackend) openFiles() error {
+	active, err := os.OpenFile(b.segmentPath(b.segments[len(b.segments)-1]), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := active.Stat()
+	if err != nil {
+		active.Close()
+		return err
+	}
+	snapshots, err := os.OpenFile(filepath.Join(b.dir, snapshotsFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		active.Close()
+		return err
+	}
+	b.active, b.activeSize, b.snapshots = active, info.Size(), snapshots
+	return nil
+}
+
+// closeFiles syncs and closes the open files
+func (b *FileBackend) closeFiles() error {
+	var errs []error
+	for _, file := range []*os.File{b.active, b.snapshots} {
+		if file != nil {
+			errs = append(errs, file.Sync(), file.Close())
+		}
+	}
+	b.active, b.snapshots = nil, nil
+	return errors.Join(errs...)
+}
+
+// Append writes records as lines of the active segment, starting a new segment when it is full
+func (b *FileBackend) Append(records []Record) error {
+	var buf bytes.Buffer
+	for _, record := range records {
+		if err := appendLine(&buf, record); err != nil {
+			return err
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed || b.active == nil {
+		return ErrClosed
+	}
+	if b.activeSize > 0 && b.activeSize+int64(buf.Len()) > b.options.SegmentSize {
+		if err := b.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := b.active.Write(buf.Bytes())
+	if err != nil {
+		// Drop a partial write so the segment keeps whole lines
+		b.active.Truncate(b.activeSize)
+		return err
+	}
+	if !b.options.NoSync {
+		if err := b.active.Sync(); err != nil {
+			return err
+		}
+	}
+	b.activeSize += int64(n)
+	return nil
+}
+
+// rotate closes the active segment and starts the next one
+func (b *FileBackend) rotate() error {
+	if err := b.active.Sync(); err != nil {
+		return err
+	}
+	b.active.Close()
+
+	next := b.segments[len(b.segments)-1] + 1
+	active, err := os.OpenFile(b.segmentPath(next), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		b.active = nil
+		return err
+	}
+	b.segments = append(b.segments, next)
+	b.active, b.activeSize = active, 0
+	return syncDir(b.dir)
+}
+
+// SaveSnapshot appends a snapshot to the snapshots file
+func (b *FileBackend) SaveSnapshot(snapshot Snapshot) error {
+	var buf bytes.Buffer
+	if err := appendLine(&buf, snapshot); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed || b.snapshots == nil {
+		return ErrClosed
+	}
+	if _, err := b.snapshots.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	if b.options.NoSync {
+		return nil
+	}
+	return b.snapshots.Sync()
+}
+
+// Compact writes records to new segments numbered after the existing ones, then removes the old
+// segments and replaces the snapshots file
+func (b *FileBackend) Compact(records []Record, snapshots []Snapshot) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return ErrClosed
+	}
+	if err := b.closeFiles(); err != nil {
+		return err
+	}
+	defer func() {
+		if b.active == nil && !b.closed {
+			b.openFiles()
+		}
+	}()
+
+	next := b.segments[len(b.segments)-1] + 1
+	var written []int
+	var buf bytes.Buffer
+	flush := func() error {
+		if err := writeFileSync(b.segmentPath(next), buf.Bytes()); err != nil {
+			return err
+		}
+		written = append(written, next)
+		next++
+		buf.Reset()
+		return nil
+	}
+	for _, record := range records {
+		if err := appendLine(&buf, record); err != nil {
+			return err
+		}
+		if int64(buf.Len()) >= b.options.SegmentSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if buf.Len() > 0 || len(written) == 0 {
+		if err := flush(); err != nil {
+			return err
+		}
+	}
+	if err := syncDir(b.dir); err != nil {
+		return err
+	}
+
+	// Remove the newest old segment first: a crash part way leaves a prefix of the old positions,
+	// which Load reads before the new segments and deduplicates
+	old := b.segments
+	b.segments = written
+	for i := len(old) - 1; i >= 0; i-- {
+		if err := os.Remove(b.segmentPath(old[i])); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	buf.Reset()
+	for _, snapshot := range snapshots {
+		if err := appendLine(&buf, snapshot); err != nil {
+			return err
+		}
+	}
+	if err := writeFileSync(filepath.Join(b.dir, snapshotsFile), buf.Bytes()); err != nil {
+		return err
+	}
+	if err := syncDir(b.dir); err != nil {
+		return err
+	}
+	return b.openFiles()
+}
+
+// Close syncs and closes the files
+func (b *FileBackend) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil
+	}
+	b.closed = true
+	return b.closeFiles()
+}
+
+// appendLine writes value as one JSON line
+func appendLine(buf *bytes.Buffer, value interface{}) error {
+	line, err := utils.JSON.StringifyCompact(value)
+	if err != nil {
+		return err
+	}
+	buf.WriteString(line)
+	buf.WriteByte('\n')
+	return nil
+}
+
+// writeFileSync atomically replaces a file: it writes and fsyncs a temporary file, then renames it
+func writeFileSync(path string, data []byte) error {
+	tmp := path + tmpSuffix
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// syncDir fsyncs a directory so created, renamed and removed files survive a crash
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	return d.Sync()
+}
@@ -0,0 +1,57 @@
+package eventstore
+
+import "sync"
+
+// MemoryBackend keeps records and snapshots in memory. Opening a new Store on the same backend
+// simulates a restart, e.g. in tests.
+type MemoryBackend struct {
+	mu        sync.Mutex
+	records   []Record
+	snapshots []Snapshot
+}
+
+// NewMemoryBackend creates an empty in-memory backend
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{}
+}
+
+// Load returns copies of the stored records and snapshots
+func (b *MemoryBackend) Load() ([]Record, []Snapshot, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]Record(nil), b.records...), append([]Snapshot(nil), b.snapshots...), nil
+}
+
+// Append stores records
+func (b *MemoryBackend) Append(records []Record) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.records = append(b.records, records...)
+	return nil
+}
+
+// SaveSnapshot stores a snapshot
+func (b *MemoryBackend) SaveSnapshot(snapshot Snapshot) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.snapshots = append(b.snapshots, snapshot)
+	return nil
+}
+
+// Compact replaces the stored records and snapshots
+func (b *MemoryBackend) Compact(records []Record, snapshots []Snapshot) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.records = append([]Record(nil), records...)
+	b.snapshots = append([]Snapshot(nil), snapshots...)
+	return nil
+}
+
+// Close does nothing; the data stays available to stores opened later
+func (b *MemoryBackend) Close() error {
+	return nil
+}
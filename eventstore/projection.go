@@ -0,0 +1,152 @@
+package eventstore
+
+import (
+	"fmt"
+	"sync"
+
+	"typescript-golang/types"
+	"typescript-golang/utils"
+)
+
+// LoadAggregate rebuilds the state of an aggregate from its latest snapshot, or from initial,
+// by applying the events after it. It returns the state and the version to pass to Append.
+func LoadAggregate[S any](s *Store, aggregateID string, initial S, apply func(state S, record Record) (S, error)) (S, int, error) {
+	state := initial
+	version := NoStream
+	if snapshot := s.Snapshot(aggregateID); snapshot.IsSome() {
+		decoded, err := utils.ParseTo[S](string(snapshot.Get().State))
+		if err != nil {
+			return initial, NoStream, types.WrapError(err, fmt.Sprintf("failed to decode snapshot of aggregate '%s'", aggregateID), types.ValidationError)
+		}
+		state, version = decoded, snapshot.Get().Version
+	}
+
+	for _, record := range s.Load(aggregateID, version) {
+		next, err := apply(state, record)
+		if err != nil {
+			return state, version, err
+		}
+		state, version = next, record.Version
+	}
+	return state, version, nil
+}
+
+// Projection folds every event of a store into a read model (like a projection in
+// EventStoreDB). It replays the history when created and then follows new events.
+type Projection[S any] struct {
+	mu          sync.RWMutex
+	store       *Store
+	initial     func() S
+	apply       func(state S, record Record) (S, error)
+	state       S
+	position    int64
+	err         error
+	generation  int
+	unsubscribe func()
+}
+
+// NewProjection creates a projection, building its state from initial() and every stored
+// event before it returns. An apply error stops the projection until Rebuild.
+func NewProjection[S any](s *Store, initial func() S, apply func(state S, record Record) (S, error)) *Projection[S] {
+	p := &Projection[S]{store: s, initial: initial, apply: apply}
+	p.Rebuild()
+	return p
+}
+
+// Rebuild resets the state and replays the history, e.g. after changing the apply function's
+// behavior or recovering from an error
+func (p *Projection[S]) Rebuild() error {
+	p.mu.Lock()
+	p.generation++
+	generation := p.generation
+	p.state = p.initial()
+	p.position = 0
+	p.err = nil
+	previous := p.unsubscribe
+	p.unsubscribe = nil
+	p.mu.Unlock()
+
+	if previous != nil {
+		previous()
+	}
+	unsubscribe := p.store.Subscribe(0, func(record Record) {
+		p.handle(generation, record)
+	})
+
+	p.mu.Lock()
+	if p.generation != generation {
+		p.mu.Unlock()
+		unsubscribe()
+		return p.Err()
+	}
+	p.unsubscribe = unsubscribe
+	err := p.err
+	p.mu.Unlock()
+	return err
+}
+
+// handle applies one record of the current generation. The store delivers the records of a
+// generation one at a time, so apply runs without the lock and may call the projection.
+func (p *Projection[S]) handle(generation int, record Record) {
+	p.mu.RLock()
+	current, failed := p.state, p.err != nil
+	stale := generation != p.generation
+	p.mu.RUnlock()
+	if stale || failed {
+		return
+	}
+
+	state, err := p.apply(current, record)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Rebuild or Close started another generation meanwhile
+	if generation != p.generation {
+		return
+	}
+	if err != nil {
+		p.err = types.WrapError(err, fmt.Sprintf("projection failed on event %d of type '%s'", record.Position, record.Type), types.InternalError).
+			WithData("position", record.Position)
+		return
+	}
+	p.state = state
+	p.position = record.Position
+}
+
+// State returns the current state
+func (p *Projection[S]) State() S {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.state
+}
+
+// Position returns the position of the last applied event
+func (p *Projection[S]) Position() int64 {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.position
+}
+
+// Err returns the error that stopped the projection, if any
+func (p *Projection[S]) Err() error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.err
+}
+
+// Close stops following new events
+func (p *Projection[S]) Close() {
+	p.mu.Lock()
+	p.generation++
+	unsubscribe := p.unsubscribe
+	p.unsubscribe = nil
+	p.mu.Unlock()
+
+	if unsubscribe != nil {
+		unsubscribe()
+	}
+}
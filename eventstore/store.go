@@ -0,0 +1,504 @@
+package eventstore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"typescript-golang/timers"
+	"typescript-golang/types"
+	"typescript-golang/utils"
+)
+
+const (
+	// AnyVersion skips the optimistic concurrency check of Append
+	AnyVersion = -1
+	// NoStream is the expected version of an aggregate without events
+	NoStream = 0
+)
+
+var (
+	// ErrVersionConflict is returned by Append when the aggregate moved past the expected version
+	ErrVersionConflict = errors.New("aggregate version conflict")
+	// ErrClosed is returned by a closed store
+	ErrClosed = errors.New("event store is closed")
+)
+
+// Record is a stored event. Version counts the events of its aggregate from 1 and Position
+// counts all events of the store from 1.
+type Record struct {
+	Position    int64           `json:"position"`
+	AggregateID string          `json:"aggregateId"`
+	Version     int             `json:"version"`
+	Type        string          `json:"type"`
+	Data        json.RawMessage `json:"data"`
+	Timestamp   time.Time       `json:"timestamp"`
+}
+
+// Event returns the record as a types.Event with the aggregate ID as Source
+func (r Record) Event() *types.Event[json.RawMessage] {
+	return &types.Event[json.RawMessage]{Type: r.Type, Data: r.Data, Timestamp: r.Timestamp, Source: r.AggregateID}
+}
+
+// Decode decodes a record into a types.Event with a typed payload
+func Decode[T any](record Record) (*types.Event[T], error) {
+	data, err := utils.ParseTo[T](string(record.Data))
+	if err != nil {
+		return nil, types.WrapError(err, fmt.Sprintf("failed to decode event %d of type '%s'", record.Position, record.Type), types.ValidationError)
+	}
+	return &types.Event[T]{Type: record.Type, Data: data, Timestamp: record.Timestamp, Source: record.AggregateID}, nil
+}
+
+// Snapshot is the saved state of an aggregate at a version
+type Snapshot struct {
+	AggregateID string          `json:"aggregateId"`
+	Version     int             `json:"version"`
+	Position    int64           `json:"position"`
+	State       json.RawMessage `json:"state"`
+	Timestamp   time.Time       `json:"timestamp"`
+}
+
+// Backend persists the records and snapshots of a Store. MemoryBackend and FileBackend are the
+// built-in implementations.
+type Backend interface {
+	// Load returns the stored records in position order and the stored snapshots
+	Load() ([]Record, []Snapshot, error)
+	// Append durably stores new records
+	Append(records []Record) error
+	SaveSnapshot(snapshot Snapshot) error
+	// Compact replaces everything stored with records and snapshots
+	Compact(records []Record, snapshots []Snapshot) error
+	Close() error
+}
+
+// CompactOptions configures Store.Compact
+type CompactOptions struct {
+	// DropSnapshotted removes the events of every aggregate up to its latest snapshot. Rebuilt
+	// projections no longer see the removed events.
+	DropSnapshotted bool
+}
+
+// stream indexes the records of one aggregate
+type stream struct {
+	version int
+	indices []int
+}
+
+// Store is an append-only event store keyed by aggregate ID (like an EventStoreDB client).
+// Appends are checked against the expected aggregate version. Subscribers receive new records
+// in position order, usually on the appending goroutine before Append returns; while another
+// goroutine is delivering to a subscriber, including a handler that appends, that goroutine
+// delivers the new records instead, after Append has returned.
+type Store struct {
+	mu          sync.RWMutex
+	backend     Backend
+	clock       timers.Clock
+	records     []Record
+	streams     map[string]*stream
+	snapshots   map[string]Snapshot
+	position    int64
+	subscribers map[*storeSubscriber]struct{}
+	closed      bool
+}
+
+// Open opens a store on a backend, loading its history
+func Open(backend Backend, clock ...timers.Clock) (*Store, error) {
+	records, snapshots, err := backend.Load()
+	if err != nil {
+		return nil, types.WrapError(err, "failed to load event store", types.InternalError)
+	}
+
+	s := &Store{
+		backend:     backend,
+		clock:       timers.Resolve(clock...),
+		subscribers: make(map[*storeSubscriber]struct{}),
+	}
+	s.index(records, snapshots)
+	return s, nil
+}
+
+// NewMemoryStore creates a store that keeps its events in memory, e.g. for tests
+func NewMemoryStore(clock ...timers.Clock) *Store {
+	s, _ := Open(NewMemoryBackend(), clock...)
+	return s
+}
+
+// OpenFileStore opens a store that keeps its events in JSONL segment files under dir
+func OpenFileStore(dir string, options ...FileOptions) (*Store, error) {
+	backend, err := NewFileBackend(dir, options...)
+	if err != nil {
+		return nil, err
+	}
+	s, err := Open(backend)
+	if err != nil {
+		backend.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// index rebuilds the in-memory indexes from records and snapshots
+func (s *Store) index(records []Record, snapshots []Snapshot) {
+	s.records = records
+	s.streams = make(map[string]*stream)
+	s.snapshots = make(map[string]Snapshot)
+
+	for i, record := range records {
+		st := s.stream(record.AggregateID)
+		st.version = record.Version
+		st.indices = append(st.indices, i)
+		if record.Position > s.position {
+			s.position = record.Position
+		}
+	}
+	// Snapshots keep versions and positions of events removed by compaction
+	for _, snapshot := range snapshots {
+		if current, exists := s.snapshots[snapshot.AggregateID]; exists && current.Version > snapshot.Version {
+			continue
+		}
+		s.snapshots[snapshot.AggregateID] = snapshot
+		if st := s.stream(snapshot.AggregateID); snapshot.Version > st.version {
+			st.version = snapshot.Version
+		}
+		if snapshot.Position > s.position {
+			s.position = snapshot.Position
+		}
+	}
+}
+
+// stream returns the index of an aggregate, creating it if needed
+func (s *Store) stream(aggregateID string) *stream {
+	st, exists := s.streams[aggregateID]
+	if !exists {
+		st = &stream{}
+		s.streams[aggregateID] = st
+	}
+	return st
+}
+
+// Append appends events to an aggregate if its version equals expectedVersion (NoStream for a
+// new aggregate, AnyVersion to skip the check). Event payloads are stored as JSON and zero
+// timestamps are set to the current time.
+func (s *Store) Append(aggregateID string, expectedVersion int, events ...*types.Event[interface{}]) ([]Record, error) {
+	if aggregateID == "" {
+		return nil, types.NewValidationError("aggregate ID must not be empty")
+	}
+
+	encoded := make([]json.RawMessage, len(events))
+	for i, event := range events {
+		data, err := utils.JSON.StringifyCompact(event.Data)
+		if err != nil {
+			return nil, types.WrapError(err, fmt.Sprintf("failed to encode event of type '%s'", event.Type), types.ValidationError)
+		}
+		encoded[i] = json.RawMessage(data)
+	}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil, ErrClosed
+	}
+	version := 0
+	if st, exists := s.streams[aggregateID]; exists {
+		version = st.version
+	}
+	if expectedVersion != AnyVersion && expectedVersion != version {
+		s.mu.Unlock()
+		return nil, types.WrapError(ErrVersionConflict, fmt.Sprintf("aggregate '%s' is at version %d, expected %d", aggregateID, version, expectedVersion), types.ValidationError).
+			WithData("aggregateId", aggregateID).
+			WithData("expectedVersion", expectedVersion).
+			WithData("version", version)
+	}
+
+	records := make([]Record, len(events))
+	for i, event := range events {
+		timestamp := event.Timestamp
+		if timestamp.IsZero() {
+			timestamp = s.clock.Now()
+		}
+		records[i] = Record{
+			Position:    s.position + int64(i) + 1,
+			AggregateID: aggregateID,
+			Version:     version + i + 1,
+			Type:        event.Type,
+			Data:        encoded[i],
+			Timestamp:   timestamp,
+		}
+	}
+	if err := s.backend.Append(records); err != nil {
+		s.mu.Unlock()
+		return nil, types.WrapError(err, fmt.Sprintf("failed to append to aggregate '%s'", aggregateID), types.InternalError)
+	}
+
+	st := s.stream(aggregateID)
+	for _, record := range records {
+		st.indices = append(st.indices, len(s.records))
+		s.records = append(s.records, record)
+	}
+	st.version += len(records)
+	s.position += int64(len(records))
+	subscribers := s.subscriberList()
+	s.mu.Unlock()
+
+	for _, sub := range subscribers {
+		s.catchUp(sub)
+	}
+	return records, nil
+}
+
+// AppendEvents appends typed events to an aggregate, see Store.Append
+func AppendEvents[T any](s *Store, aggregateID string, expectedVersion int, events ...*types.Event[T]) ([]Record, error) {
+	converted := make([]*types.Event[interface{}], len(events))
+	for i, event := range events {
+		converted[i] = &types.Event[interface{}]{Type: event.Type, Data: event.Data, Timestamp: event.Timestamp, Source: event.Source}
+	}
+	return s.Append(aggregateID, expectedVersion, converted...)
+}
+
+// Version returns the version of an aggregate, NoStream if it has no events
+func (s *Store) Version(aggregateID string) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if st, exists := s.streams[aggregateID]; exists {
+		return st.version
+	}
+	return NoStream
+}
+
+// Position returns the position of the last event in the store
+func (s *Store) Position() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.position
+}
+
+// Load returns the events of an aggregate after a version, 0 for all of them
+func (s *Store) Load(aggregateID string, afterVersion int) []Record {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	st, exists := s.streams[aggregateID]
+	if !exists {
+		return nil
+	}
+	var records []Record
+	for _, i := range st.indices {
+		if s.records[i].Version > afterVersion {
+			records = append(records, s.records[i])
+		}
+	}
+	return records
+}
+
+// ReadAll returns the events of all aggregates after a position, 0 for all of them
+func (s *Store) ReadAll(afterPosition int64) []Record {
+	return s.readAfter(afterPosition, -1)
+}
+
+// readAfter returns up to limit records after a position, all of them for a negative limit
+func (s *Store) readAfter(afterPosition int64, limit int) []Record {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	start := sort.Search(len(s.records), func(i int) bool {
+		return s.records[i].Position > afterPosition
+	})
+	end := len(s.records)
+	if limit >= 0 && start+limit < end {
+		end = start + limit
+	}
+	return append([]Record(nil), s.records[start:end]...)
+}
+
+// SaveSnapshot saves the state of an aggregate at a version, so LoadAggregate can start from it
+// instead of the first event
+func (s *Store) SaveSnapshot(aggregateID string, version int, state interface{}) error {
+	data, err := utils.JSON.StringifyCompact(state)
+	if err != nil {
+		return types.WrapError(err, fmt.Sprintf("failed to encode snapshot of aggregate '%s'", aggregateID), types.ValidationError)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+	current := 0
+	if st, exists := s.streams[aggregateID]; exists {
+		current = st.version
+	}
+	if version < 1 || version > current {
+		return types.NewValidationError(fmt.Sprintf("snapshot version %d of aggregate '%s' is outside 1..%d", version, aggregateID, current))
+	}
+
+	snapshot := Snapshot{
+		AggregateID: aggregateID,
+		Version:     version,
+		Position:    s.position,
+		State:       json.RawMessage(data),
+		Timestamp:   s.clock.Now(),
+	}
+	if err := s.backend.SaveSnapshot(snapshot); err != nil {
+		return types.WrapError(err, fmt.Sprintf("failed to save snapshot of aggregate '%s'", aggregateID), types.InternalError)
+	}
+	if existing, exists := s.snapshots[aggregateID]; !exists || existing.Version <= version {
+		s.snapshots[aggregateID] = snapshot
+	}
+	return nil
+}
+
+// Snapshot returns the latest snapshot of an aggregate, if any
+func (s *Store) Snapshot(aggregateID string) types.Optional[Snapshot] {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if snapshot, exists := s.snapshots[aggregateID]; exists {
+		return types.Some(snapshot)
+	}
+	return types.None[Snapshot]()
+}
+
+// Compact rewrites the backend storage, keeping the latest snapshot of each aggregate and,
+// with DropSnapshotted, only the events after it
+func (s *Store) Compact(options ...CompactOptions) error {
+	var opts CompactOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+
+	records := s.records
+	if opts.DropSnapshotted {
+		records = make([]Record, 0, len(s.records))
+		for _, record := range s.records {
+			if snapshot, exists := s.snapshots[record.AggregateID]; exists && record.Version <= snapshot.Version {
+				continue
+			}
+			records = append(records, record)
+		}
+	}
+	snapshots := make([]Snapshot, 0, len(s.snapshots))
+	for _, snapshot := range s.snapshots {
+		snapshots = append(snapshots, snapshot)
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].AggregateID < snapshots[j].AggregateID
+	})
+
+	if err := s.backend.Compact(records, snapshots); err != nil {
+		return types.WrapError(err, "failed to compact event store", types.InternalError)
+	}
+	s.index(records, snapshots)
+	return nil
+}
+
+// Close closes the store and its backend; subscribers stop receiving events
+func (s *Store) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	for sub := range s.subscribers {
+		sub.closed.Store(true)
+	}
+	s.subscribers = make(map[*storeSubscriber]struct{})
+	s.mu.Unlock()
+
+	return s.backend.Close()
+}
+
+// storeSubscriber delivers records to a handler in position order
+type storeSubscriber struct {
+	mu       sync.Mutex
+	pending  atomic.Bool
+	closed   atomic.Bool
+	position int64
+	handler  func(record Record)
+}
+
+// subscriberBatch bounds the records a subscriber copies per read
+const subscriberBatch = 256
+
+// Subscribe calls handler with every event after a position, 0 for all of them: first the
+// stored history on the calling goroutine, then each new event. It returns a function that
+// stops the subscription.
+func (s *Store) Subscribe(afterPosition int64, handler func(record Record)) func() {
+	sub := &storeSubscriber{position: afterPosition, handler: handler}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return func() {}
+	}
+	s.subscribers[sub] = struct{}{}
+	s.mu.Unlock()
+
+	s.catchUp(sub)
+	return func() {
+		sub.closed.Store(true)
+		s.mu.Lock()
+		delete(s.subscribers, sub)
+		s.mu.Unlock()
+	}
+}
+
+// subscriberList returns the current subscribers; callers hold s.mu
+func (s *Store) subscriberList() []*storeSubscriber {
+	subscribers := make([]*storeSubscriber, 0, len(s.subscribers))
+	for sub := range s.subscribers {
+		subscribers = append(subscribers, sub)
+	}
+	return subscribers
+}
+
+// catchUp delivers the records a subscriber has not seen. A goroutine that finds the subscriber
+// busy, including a handler appending to the store, leaves the new records to the goroutine
+// already delivering, which checks pending before it returns.
+func (s *Store) catchUp(sub *storeSubscriber) {
+	sub.pending.Store(true)
+	for sub.pending.Load() {
+		if !sub.mu.TryLock() {
+			return
+		}
+		sub.pending.Store(false)
+		for !sub.closed.Load() {
+			batch := s.readAfter(sub.position, subscriberBatch)
+			if len(batch) == 0 {
+				break
+			}
+			for _, record := range batch {
+				if sub.closed.Load() {
+					break
+				}
+				callHandler(sub.handler, record)
+				sub.position = record.Position
+			}
+		}
+		sub.mu.Unlock()
+	}
+}
+
+// callHandler calls a subscriber, reporting a panic instead of stopping the delivery
+func callHandler(handler func(record Record), record Record) {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Printf("Warning: event store subscriber panicked on event %d: %v\n", record.Position, r)
+		}
+	}()
+	handler(record)
+}
@@ -0,0 +1,77 @@
+package eventstore
+
+import (
+	"testing"
+	"time"
+
+	"typescript-golang/types"
+)
+
+type orderPlaced struct {
+	ID    string  `json:"id"`
+	Total float64 `json:"total"`
+}
+
+func TestProjectionApplyCanReadProjection(t *testing.T) {
+	s := NewMemoryStore()
+	defer s.Close()
+
+	var p *Projection[int]
+	p = NewProjection(s, func() int { return 0 }, func(count int, record Record) (int, error) {
+		if p != nil {
+			// Reading the projection from apply must not deadlock
+			_ = p.Position()
+		}
+		return count + 1, nil
+	})
+
+	appended := make(chan error, 1)
+	go func() {
+		_, err := s.Append("order-1", NoStream, types.NewEvent[interface{}]("order:placed", 1, nil))
+		appended <- err
+	}()
+	select {
+	case err := <-appended:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("apply deadlocked on the projection")
+	}
+	if p.State() != 1 || p.Position() != 1 {
+		t.Fatalf("state = %d at position %d, want 1 at 1", p.State(), p.Position())
+	}
+}
+
+func TestReplayToDecodesPayloads(t *testing.T) {
+	s := NewMemoryStore()
+	defer s.Close()
+	if _, err := s.Append("order-1", NoStream,
+		types.NewEvent[interface{}]("order:placed", orderPlaced{ID: "order-1", Total: 9.5}, nil),
+		types.NewEvent[interface{}]("order:noted", map[string]interface{}{"note": "gift"}, nil),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	bus := types.NewEventBus()
+	types.RegisterTopic(bus, types.NewEventKey[orderPlaced]("order:placed"))
+	payloads := make(chan interface{}, 2)
+	bus.Handle("order:*", func(msg *types.BusMessage) error {
+		if msg.Headers[ReplayHeader] != "true" {
+			t.Errorf("event %s was not marked as replayed", msg.Headers[PositionHeader])
+		}
+		payloads <- msg.Payload
+		return nil
+	})
+	if err := s.ReplayTo(bus, 0); err != nil {
+		t.Fatal(err)
+	}
+	bus.Close()
+
+	if placed, ok := (<-payloads).(orderPlaced); !ok || placed.Total != 9.5 {
+		t.Fatalf("registered payload decoded as %#v", placed)
+	}
+	if noted, ok := (<-payloads).(map[string]interface{}); !ok || noted["note"] != "gift" {
+		t.Fatalf("unregistered payload decoded as %#v", noted)
+	}
+}
@@ -1,5 +1,3 @@
 module typescript-golang
 
-go 1.18
-
-require ()
\ No newline at end of file
+go 1.20
@@ -0,0 +1,140 @@
+package streams
+
+import (
+	"bytes"
+	"io"
+
+	"typescript-golang/async"
+)
+
+// defaultChunkSize is the read size of FromReader
+const defaultChunkSize = 32 * 1024
+
+// FromSlice creates a stream that emits the items in order and then closes (like ReadableStream.from() in TypeScript)
+func FromSlice[T any](items []T) *ReadableStream[T] {
+	index := 0
+	return NewReadableStream(UnderlyingSource[T]{
+		Pull: func(c *ReadableStreamController[T]) error {
+			if index >= len(items) {
+				return c.Close()
+			}
+			index++
+			return c.Enqueue(items[index-1])
+		},
+	})
+}
+
+// FromReader creates a byte stream that reads r in chunks of up to chunkSize bytes (32 KiB by
+// default) as the consumer pulls. The stream closes at io.EOF; r is not closed.
+func FromReader(r io.Reader, chunkSize ...int) *ReadableStream[[]byte] {
+	size := defaultChunkSize
+	if len(chunkSize) > 0 && chunkSize[0] > 0 {
+		size = chunkSize[0]
+	}
+	return NewReadableStream(UnderlyingSource[[]byte]{
+		Pull: func(c *ReadableStreamController[[]byte]) error {
+			buf := make([]byte, size)
+			n, err := r.Read(buf)
+			if n > 0 {
+				if enqueueErr := c.Enqueue(buf[:n]); enqueueErr != nil {
+					return enqueueErr
+				}
+			}
+			if err == io.EOF {
+				return c.Close()
+			}
+			return err
+		},
+	})
+}
+
+// ToWriter creates a byte stream that writes every chunk to w. w is not closed when the stream closes.
+func ToWriter(w io.Writer) *WritableStream[[]byte] {
+	return NewWritableStream(UnderlyingSink[[]byte]{
+		Write: func(chunk []byte, c *WritableStreamController) error {
+			_, err := w.Write(chunk)
+			return err
+		},
+	})
+}
+
+// SplitLines creates a transform that decodes byte chunks into lines without their "\n" or
+// "\r\n" terminators. A final line without a terminator is emitted when the input closes.
+func SplitLines() *TransformStream[[]byte, string] {
+	var pending []byte
+	return NewTransformStream(Transformer[[]byte, string]{
+		Transform: func(chunk []byte, c *TransformStreamController[string]) error {
+			pending = append(pending, chunk...)
+			for {
+				i := bytes.IndexByte(pending, '\n')
+				if i < 0 {
+					return nil
+				}
+				line := bytes.TrimSuffix(pending[:i], []byte("\r"))
+				if err := c.Enqueue(string(line)); err != nil {
+					return err
+				}
+				pending = pending[i+1:]
+			}
+		},
+		Flush: func(c *TransformStreamController[string]) error {
+			if len(pending) == 0 {
+				return nil
+			}
+			return c.Enqueue(string(bytes.TrimSuffix(pending, []byte("\r"))))
+		},
+	})
+}
+
+// Lines creates a stream of the lines read from r
+func Lines(r io.Reader) *ReadableStream[string] {
+	return PipeThrough(FromReader(r), SplitLines())
+}
+
+// Map creates a transform that converts every chunk with fn
+func Map[I, O any](fn func(chunk I) (O, error)) *TransformStream[I, O] {
+	return NewTransformStream(Transformer[I, O]{
+		Transform: func(chunk I, c *TransformStreamController[O]) error {
+			out, err := fn(chunk)
+			if err != nil {
+				return err
+			}
+			return c.Enqueue(out)
+		},
+	})
+}
+
+// Filter creates a transform that only passes chunks for which predicate returns true
+func Filter[T any](predicate func(chunk T) bool) *TransformStream[T, T] {
+	return NewTransformStream(Transformer[T, T]{
+		Transform: func(chunk T, c *TransformStreamController[T]) error {
+			if predicate(chunk) {
+				return c.Enqueue(chunk)
+			}
+			return nil
+		},
+	})
+}
+
+// Collect reads the whole stream into a slice
+func Collect[T any](s *ReadableStream[T]) *async.Promise[[]T] {
+	return async.NewPromise(func() ([]T, error) {
+		reader, err := s.GetReader()
+		if err != nil {
+			return nil, err
+		}
+		defer reader.ReleaseLock()
+
+		var items []T
+		for {
+			result, err := s.read(nil, nil)
+			if err != nil {
+				return items, err
+			}
+			if result.Done {
+				return items, nil
+			}
+			items = append(items, result.Value)
+		}
+	})
+}
@@ -0,0 +1,30 @@
+package streams
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSplitLinesAcrossChunks(t *testing.T) {
+	chunks := [][]byte{[]byte("fir"), []byte("st\r\nsec"), []byte("ond\n\nthi"), []byte("rd")}
+	lines, err := Collect(PipeThrough(FromSlice(chunks), SplitLines())).AwaitWithTimeout(time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"first", "second", "", "third"}
+	if strings.Join(lines, "|") != strings.Join(want, "|") {
+		t.Fatalf("lines %q, want %q", lines, want)
+	}
+}
+
+func TestLinesReadsInSmallChunks(t *testing.T) {
+	input := "alpha\nbeta\r\ngamma\n"
+	lines, err := Collect(PipeThrough(FromReader(strings.NewReader(input), 3), SplitLines())).AwaitWithTimeout(time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(lines, ",") != "alpha,beta,gamma" {
+		t.Fatalf("lines %q", lines)
+	}
+}
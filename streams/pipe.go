@@ -0,0 +1,215 @@
+package streams
+
+import (
+	"sync"
+
+	"typescript-golang/async"
+	"typescript-golang/types"
+)
+
+// PipeOptions configures PipeTo and PipeThrough (like StreamPipeOptions in TypeScript)
+type PipeOptions struct {
+	// PreventClose leaves the destination open when the source closes
+	PreventClose bool
+	// PreventAbort leaves the destination unaborted when the source errors
+	PreventAbort bool
+	// PreventCancel leaves the source uncancelled when the destination errors
+	PreventCancel bool
+	// Signal aborts the pipe, aborting the destination and cancelling the source unless prevented
+	Signal *async.AbortSignal
+}
+
+// PipeTo pipes every chunk to dest, respecting dest's backpressure (like stream.pipeTo() in
+// TypeScript). Both streams are locked while piping. The promise resolves once the source has
+// closed and dest has been closed, and rejects with the error that stopped the pipe.
+func (s *ReadableStream[T]) PipeTo(dest *WritableStream[T], options ...PipeOptions) *async.Promise[interface{}] {
+	var o PipeOptions
+	if len(options) > 0 {
+		o = options[0]
+	}
+	return async.NewPromise(func() (interface{}, error) {
+		return nil, s.pipe(dest, o)
+	})
+}
+
+// pipe implements PipeTo on the calling goroutine
+func (s *ReadableStream[T]) pipe(dest *WritableStream[T], o PipeOptions) error {
+	reader, err := s.GetReader()
+	if err != nil {
+		return err
+	}
+	defer reader.ReleaseLock()
+	writer, err := dest.GetWriter()
+	if err != nil {
+		return err
+	}
+	defer writer.ReleaseLock()
+
+	// stop aborts when the caller's signal aborts or dest closes or errors underneath the pipe
+	stop := async.NewAbortController()
+	if o.Signal != nil {
+		defer o.Signal.OnAbort(func(reason error) {
+			stop.Abort(reason)
+		})()
+	}
+	piping := make(chan struct{})
+	defer close(piping)
+	go func() {
+		select {
+		case <-dest.finished:
+			stop.Abort(ErrStreamClosed)
+		case <-piping:
+		}
+	}()
+	signal := stop.Signal()
+
+	// stopped handles the pipe being stopped by the signal or by dest
+	stopped := func() error {
+		dest.mu.Lock()
+		destErr, destFinished := dest.err, dest.state != streamOpen
+		dest.mu.Unlock()
+
+		if destFinished {
+			if destErr == nil {
+				destErr = ErrStreamClosed
+			}
+			if !o.PreventCancel {
+				s.cancel(destErr)
+			}
+			return destErr
+		}
+
+		reason := signal.Reason()
+		if !o.PreventAbort {
+			dest.abort(reason)
+		}
+		if !o.PreventCancel {
+			s.cancel(reason)
+		}
+		return reason
+	}
+
+	var lastWrite *async.Promise[interface{}]
+	for {
+		if err := dest.waitReady(signal.Done(), signal.Reason); err != nil {
+			return stopped()
+		}
+
+		result, err := s.read(signal.Done(), signal.Reason)
+		if err != nil {
+			if signal.Aborted() {
+				return stopped()
+			}
+			// The source errored
+			if !o.PreventAbort {
+				dest.abort(err)
+			}
+			return err
+		}
+
+		if result.Done {
+			if lastWrite != nil {
+				if _, err := lastWrite.Await(); err != nil {
+					return err
+				}
+			}
+			if o.PreventClose {
+				return nil
+			}
+			_, err := dest.close().Await()
+			return err
+		}
+		lastWrite = dest.write(result.Value)
+	}
+}
+
+// PipeThrough pipes the stream into transform's writable side in the background and returns
+// its readable side (like stream.pipeThrough() in TypeScript). Errors propagate through the
+// returned stream.
+func PipeThrough[I, O any](s *ReadableStream[I], transform *TransformStream[I, O], options ...PipeOptions) *ReadableStream[O] {
+	var o PipeOptions
+	if len(options) > 0 {
+		o = options[0]
+	}
+	go func() {
+		if err := s.pipe(transform.writable, o); err == ErrStreamLocked {
+			transform.writable.abort(err)
+		}
+	}()
+	return transform.readable
+}
+
+// Tee splits the stream into two branches that each receive every chunk (like stream.tee() in
+// TypeScript). The stream is locked to the branches. Each branch queues up to the optional
+// strategy's high-water mark (one chunk by default), and the source is only read once both
+// branches have room, so the slower branch sets the pace; cancel a branch that is no longer
+// read, or the other one stalls. The source is only cancelled once both branches are.
+func (s *ReadableStream[T]) Tee(strategy ...QueuingStrategy[T]) (*ReadableStream[T], *ReadableStream[T], error) {
+	reader, err := s.GetReader()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		pullMu      sync.Mutex
+		cancelMu    sync.Mutex
+		cancelled   [2]bool
+		reasons     = make([]error, 2)
+		controllers [2]*ReadableStreamController[T]
+		created     = make(chan struct{})
+	)
+
+	pull := func(*ReadableStreamController[T]) error {
+		// The first branch may pull before the second one exists
+		<-created
+		pullMu.Lock()
+		defer pullMu.Unlock()
+
+		// Wait for room in both branches; a cancelled or closed branch no longer has demand
+		for _, controller := range controllers {
+			controller.stream.waitForDemand(nil, nil)
+		}
+		result, err := reader.Read().Await()
+		cancelMu.Lock()
+		defer cancelMu.Unlock()
+		for i, controller := range controllers {
+			switch {
+			case cancelled[i]:
+			case err != nil:
+				controller.Error(err)
+			case result.Done:
+				controller.Close()
+			default:
+				controller.Enqueue(result.Value)
+			}
+		}
+		return nil
+	}
+
+	branch := func(i int) *ReadableStream[T] {
+		return NewReadableStream(UnderlyingSource[T]{
+			Start: func(c *ReadableStreamController[T]) error {
+				controllers[i] = c
+				return nil
+			},
+			Pull: pull,
+			Cancel: func(reason error) error {
+				cancelMu.Lock()
+				cancelled[i] = true
+				reasons[i] = reason
+				both := cancelled[0] && cancelled[1]
+				cancelMu.Unlock()
+
+				if both {
+					_, err := reader.Cancel(types.NewAggregateError("both tee branches were cancelled", reasons, types.CancelledError)).Await()
+					return err
+				}
+				return nil
+			},
+		}, strategy...)
+	}
+
+	first, second := branch(0), branch(1)
+	close(created)
+	return first, second, nil
+}
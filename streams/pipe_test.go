@@ -0,0 +1,196 @@
+package streams
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"typescript-golang/async"
+	"typescript-golang/types"
+)
+
+// abortedAfter returns a signal that aborts after a delay
+func abortedAfter(delay time.Duration) *async.AbortSignal {
+	controller := async.NewAbortController()
+	time.AfterFunc(delay, func() { controller.Abort() })
+	return controller.Signal()
+}
+
+// recordingSink records the chunks written to it and how it was finished
+type recordingSink struct {
+	mu      sync.Mutex
+	chunks  []int
+	closed  bool
+	aborted error
+}
+
+func (r *recordingSink) stream(strategy ...QueuingStrategy[int]) *WritableStream[int] {
+	return NewWritableStream(UnderlyingSink[int]{
+		Write: func(chunk int, c *WritableStreamController) error {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.chunks = append(r.chunks, chunk)
+			return nil
+		},
+		Close: func() error {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.closed = true
+			return nil
+		},
+		Abort: func(reason error) error {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.aborted = reason
+			return nil
+		},
+	}, strategy...)
+}
+
+// endless returns a source that never ends and records whether it was cancelled
+func endless(cancelled *atomic.Value) *ReadableStream[int] {
+	var pulls atomic.Int32
+	source := counter(&pulls)
+	source.Cancel = func(reason error) error {
+		cancelled.Store(reason)
+		return nil
+	}
+	return NewReadableStream(source)
+}
+
+func TestPipeToClosesDestination(t *testing.T) {
+	sink := &recordingSink{}
+	if _, err := FromSlice([]int{1, 2, 3}).PipeTo(sink.stream()).AwaitWithTimeout(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if !sink.closed || len(sink.chunks) != 3 {
+		t.Fatalf("chunks %v, closed %v", sink.chunks, sink.closed)
+	}
+}
+
+func TestPipeToSignalAbortsBothEnds(t *testing.T) {
+	var cancelled atomic.Value
+	sink := &recordingSink{}
+	reason := types.NewError("stop", types.CancelledError)
+	controller := async.NewAbortController()
+
+	pipe := endless(&cancelled).PipeTo(sink.stream(), PipeOptions{Signal: controller.Signal()})
+	settle()
+	controller.Abort(reason)
+
+	if _, err := pipe.AwaitWithTimeout(time.Second); err != reason {
+		t.Fatalf("pipe rejected with %v", err)
+	}
+	if sink.aborted != reason || cancelled.Load() != reason {
+		t.Fatalf("dest aborted with %v, source cancelled with %v", sink.aborted, cancelled.Load())
+	}
+}
+
+func TestPipeToPreventCancelAndAbort(t *testing.T) {
+	var cancelled atomic.Value
+	sink := &recordingSink{}
+	controller := async.NewAbortController()
+
+	pipe := endless(&cancelled).PipeTo(sink.stream(), PipeOptions{Signal: controller.Signal(), PreventAbort: true, PreventCancel: true})
+	settle()
+	controller.Abort()
+
+	if _, err := pipe.AwaitWithTimeout(time.Second); err == nil {
+		t.Fatal("pipe resolved after abort")
+	}
+	if sink.aborted != nil || cancelled.Load() != nil {
+		t.Fatalf("dest aborted with %v, source cancelled with %v", sink.aborted, cancelled.Load())
+	}
+}
+
+func TestPipeToDestinationErrorCancelsSource(t *testing.T) {
+	var cancelled atomic.Value
+	failure := errors.New("disk full")
+	dest := NewWritableStream(UnderlyingSink[int]{
+		Write: func(chunk int, c *WritableStreamController) error {
+			if chunk == 2 {
+				return failure
+			}
+			return nil
+		},
+	})
+
+	if _, err := endless(&cancelled).PipeTo(dest).AwaitWithTimeout(time.Second); err != failure {
+		t.Fatalf("pipe rejected with %v", err)
+	}
+	if cancelled.Load() != failure {
+		t.Fatalf("source cancelled with %v", cancelled.Load())
+	}
+}
+
+func TestPipeToSourceErrorAbortsDestination(t *testing.T) {
+	failure := errors.New("read failed")
+	sink := &recordingSink{}
+	source := NewReadableStream(UnderlyingSource[int]{
+		Pull: func(c *ReadableStreamController[int]) error { return failure },
+	})
+
+	if _, err := source.PipeTo(sink.stream()).AwaitWithTimeout(time.Second); err != failure {
+		t.Fatalf("pipe rejected with %v", err)
+	}
+	if sink.aborted != failure {
+		t.Fatalf("dest aborted with %v", sink.aborted)
+	}
+}
+
+func TestTeeDeliversEveryChunkToBothBranches(t *testing.T) {
+	left, right, err := FromSlice([]int{1, 2, 3, 4}).Tee()
+	if err != nil {
+		t.Fatal(err)
+	}
+	leftValues, rightValues := Collect(left), Collect(right)
+	for _, p := range []*async.Promise[[]int]{leftValues, rightValues} {
+		values, err := p.AwaitWithTimeout(time.Second)
+		if err != nil || len(values) != 4 || values[3] != 4 {
+			t.Fatalf("branch values %v, %v", values, err)
+		}
+	}
+}
+
+func TestTeeSlowBranchAppliesBackpressure(t *testing.T) {
+	var pulls atomic.Int32
+	left, right, _ := NewReadableStream(counter(&pulls)).Tee(CountQueuingStrategy[int](2))
+	reader, _ := left.GetReader()
+	for i := 0; i < 2; i++ {
+		if _, err := reader.Read().AwaitWithTimeout(time.Second); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pending := reader.Read()
+	settle()
+	// The unread right branch holds at most its high-water mark, so the source stops
+	if n := pulls.Load(); n > 4 {
+		t.Fatalf("source pulled %d chunks with an unread branch", n)
+	}
+	if !pending.IsPending() {
+		t.Fatal("fast branch ran ahead of the unread branch's high-water mark")
+	}
+
+	right.Cancel()
+	if _, err := pending.AwaitWithTimeout(time.Second); err != nil {
+		t.Fatalf("fast branch stalled after the slow branch was cancelled: %v", err)
+	}
+}
+
+func TestTeeCancelsSourceOnlyWhenBothBranchesCancel(t *testing.T) {
+	var cancelled atomic.Value
+	left, right, _ := endless(&cancelled).Tee()
+
+	left.Cancel().AwaitWithTimeout(time.Second)
+	if cancelled.Load() != nil {
+		t.Fatal("source cancelled with one branch left")
+	}
+	right.Cancel().AwaitWithTimeout(time.Second)
+	var reason *types.AggregateError
+	if err, _ := cancelled.Load().(error); !errors.As(err, &reason) || len(reason.Errors()) != 2 {
+		t.Fatalf("source cancelled with %v", cancelled.Load())
+	}
+}
@@ -0,0 +1,470 @@
+package streams
+
+import (
+	"context"
+	"sync"
+
+	"typescript-golang/async"
+)
+
+// UnderlyingSource produces the chunks of a ReadableStream (like the underlyingSource object
+// in TypeScript). Every callback is optional.
+type UnderlyingSource[T any] struct {
+	// Start is called once, synchronously, when the stream is created. A push source can
+	// keep the controller and enqueue chunks from its own goroutine.
+	Start func(controller *ReadableStreamController[T]) error
+	// Pull is called on a background goroutine whenever the queue is below the high-water
+	// mark or a read is waiting, and again after it returns while that is still the case.
+	// It should enqueue at least one chunk or close the stream.
+	Pull func(controller *ReadableStreamController[T]) error
+	// Cancel is called when the consumer cancels the stream
+	Cancel func(reason error) error
+}
+
+// queuedChunk is a chunk waiting in a stream's queue together with its measured size
+type queuedChunk[T any] struct {
+	value T
+	size  int
+}
+
+type streamState int
+
+const (
+	streamOpen streamState = iota
+	streamClosed
+	streamErrored
+)
+
+// ReadableStream is a source of chunks with pull-based backpressure (like ReadableStream in TypeScript)
+type ReadableStream[T any] struct {
+	mu             sync.Mutex
+	source         UnderlyingSource[T]
+	controller     *ReadableStreamController[T]
+	highWaterMark  int
+	size           func(T) int
+	queue          []queuedChunk[T]
+	queueSize      int
+	state          streamState
+	err            error
+	closeRequested bool
+	started        bool
+	pulling        bool
+	pendingReads   int
+	locked         bool
+	// changed is closed and replaced whenever the stream's state or queue changes
+	changed chan struct{}
+	// finished is closed once the stream is closed or errored
+	finished chan struct{}
+}
+
+// NewReadableStream creates a ReadableStream (like new ReadableStream() in TypeScript). The
+// optional strategy defaults to a high-water mark of one chunk.
+func NewReadableStream[T any](source UnderlyingSource[T], strategy ...QueuingStrategy[T]) *ReadableStream[T] {
+	s := &ReadableStream[T]{
+		source:   source,
+		changed:  make(chan struct{}),
+		finished: make(chan struct{}),
+	}
+	s.highWaterMark, s.size = resolveStrategy(strategy, 1)
+	s.controller = &ReadableStreamController[T]{stream: s}
+
+	if source.Start != nil {
+		if err := guard(func() error { return source.Start(s.controller) }); err != nil {
+			s.controller.Error(err)
+			return s
+		}
+	}
+
+	s.mu.Lock()
+	s.started = true
+	s.mu.Unlock()
+	s.maybePull()
+	return s
+}
+
+// notify wakes everything waiting for a change. Must be called with s.mu held.
+func (s *ReadableStream[T]) notify() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// finishClose moves the stream to the closed state. Must be called with s.mu held.
+func (s *ReadableStream[T]) finishClose() {
+	s.state = streamClosed
+	close(s.finished)
+	s.notify()
+}
+
+// desiredSize returns how much the queue can grow before reaching the high-water mark.
+// Must be called with s.mu held.
+func (s *ReadableStream[T]) desiredSize() int {
+	if s.state != streamOpen {
+		return 0
+	}
+	return s.highWaterMark - s.queueSize
+}
+
+// shouldPull reports whether the source should be asked for more chunks. Must be called with s.mu held.
+func (s *ReadableStream[T]) shouldPull() bool {
+	if !s.started || s.state != streamOpen || s.closeRequested {
+		return false
+	}
+	return s.hasWaitingReads() || s.desiredSize() > 0
+}
+
+// hasWaitingReads reports whether more reads are waiting than there are queued chunks. Must be
+// called with s.mu held.
+func (s *ReadableStream[T]) hasWaitingReads() bool {
+	return s.pendingReads > len(s.queue)
+}
+
+// maybePull starts the pull loop if the source wants to be pulled and is not already being pulled
+func (s *ReadableStream[T]) maybePull() {
+	s.mu.Lock()
+	if s.source.Pull == nil || s.pulling || !s.shouldPull() {
+		s.mu.Unlock()
+		return
+	}
+	s.pulling = true
+	s.mu.Unlock()
+
+	go func() {
+		for {
+			if err := guard(func() error { return s.source.Pull(s.controller) }); err != nil {
+				s.controller.Error(err)
+			}
+
+			s.mu.Lock()
+			if !s.shouldPull() {
+				s.pulling = false
+				s.mu.Unlock()
+				return
+			}
+			s.mu.Unlock()
+		}
+	}()
+}
+
+// read takes the next chunk, waiting until one is available, the stream closes or errors, or
+// cancel is closed. A chunk is only dequeued when it is returned.
+func (s *ReadableStream[T]) read(cancel <-chan struct{}, reason func() error) (async.IteratorResult[T], error) {
+	s.mu.Lock()
+	s.pendingReads++
+	s.notify()
+	s.mu.Unlock()
+	s.maybePull()
+
+	for {
+		s.mu.Lock()
+		if len(s.queue) > 0 {
+			chunk := s.queue[0]
+			s.queue[0] = queuedChunk[T]{}
+			s.queue = s.queue[1:]
+			s.queueSize -= chunk.size
+			s.pendingReads--
+			if s.closeRequested && len(s.queue) == 0 {
+				s.finishClose()
+			} else {
+				s.notify()
+			}
+			s.mu.Unlock()
+			s.maybePull()
+			return async.IteratorResult[T]{Value: chunk.value}, nil
+		}
+		if s.state != streamOpen {
+			// err is nil for a closed stream
+			err := s.err
+			s.pendingReads--
+			s.mu.Unlock()
+			return async.IteratorResult[T]{Done: true}, err
+		}
+		changed := s.changed
+		s.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-cancel:
+			s.mu.Lock()
+			s.pendingReads--
+			s.notify()
+			s.mu.Unlock()
+			return async.IteratorResult[T]{}, reason()
+		}
+	}
+}
+
+// waitForDemand blocks until a chunk would be consumed without exceeding the high-water mark
+// or a read is waiting. It fails if the stream is no longer readable or cancel is closed.
+func (s *ReadableStream[T]) waitForDemand(cancel <-chan struct{}, reason func() error) error {
+	for {
+		s.mu.Lock()
+		switch {
+		case s.state == streamErrored:
+			err := s.err
+			s.mu.Unlock()
+			return err
+		case s.state == streamClosed || s.closeRequested:
+			s.mu.Unlock()
+			return ErrStreamClosed
+		case s.hasWaitingReads() || s.desiredSize() > 0:
+			s.mu.Unlock()
+			return nil
+		}
+		changed := s.changed
+		s.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-cancel:
+			return reason()
+		}
+	}
+}
+
+// cancel empties and closes the stream, then calls the source's Cancel
+func (s *ReadableStream[T]) cancel(reason error) error {
+	s.mu.Lock()
+	switch s.state {
+	case streamClosed:
+		s.mu.Unlock()
+		return nil
+	case streamErrored:
+		err := s.err
+		s.mu.Unlock()
+		return err
+	}
+	s.queue = nil
+	s.queueSize = 0
+	s.finishClose()
+	s.mu.Unlock()
+
+	if s.source.Cancel != nil {
+		return guard(func() error { return s.source.Cancel(reason) })
+	}
+	return nil
+}
+
+// closedPromise returns a promise that settles when the stream closes or errors
+func (s *ReadableStream[T]) closedPromise() *async.Promise[interface{}] {
+	return async.Lazy(func() (interface{}, error) {
+		<-s.finished
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return nil, s.err
+	})
+}
+
+// lock acquires the stream's single reader lock
+func (s *ReadableStream[T]) lock() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.locked {
+		return ErrStreamLocked
+	}
+	s.locked = true
+	return nil
+}
+
+// unlock releases the stream's reader lock
+func (s *ReadableStream[T]) unlock() {
+	s.mu.Lock()
+	s.locked = false
+	s.mu.Unlock()
+}
+
+// Locked reports whether the stream has a reader (like stream.locked in TypeScript)
+func (s *ReadableStream[T]) Locked() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.locked
+}
+
+// GetReader locks the stream to a new reader (like stream.getReader() in TypeScript)
+func (s *ReadableStream[T]) GetReader() (*ReadableStreamReader[T], error) {
+	if err := s.lock(); err != nil {
+		return nil, err
+	}
+	return &ReadableStreamReader[T]{stream: s}, nil
+}
+
+// Cancel discards queued chunks and closes the stream, signalling the source that the
+// consumer lost interest (like stream.cancel() in TypeScript). It fails if the stream is locked.
+func (s *ReadableStream[T]) Cancel(reason ...error) *async.Promise[interface{}] {
+	if s.Locked() {
+		return async.Reject[interface{}](ErrStreamLocked)
+	}
+	r := cancelReason("stream cancelled", reason)
+	return async.NewPromise(func() (interface{}, error) {
+		return nil, s.cancel(r)
+	})
+}
+
+// Values locks the stream and returns it as an async iterator for async.ForAwait (like
+// stream.values() in TypeScript). Return cancels the stream and releases the lock.
+func (s *ReadableStream[T]) Values() (async.AsyncIterator[T], error) {
+	reader, err := s.GetReader()
+	if err != nil {
+		return nil, err
+	}
+	return &streamIterator[T]{reader: reader}, nil
+}
+
+// ReadableStreamController lets an UnderlyingSource enqueue chunks, close or error its stream
+// (like ReadableStreamDefaultController in TypeScript)
+type ReadableStreamController[T any] struct {
+	stream *ReadableStream[T]
+}
+
+// Enqueue adds a chunk to the stream's queue. It fails if the stream is closed or closing.
+func (c *ReadableStreamController[T]) Enqueue(chunk T) error {
+	s := c.stream
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state == streamErrored {
+		return s.err
+	}
+	if s.state != streamOpen || s.closeRequested {
+		return ErrStreamClosed
+	}
+	size := s.size(chunk)
+	s.queue = append(s.queue, queuedChunk[T]{value: chunk, size: size})
+	s.queueSize += size
+	s.notify()
+	return nil
+}
+
+// Close closes the stream once the queued chunks have been read
+func (c *ReadableStreamController[T]) Close() error {
+	s := c.stream
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state != streamOpen || s.closeRequested {
+		return ErrStreamClosed
+	}
+	s.closeRequested = true
+	if len(s.queue) == 0 {
+		s.finishClose()
+	} else {
+		s.notify()
+	}
+	return nil
+}
+
+// Error errors the stream, discarding queued chunks; pending and future reads fail with err
+func (c *ReadableStreamController[T]) Error(err error) {
+	s := c.stream
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state != streamOpen {
+		return
+	}
+	s.state = streamErrored
+	s.err = err
+	s.queue = nil
+	s.queueSize = 0
+	close(s.finished)
+	s.notify()
+}
+
+// DesiredSize returns how many more units can be enqueued before reaching the high-water
+// mark; it is zero or negative when the consumer is not keeping up
+func (c *ReadableStreamController[T]) DesiredSize() int {
+	c.stream.mu.Lock()
+	defer c.stream.mu.Unlock()
+	return c.stream.desiredSize()
+}
+
+// ReadableStreamReader reads chunks from a locked stream (like ReadableStreamDefaultReader in TypeScript)
+type ReadableStreamReader[T any] struct {
+	mu       sync.Mutex
+	stream   *ReadableStream[T]
+	released bool
+}
+
+// active returns the reader's stream, or an error if the lock was released
+func (r *ReadableStreamReader[T]) active() (*ReadableStream[T], error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.released {
+		return nil, ErrLockReleased
+	}
+	return r.stream, nil
+}
+
+// Read resolves with the next chunk, or with Done set once the stream has closed (like
+// reader.read() in TypeScript). It rejects with the stream's error if it errored, or with the
+// abort reason if the optional signal aborts first; no chunk is lost on abort.
+func (r *ReadableStreamReader[T]) Read(signal ...*async.AbortSignal) *async.Promise[async.IteratorResult[T]] {
+	s, err := r.active()
+	if err != nil {
+		return async.Reject[async.IteratorResult[T]](err)
+	}
+
+	var cancel <-chan struct{}
+	reason := func() error { return nil }
+	if len(signal) > 0 && signal[0] != nil {
+		cancel = signal[0].Done()
+		reason = signal[0].Reason
+	}
+	return async.NewPromise(func() (async.IteratorResult[T], error) {
+		return s.read(cancel, reason)
+	})
+}
+
+// Cancel cancels the stream through the reader (like reader.cancel() in TypeScript)
+func (r *ReadableStreamReader[T]) Cancel(reason ...error) *async.Promise[interface{}] {
+	s, err := r.active()
+	if err != nil {
+		return async.Reject[interface{}](err)
+	}
+	cause := cancelReason("stream cancelled", reason)
+	return async.NewPromise(func() (interface{}, error) {
+		return nil, s.cancel(cause)
+	})
+}
+
+// Closed returns a promise that resolves when the stream closes or rejects if it errors
+// (like reader.closed in TypeScript)
+func (r *ReadableStreamReader[T]) Closed() *async.Promise[interface{}] {
+	return r.stream.closedPromise()
+}
+
+// ReleaseLock unlocks the stream so another reader can be acquired (like reader.releaseLock() in TypeScript)
+func (r *ReadableStreamReader[T]) ReleaseLock() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.released {
+		r.released = true
+		r.stream.unlock()
+	}
+}
+
+// streamIterator adapts a reader to async.AsyncIterator
+type streamIterator[T any] struct {
+	reader *ReadableStreamReader[T]
+}
+
+func (it *streamIterator[T]) Next(ctx context.Context) *async.Promise[async.IteratorResult[T]] {
+	s, err := it.reader.active()
+	if err != nil {
+		return async.Resolve(async.IteratorResult[T]{Done: true})
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return async.NewPromise(func() (async.IteratorResult[T], error) {
+		return s.read(ctx.Done(), ctx.Err)
+	})
+}
+
+func (it *streamIterator[T]) Return() *async.Promise[async.IteratorResult[T]] {
+	return async.NewPromise(func() (async.IteratorResult[T], error) {
+		s, err := it.reader.active()
+		if err != nil {
+			return async.IteratorResult[T]{Done: true}, nil
+		}
+		err = s.cancel(cancelReason("iteration stopped", nil))
+		it.reader.ReleaseLock()
+		return async.IteratorResult[T]{Done: true}, err
+	})
+}
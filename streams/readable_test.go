@@ -0,0 +1,78 @@
+package streams
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// counter returns a source that enqueues 0, 1, 2, ... and counts its pulls
+func counter(pulls *atomic.Int32) UnderlyingSource[int] {
+	return UnderlyingSource[int]{
+		Pull: func(c *ReadableStreamController[int]) error {
+			return c.Enqueue(int(pulls.Add(1)) - 1)
+		},
+	}
+}
+
+// settle waits for background pulls and writes to reach a steady state
+func settle() {
+	time.Sleep(20 * time.Millisecond)
+}
+
+func TestReadableStopsPullingAtHighWaterMark(t *testing.T) {
+	var pulls atomic.Int32
+	s := NewReadableStream(counter(&pulls), CountQueuingStrategy[int](3))
+	settle()
+	if n := pulls.Load(); n != 3 {
+		t.Fatalf("pulled %d chunks without a reader, want the high-water mark of 3", n)
+	}
+
+	reader, err := s.GetReader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for want := 0; want < 2; want++ {
+		result, err := reader.Read().AwaitWithTimeout(time.Second)
+		if err != nil || result.Value != want {
+			t.Fatalf("read %d: %+v, %v", want, result, err)
+		}
+	}
+	settle()
+	if n := pulls.Load(); n != 5 {
+		t.Fatalf("pulled %d chunks after two reads, want 5", n)
+	}
+}
+
+func TestReadableSizeFunction(t *testing.T) {
+	var pulls atomic.Int32
+	NewReadableStream(UnderlyingSource[[]byte]{
+		Pull: func(c *ReadableStreamController[[]byte]) error {
+			pulls.Add(1)
+			return c.Enqueue(make([]byte, 4))
+		},
+	}, ByteLengthQueuingStrategy(10))
+	settle()
+	if n := pulls.Load(); n != 3 {
+		t.Fatalf("pulled %d chunks of 4 bytes for a 10-byte high-water mark, want 3", n)
+	}
+}
+
+func TestReadableReadAbortKeepsChunk(t *testing.T) {
+	var controller *ReadableStreamController[int]
+	s := NewReadableStream(UnderlyingSource[int]{
+		Start: func(c *ReadableStreamController[int]) error {
+			controller = c
+			return nil
+		},
+	})
+	reader, _ := s.GetReader()
+
+	if _, err := reader.Read(abortedAfter(10 * time.Millisecond)).AwaitWithTimeout(time.Second); err == nil {
+		t.Fatal("read did not reject on abort")
+	}
+	controller.Enqueue(1)
+	if result, err := reader.Read().AwaitWithTimeout(time.Second); err != nil || result.Value != 1 {
+		t.Fatalf("chunk after aborted read: %+v, %v", result, err)
+	}
+}
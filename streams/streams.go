@@ -0,0 +1,72 @@
+package streams
+
+import (
+	"errors"
+	"fmt"
+
+	"typescript-golang/types"
+)
+
+var (
+	// ErrStreamLocked is returned when a reader or writer is requested for a stream that already has one
+	ErrStreamLocked = errors.New("stream is locked")
+	// ErrStreamClosed is returned when enqueuing to or writing to a stream that is closed or closing
+	ErrStreamClosed = errors.New("stream is closed")
+	// ErrLockReleased rejects reads and writes through a reader or writer whose lock was released
+	ErrLockReleased = errors.New("reader or writer lock was released")
+)
+
+// QueuingStrategy controls backpressure (like QueuingStrategy in the Streams API). A stream
+// signals backpressure once the total size of its queued chunks reaches HighWaterMark.
+type QueuingStrategy[T any] struct {
+	HighWaterMark int
+	// Size measures a chunk (default 1 per chunk)
+	Size func(chunk T) int
+}
+
+// CountQueuingStrategy counts every chunk as 1 (like new CountQueuingStrategy() in TypeScript)
+func CountQueuingStrategy[T any](highWaterMark int) QueuingStrategy[T] {
+	return QueuingStrategy[T]{HighWaterMark: highWaterMark}
+}
+
+// ByteLengthQueuingStrategy measures byte chunks by length (like new ByteLengthQueuingStrategy() in TypeScript)
+func ByteLengthQueuingStrategy(highWaterMark int) QueuingStrategy[[]byte] {
+	return QueuingStrategy[[]byte]{
+		HighWaterMark: highWaterMark,
+		Size:          func(chunk []byte) int { return len(chunk) },
+	}
+}
+
+// resolveStrategy returns the high-water mark and size function of the optional strategy
+func resolveStrategy[T any](strategy []QueuingStrategy[T], defaultHighWaterMark int) (int, func(T) int) {
+	highWaterMark := defaultHighWaterMark
+	size := func(T) int { return 1 }
+	if len(strategy) > 0 {
+		highWaterMark = strategy[0].HighWaterMark
+		if strategy[0].Size != nil {
+			size = strategy[0].Size
+		}
+	}
+	if highWaterMark < 0 {
+		highWaterMark = 0
+	}
+	return highWaterMark, size
+}
+
+// cancelReason returns the optional reason or a default CancelledError
+func cancelReason(message string, reason []error) error {
+	if len(reason) > 0 && reason[0] != nil {
+		return reason[0]
+	}
+	return types.NewError(message, types.CancelledError)
+}
+
+// guard calls a user callback, converting a panic into an error
+func guard(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = types.NewError(fmt.Sprintf("panic: %v", r), types.InternalError).WithData("panic", r)
+		}
+	}()
+	return fn()
+}
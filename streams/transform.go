@@ -0,0 +1,135 @@
+package streams
+
+import (
+	"fmt"
+
+	"typescript-golang/types"
+)
+
+// Transformer converts chunks written to a TransformStream into chunks read from it (like the
+// transformer object in TypeScript). Every callback is optional; without Transform, chunks are
+// passed through unchanged, which requires I and O to be the same type.
+type Transformer[I, O any] struct {
+	// Start is called once, synchronously, when the stream is created
+	Start func(controller *TransformStreamController[O]) error
+	// Transform is called for one written chunk at a time and may enqueue any number of outputs
+	Transform func(chunk I, controller *TransformStreamController[O]) error
+	// Flush is called when the writable side closes, before the readable side closes
+	Flush func(controller *TransformStreamController[O]) error
+}
+
+// TransformStream is a writable/readable pair that transforms chunks on the way through (like
+// TransformStream in TypeScript). Writes wait while the readable side is full, so
+// backpressure propagates from the consumer to the producer.
+type TransformStream[I, O any] struct {
+	readable *ReadableStream[O]
+	writable *WritableStream[I]
+}
+
+// NewTransformStream creates a TransformStream (like new TransformStream() in TypeScript). The
+// optional strategy applies to the writable side and defaults to a high-water mark of one
+// chunk; the readable side has a high-water mark of zero, so chunks are only transformed when
+// they are read.
+func NewTransformStream[I, O any](transformer Transformer[I, O], writableStrategy ...QueuingStrategy[I]) *TransformStream[I, O] {
+	t := &TransformStream[I, O]{}
+	controller := &TransformStreamController[O]{}
+
+	t.readable = NewReadableStream(UnderlyingSource[O]{
+		Start: func(c *ReadableStreamController[O]) error {
+			controller.readable = c
+			return nil
+		},
+		Cancel: func(reason error) error {
+			t.writable.fail(reason)
+			return nil
+		},
+	}, CountQueuingStrategy[O](0))
+
+	transform := transformer.Transform
+	if transform == nil {
+		transform = func(chunk I, c *TransformStreamController[O]) error {
+			out, ok := interface{}(chunk).(O)
+			if !ok {
+				return types.NewError(fmt.Sprintf("transform stream cannot pass %T through without a Transform", chunk), types.ValidationError)
+			}
+			return c.Enqueue(out)
+		}
+	}
+
+	t.writable = NewWritableStream(UnderlyingSink[I]{
+		Start: func(c *WritableStreamController) error {
+			controller.writable = c
+			if transformer.Start != nil {
+				return transformer.Start(controller)
+			}
+			return nil
+		},
+		Write: func(chunk I, c *WritableStreamController) error {
+			signal := c.Signal()
+			if err := t.readable.waitForDemand(signal.Done(), signal.Reason); err != nil {
+				return err
+			}
+			if err := transform(chunk, controller); err != nil {
+				controller.readable.Error(err)
+				return err
+			}
+			return nil
+		},
+		Close: func() error {
+			if transformer.Flush != nil {
+				if err := transformer.Flush(controller); err != nil {
+					controller.readable.Error(err)
+					return err
+				}
+			}
+			controller.readable.Close()
+			return nil
+		},
+		Abort: func(reason error) error {
+			controller.readable.Error(reason)
+			return nil
+		},
+	}, writableStrategy...)
+
+	return t
+}
+
+// Readable returns the side that outputs transformed chunks (like transformStream.readable in TypeScript)
+func (t *TransformStream[I, O]) Readable() *ReadableStream[O] {
+	return t.readable
+}
+
+// Writable returns the side that accepts input chunks (like transformStream.writable in TypeScript)
+func (t *TransformStream[I, O]) Writable() *WritableStream[I] {
+	return t.writable
+}
+
+// TransformStreamController lets a Transformer enqueue output, error or terminate its stream
+// (like TransformStreamDefaultController in TypeScript)
+type TransformStreamController[O any] struct {
+	readable *ReadableStreamController[O]
+	writable *WritableStreamController
+}
+
+// Enqueue adds a chunk to the readable side
+func (c *TransformStreamController[O]) Enqueue(chunk O) error {
+	return c.readable.Enqueue(chunk)
+}
+
+// Error errors both sides of the stream
+func (c *TransformStreamController[O]) Error(err error) {
+	c.readable.Error(err)
+	c.writable.Error(err)
+}
+
+// Terminate closes the readable side and errors the writable side, e.g. once a transformer
+// has seen all the input it needs
+func (c *TransformStreamController[O]) Terminate() {
+	c.readable.Close()
+	c.writable.Error(types.NewError("transform stream terminated", types.CancelledError))
+}
+
+// DesiredSize returns the readable side's desired size
+func (c *TransformStreamController[O]) DesiredSize() int {
+	return c.readable.DesiredSize()
+}
@@ -0,0 +1,442 @@
+package streams
+
+import (
+	"sync"
+
+	"typescript-golang/async"
+)
+
+// UnderlyingSink consumes the chunks written to a WritableStream (like the underlyingSink
+// object in TypeScript). Every callback is optional.
+type UnderlyingSink[T any] struct {
+	// Start is called once, synchronously, when the stream is created
+	Start func(controller *WritableStreamController) error
+	// Write is called for one chunk at a time, in order, on a background goroutine
+	Write func(chunk T, controller *WritableStreamController) error
+	// Close is called after every queued chunk has been written
+	Close func() error
+	// Abort is called when the producer aborts the stream, after any in-flight write returns
+	Abort func(reason error) error
+}
+
+// writeRequest is a queued chunk and the resolvers of its Write promise
+type writeRequest[T any] struct {
+	chunk T
+	size  int
+	done  async.PromiseWithResolvers[interface{}]
+}
+
+// WritableStream is a destination for chunks with backpressure (like WritableStream in TypeScript)
+type WritableStream[T any] struct {
+	mu            sync.Mutex
+	sink          UnderlyingSink[T]
+	controller    *WritableStreamController
+	highWaterMark int
+	size          func(T) int
+	// queue holds unwritten chunks, starting with the one being written while processing is set
+	queue      []*writeRequest[T]
+	queueSize  int
+	closeReq   *async.PromiseWithResolvers[interface{}]
+	processing bool
+	state      streamState
+	err        error
+	locked     bool
+	changed    chan struct{}
+	finished   chan struct{}
+}
+
+// NewWritableStream creates a WritableStream (like new WritableStream() in TypeScript). The
+// optional strategy defaults to a high-water mark of one chunk.
+func NewWritableStream[T any](sink UnderlyingSink[T], strategy ...QueuingStrategy[T]) *WritableStream[T] {
+	s := &WritableStream[T]{
+		sink:     sink,
+		changed:  make(chan struct{}),
+		finished: make(chan struct{}),
+	}
+	s.highWaterMark, s.size = resolveStrategy(strategy, 1)
+	s.controller = &WritableStreamController{
+		abort: async.NewAbortController(),
+		error: s.fail,
+	}
+
+	if sink.Start != nil {
+		if err := guard(func() error { return sink.Start(s.controller) }); err != nil {
+			s.fail(err)
+		}
+	}
+	return s
+}
+
+// notify wakes everything waiting for a change. Must be called with s.mu held.
+func (s *WritableStream[T]) notify() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// desiredSize returns how much can be written before reaching the high-water mark. Must be
+// called with s.mu held.
+func (s *WritableStream[T]) desiredSize() int {
+	if s.state != streamOpen {
+		return 0
+	}
+	return s.highWaterMark - s.queueSize
+}
+
+// fail errors the stream: queued writes and a pending close reject with err, and the
+// controller's signal aborts. A write already in flight completes normally.
+func (s *WritableStream[T]) fail(err error) {
+	s.mu.Lock()
+	if s.state != streamOpen {
+		s.mu.Unlock()
+		return
+	}
+	s.state = streamErrored
+	s.err = err
+
+	var rejected []*writeRequest[T]
+	if s.processing && len(s.queue) > 0 {
+		rejected = s.queue[1:]
+		s.queue = s.queue[:1]
+		s.queueSize = s.queue[0].size
+	} else {
+		rejected = s.queue
+		s.queue = nil
+		s.queueSize = 0
+	}
+	closeReq := s.closeReq
+	s.closeReq = nil
+	close(s.finished)
+	s.notify()
+	s.mu.Unlock()
+
+	s.controller.abort.Abort(err)
+	for _, req := range rejected {
+		req.done.Reject(err)
+	}
+	if closeReq != nil {
+		closeReq.Reject(err)
+	}
+}
+
+// write queues a chunk and starts processing the queue if needed
+func (s *WritableStream[T]) write(chunk T) *async.Promise[interface{}] {
+	s.mu.Lock()
+	if s.state == streamErrored {
+		err := s.err
+		s.mu.Unlock()
+		return async.Reject[interface{}](err)
+	}
+	if s.state != streamOpen || s.closeReq != nil {
+		s.mu.Unlock()
+		return async.Reject[interface{}](ErrStreamClosed)
+	}
+
+	req := &writeRequest[T]{chunk: chunk, size: s.size(chunk), done: async.WithResolvers[interface{}]()}
+	s.queue = append(s.queue, req)
+	s.queueSize += req.size
+	s.notify()
+	s.startProcessing()
+	s.mu.Unlock()
+	return req.done.Promise
+}
+
+// startProcessing starts the goroutine that drains the queue. Must be called with s.mu held.
+func (s *WritableStream[T]) startProcessing() {
+	if s.processing {
+		return
+	}
+	s.processing = true
+	go s.process()
+}
+
+// process writes queued chunks one at a time and closes the sink once a requested close is reached
+func (s *WritableStream[T]) process() {
+	for {
+		s.mu.Lock()
+		if s.state != streamOpen || (len(s.queue) == 0 && s.closeReq == nil) {
+			s.processing = false
+			s.notify()
+			s.mu.Unlock()
+			return
+		}
+
+		if len(s.queue) == 0 {
+			closeReq := s.closeReq
+			s.mu.Unlock()
+
+			var err error
+			if s.sink.Close != nil {
+				err = guard(s.sink.Close)
+			}
+			if err != nil {
+				s.fail(err)
+				closeReq.Reject(err)
+				continue
+			}
+
+			s.mu.Lock()
+			s.state = streamClosed
+			s.closeReq = nil
+			close(s.finished)
+			s.notify()
+			s.mu.Unlock()
+			closeReq.Resolve(nil)
+			continue
+		}
+
+		req := s.queue[0]
+		s.mu.Unlock()
+
+		var err error
+		if s.sink.Write != nil {
+			err = guard(func() error { return s.sink.Write(req.chunk, s.controller) })
+		}
+
+		if err != nil {
+			// Fail while req is still the in-flight head so only the chunks behind it are rejected
+			s.fail(err)
+		}
+
+		s.mu.Lock()
+		s.queue[0] = nil
+		s.queue = s.queue[1:]
+		s.queueSize -= req.size
+		s.notify()
+		s.mu.Unlock()
+
+		if err != nil {
+			req.done.Reject(err)
+			continue
+		}
+		req.done.Resolve(nil)
+	}
+}
+
+// close requests the sink to close after the queued chunks are written
+func (s *WritableStream[T]) close() *async.Promise[interface{}] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state == streamErrored {
+		return async.Reject[interface{}](s.err)
+	}
+	if s.state != streamOpen || s.closeReq != nil {
+		return async.Reject[interface{}](ErrStreamClosed)
+	}
+
+	closeReq := async.WithResolvers[interface{}]()
+	s.closeReq = &closeReq
+	s.notify()
+	s.startProcessing()
+	return closeReq.Promise
+}
+
+// abort errors the stream with reason and calls the sink's Abort once no write is in flight
+func (s *WritableStream[T]) abort(reason error) error {
+	s.mu.Lock()
+	if s.state == streamClosed {
+		s.mu.Unlock()
+		return nil
+	}
+	alreadyErrored := s.state == streamErrored
+	s.mu.Unlock()
+	if alreadyErrored {
+		return nil
+	}
+
+	s.fail(reason)
+	for {
+		s.mu.Lock()
+		if !s.processing {
+			s.mu.Unlock()
+			break
+		}
+		changed := s.changed
+		s.mu.Unlock()
+		<-changed
+	}
+
+	if s.sink.Abort != nil {
+		return guard(func() error { return s.sink.Abort(reason) })
+	}
+	return nil
+}
+
+// waitReady blocks until the queue is below the high-water mark. It fails if the stream
+// errors or closes, or if cancel is closed.
+func (s *WritableStream[T]) waitReady(cancel <-chan struct{}, reason func() error) error {
+	for {
+		s.mu.Lock()
+		switch {
+		case s.state == streamErrored:
+			err := s.err
+			s.mu.Unlock()
+			return err
+		case s.state == streamClosed || s.closeReq != nil:
+			s.mu.Unlock()
+			return ErrStreamClosed
+		case s.desiredSize() > 0:
+			s.mu.Unlock()
+			return nil
+		}
+		changed := s.changed
+		s.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-cancel:
+			return reason()
+		}
+	}
+}
+
+// closedPromise returns a promise that settles when the stream closes or errors
+func (s *WritableStream[T]) closedPromise() *async.Promise[interface{}] {
+	return async.Lazy(func() (interface{}, error) {
+		<-s.finished
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return nil, s.err
+	})
+}
+
+// Locked reports whether the stream has a writer (like stream.locked in TypeScript)
+func (s *WritableStream[T]) Locked() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.locked
+}
+
+// GetWriter locks the stream to a new writer (like stream.getWriter() in TypeScript)
+func (s *WritableStream[T]) GetWriter() (*WritableStreamWriter[T], error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.locked {
+		return nil, ErrStreamLocked
+	}
+	s.locked = true
+	return &WritableStreamWriter[T]{stream: s}, nil
+}
+
+// Close closes the stream once queued chunks are written (like stream.close() in TypeScript).
+// It fails if the stream is locked.
+func (s *WritableStream[T]) Close() *async.Promise[interface{}] {
+	if s.Locked() {
+		return async.Reject[interface{}](ErrStreamLocked)
+	}
+	return s.close()
+}
+
+// Abort errors the stream, discarding queued chunks (like stream.abort() in TypeScript). It
+// fails if the stream is locked.
+func (s *WritableStream[T]) Abort(reason ...error) *async.Promise[interface{}] {
+	if s.Locked() {
+		return async.Reject[interface{}](ErrStreamLocked)
+	}
+	r := cancelReason("stream aborted", reason)
+	return async.NewPromise(func() (interface{}, error) {
+		return nil, s.abort(r)
+	})
+}
+
+// WritableStreamController lets an UnderlyingSink error its stream and observe aborts (like
+// WritableStreamDefaultController in TypeScript)
+type WritableStreamController struct {
+	abort *async.AbortController
+	error func(err error)
+}
+
+// Error errors the stream; queued and future writes reject with err
+func (c *WritableStreamController) Error(err error) {
+	c.error(err)
+}
+
+// Signal is aborted when the stream is aborted or errors, so a long write can stop early
+func (c *WritableStreamController) Signal() *async.AbortSignal {
+	return c.abort.Signal()
+}
+
+// WritableStreamWriter writes chunks to a locked stream (like WritableStreamDefaultWriter in TypeScript)
+type WritableStreamWriter[T any] struct {
+	mu       sync.Mutex
+	stream   *WritableStream[T]
+	released bool
+}
+
+// active returns the writer's stream, or an error if the lock was released
+func (w *WritableStreamWriter[T]) active() (*WritableStream[T], error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.released {
+		return nil, ErrLockReleased
+	}
+	return w.stream, nil
+}
+
+// Write queues a chunk and resolves once the sink has written it (like writer.write() in
+// TypeScript). Await Ready first to respect backpressure.
+func (w *WritableStreamWriter[T]) Write(chunk T) *async.Promise[interface{}] {
+	s, err := w.active()
+	if err != nil {
+		return async.Reject[interface{}](err)
+	}
+	return s.write(chunk)
+}
+
+// Ready resolves once the queue is below the high-water mark (like writer.ready in TypeScript).
+// It rejects if the stream errors or closes first.
+func (w *WritableStreamWriter[T]) Ready() *async.Promise[interface{}] {
+	s, err := w.active()
+	if err != nil {
+		return async.Reject[interface{}](err)
+	}
+	return async.NewPromise(func() (interface{}, error) {
+		return nil, s.waitReady(nil, nil)
+	})
+}
+
+// DesiredSize returns how much can be written before reaching the high-water mark (like
+// writer.desiredSize in TypeScript)
+func (w *WritableStreamWriter[T]) DesiredSize() int {
+	w.stream.mu.Lock()
+	defer w.stream.mu.Unlock()
+	return w.stream.desiredSize()
+}
+
+// Close closes the stream once queued chunks are written (like writer.close() in TypeScript)
+func (w *WritableStreamWriter[T]) Close() *async.Promise[interface{}] {
+	s, err := w.active()
+	if err != nil {
+		return async.Reject[interface{}](err)
+	}
+	return s.close()
+}
+
+// Abort errors the stream, discarding queued chunks (like writer.abort() in TypeScript)
+func (w *WritableStreamWriter[T]) Abort(reason ...error) *async.Promise[interface{}] {
+	s, err := w.active()
+	if err != nil {
+		return async.Reject[interface{}](err)
+	}
+	r := cancelReason("stream aborted", reason)
+	return async.NewPromise(func() (interface{}, error) {
+		return nil, s.abort(r)
+	})
+}
+
+// Closed returns a promise that resolves when the stream closes or rejects if it errors
+// (like writer.closed in TypeScript)
+func (w *WritableStreamWriter[T]) Closed() *async.Promise[interface{}] {
+	return w.stream.closedPromise()
+}
+
+// ReleaseLock unlocks the stream so another writer can be acquired (like writer.releaseLock() in TypeScript)
+func (w *WritableStreamWriter[T]) ReleaseLock() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.released {
+		w.released = true
+		w.stream.mu.Lock()
+		w.stream.locked = false
+		w.stream.mu.Unlock()
+	}
+}
@@ -0,0 +1,48 @@
+package streams
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWritableBackpressure(t *testing.T) {
+	release := make(chan struct{})
+	var written []int
+	s := NewWritableStream(UnderlyingSink[int]{
+		Write: func(chunk int, c *WritableStreamController) error {
+			<-release
+			written = append(written, chunk)
+			return nil
+		},
+	}, CountQueuingStrategy[int](2))
+	writer, _ := s.GetWriter()
+
+	if size := writer.DesiredSize(); size != 2 {
+		t.Fatalf("desired size %d, want 2", size)
+	}
+	writer.Write(1)
+	writer.Write(2)
+	last := writer.Write(3)
+	if size := writer.DesiredSize(); size != -1 {
+		t.Fatalf("desired size %d with 3 queued, want -1", size)
+	}
+	ready := writer.Ready()
+	settle()
+	if !ready.IsPending() {
+		t.Fatal("Ready resolved above the high-water mark")
+	}
+
+	close(release)
+	if _, err := ready.AwaitWithTimeout(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := last.AwaitWithTimeout(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := writer.Close().AwaitWithTimeout(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if len(written) != 3 || written[2] != 3 {
+		t.Fatalf("written %v", written)
+	}
+}
@@ -8,7 +8,7 @@ import (
 	"strings"
 	"time"
 
-	"PROJECT_NAME/async"
+	"PROJECT_NAME/streams"
 	"PROJECT_NAME/types"
 	"PROJECT_NAME/utils"
 
@@ -166,55 +166,45 @@ func processFiles(inputFile, outputFile string, workers int) error {
 		return types.NewError("Input file not found", types.ValidationError)
 	}
 
-	// Use Promise for async file processing
-	promise := async.NewPromise(func() (string, error) {
-		// Simulate file processing
-		fmt.Printf("⚙️  Processing with %d workers...\n", workers)
-		
-		// Read file content
-		content, err := os.ReadFile(inputFile)
-		if err != nil {
-			return "", err
-		}
-
-		// Process content using TypeScript-like utilities
-		lines := strings.Split(string(content), "\n")
-		
-		// Filter non-empty lines
-		nonEmptyLines := utils.Filter(lines, func(line string) bool {
-			return !utils.Strings.IsBlank(line)
-		})
-
-		// Transform lines (example: add line numbers)
-		numberedLines := utils.MapWithIndex(nonEmptyLines, func(line string, index int) string {
-			return fmt.Sprintf("%d: %s", index+1, line)
-		})
-
-		// Join back
-		result := strings.Join(numberedLines, "\n")
-
-		// Simulate processing time
-		time.Sleep(500 * time.Millisecond)
+	fmt.Printf("⚙️  Processing with %d workers...\n", workers)
 
-		return result, nil
-	})
-
-	// Await result
-	result, err := promise.Await()
+	// Stream the file line by line instead of reading it into memory
+	file, err := os.Open(inputFile)
 	if err != nil {
-		return types.NewError("Processing failed", types.InternalError).WithCause(err)
+		return types.WrapError(err, "Failed to open input", types.InternalError)
 	}
+	defer file.Close()
 
-	// Write output
+	out := os.Stdout
 	if outputFile != "" {
-		err = os.WriteFile(outputFile, []byte(result), 0644)
+		out, err = os.Create(outputFile)
 		if err != nil {
-			return types.NewError("Failed to write output", types.InternalError).WithCause(err)
+			return types.WrapError(err, "Failed to write output", types.InternalError)
 		}
-		fmt.Printf("✅ Output written to: %s\n", outputFile)
+		defer out.Close()
 	} else {
 		fmt.Println("📄 Processed content:")
-		fmt.Println(result)
+	}
+
+	// Filter non-empty lines
+	nonEmptyLines := streams.PipeThrough(streams.Lines(file), streams.Filter(func(line string) bool {
+		return !utils.Strings.IsBlank(line)
+	}))
+
+	// Transform lines (example: add line numbers)
+	index := 0
+	numberedLines := streams.PipeThrough(nonEmptyLines, streams.Map(func(line string) ([]byte, error) {
+		index++
+		return []byte(fmt.Sprintf("%d: %s\n", index, line)), nil
+	}))
+
+	// Pipe to the output, respecting backpressure
+	if _, err := numberedLines.PipeTo(streams.ToWriter(out)).Await(); err != nil {
+		return types.WrapError(err, "Processing failed", types.InternalError)
+	}
+
+	if outputFile != "" {
+		fmt.Printf("✅ Output written to: %s\n", outputFile)
 	}
 
 	return nil
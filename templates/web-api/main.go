@@ -5,10 +5,13 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"os"
 	"strconv"
+	"sync/atomic"
 	"time"
 
 	"PROJECT_NAME/async"
+	"PROJECT_NAME/transport"
 	"PROJECT_NAME/types"
 	"PROJECT_NAME/utils"
 
@@ -24,16 +27,33 @@ type User struct {
 }
 
 // In-memory user store (use database in production)
-var users = types.NewMap[int, *User]()
-var userIdCounter = 1
+// Handlers run on their own goroutines, so shared state must be safe for concurrent use
+var users = types.NewConcurrentMap[int, *User]()
+var userIdCounter atomic.Int64
 
-// Event emitter for user events
-var userEvents = types.NewEventEmitter[UserEvent]()
+// Event bus for user events; each topic key has its own payload type
+var userEvents = types.NewEventBus()
 
-type UserEvent struct {
-	Type string      `json:"type"`
-	User *User       `json:"user"`
-	Time time.Time   `json:"time"`
+var (
+	userCreated = types.NewEventKey[UserCreated]("user:created")
+	userUpdated = types.NewEventKey[UserUpdated]("user:updated")
+	userDeleted = types.NewEventKey[UserDeleted]("user:deleted")
+)
+
+type UserCreated struct {
+	User *User     `json:"user"`
+	Time time.Time `json:"time"`
+}
+
+type UserUpdated struct {
+	User     *User     `json:"user"`
+	Previous *User     `json:"previous"`
+	Time     time.Time `json:"time"`
+}
+
+type UserDeleted struct {
+	User *User     `json:"user"`
+	Time time.Time `json:"time"`
 }
 
 func main() {
@@ -45,6 +65,16 @@ func main() {
 	// Setup event listeners
 	setupEventListeners()
 	
+	// Share user events with CLI tools over a Unix socket, e.g. EVENTS_SOCKET=/tmp/api.sock
+	if socket := os.Getenv("EVENTS_SOCKET"); socket != "" {
+		server, err := transport.Serve(userEvents, transport.NewUnixTransport(socket))
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer server.Close()
+		fmt.Printf("🔌 Serving user events on %s\n", server.Addr())
+	}
+	
 	// Setup routes
 	router := setupRoutes()
 	
@@ -67,20 +97,33 @@ func initializeData() {
 	users.Set(1, &User{ID: 1, Name: "Alice Johnson", Email: "alice@example.com", Age: 28})
 	users.Set(2, &User{ID: 2, Name: "Bob Smith", Email: "bob@example.com", Age: 32})
 	users.Set(3, &User{ID: 3, Name: "Carol Brown", Email: "carol@example.com", Age: 25})
-	userIdCounter = 4
+	userIdCounter.Store(3)
 }
 
 func setupEventListeners() {
-	userEvents.On("user:created", func(event UserEvent) {
+	userEvents.Use(func(msg *types.BusMessage, next func(*types.BusMessage) error) error {
+		msg.Headers["published-at"] = msg.Timestamp.Format(time.RFC3339)
+		return next(msg)
+	})
+
+	types.SubscribeTopic(userEvents, userCreated, func(event UserCreated, msg *types.BusMessage) error {
 		fmt.Printf("📢 Event: User %s was created\n", event.User.Name)
+		return nil
 	})
 	
-	userEvents.On("user:updated", func(event UserEvent) {
+	types.SubscribeTopic(userEvents, userUpdated, func(event UserUpdated, msg *types.BusMessage) error {
 		fmt.Printf("📢 Event: User %s was updated\n", event.User.Name)
+		return nil
 	})
 	
-	userEvents.On("user:deleted", func(event UserEvent) {
+	types.SubscribeTopic(userEvents, userDeleted, func(event UserDeleted, msg *types.BusMessage) error {
 		fmt.Printf("📢 Event: User %s was deleted\n", event.User.Name)
+		return nil
+	})
+
+	userEvents.Subscribe(types.DeadLetterTopic, func(payload interface{}) {
+		letter := payload.(*types.DeadLetter)
+		fmt.Printf("⚠️  Event %s failed in %s: %v\n", letter.Message.Topic, letter.Subscriber, letter.Err)
 	})
 }
 
@@ -143,8 +186,9 @@ func getUsersHandler(w http.ResponseWriter, r *http.Request) {
 		time.Sleep(10 * time.Millisecond) // Simulate DB query
 		
 		allUsers := make([]User, 0, users.Size())
-		users.ForEach(func(id int, user *User) {
+		users.Range(func(id int, user *User) bool {
 			allUsers = append(allUsers, *user)
+			return true
 		})
 		
 		return allUsers, nil
@@ -196,13 +240,11 @@ func createUserHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	
 	// Assign ID and save
-	user.ID = userIdCounter
-	userIdCounter++
+	user.ID = int(userIdCounter.Add(1))
 	users.Set(user.ID, &user)
 	
-	// Emit event
-	userEvents.Emit("user:created", UserEvent{
-		Type: "created",
+	// Publish event
+	types.PublishTopic(userEvents, userCreated, UserCreated{
 		User: &user,
 		Time: time.Now(),
 	})
@@ -220,8 +262,7 @@ func updateUserHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
-	existingUserOpt := users.Get(id)
-	if existingUserOpt.IsNone() {
+	if !users.Has(id) {
 		writeErrorResponse(w, http.StatusNotFound, "User not found", nil)
 		return
 	}
@@ -239,13 +280,23 @@ func updateUserHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	
 	updatedUser.ID = id
-	users.Set(id, &updatedUser)
 	
-	// Emit event
-	userEvents.Emit("user:updated", UserEvent{
-		Type: "updated",
-		User: &updatedUser,
-		Time: time.Now(),
+	// Replace the user only if it still exists, capturing the previous version atomically
+	var previous *User
+	users.Update(id, func(existing *User) *User {
+		previous = existing
+		return &updatedUser
+	})
+	if previous == nil {
+		writeErrorResponse(w, http.StatusNotFound, "User not found", nil)
+		return
+	}
+	
+	// Publish event
+	types.PublishTopic(userEvents, userUpdated, UserUpdated{
+		User:     &updatedUser,
+		Previous: previous,
+		Time:     time.Now(),
 	})
 	
 	writeJSONResponse(w, http.StatusOK, updatedUser)
@@ -261,18 +312,16 @@ func deleteUserHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
-	userOpt := users.Get(id)
+	userOpt := users.GetAndDelete(id)
 	if userOpt.IsNone() {
 		writeErrorResponse(w, http.StatusNotFound, "User not found", nil)
 		return
 	}
 	
 	user := userOpt.Get()
-	users.Delete(id)
 	
-	// Emit event
-	userEvents.Emit("user:deleted", UserEvent{
-		Type: "deleted",
+	// Publish event
+	types.PublishTopic(userEvents, userDeleted, UserDeleted{
 		User: user,
 		Time: time.Now(),
 	})
@@ -0,0 +1,294 @@
+package testing
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"typescript-golang/timers"
+	"typescript-golang/types"
+)
+
+// DefaultMarbleFrame is the virtual time of one marble frame
+const DefaultMarbleFrame = 10 * time.Millisecond
+
+// DefaultMarbleMaxFrames bounds Flush for observables that never stop, like Interval
+const DefaultMarbleMaxFrames = 1000
+
+// ErrMarble is the error emitted for '#' unless the scheduler's Error is set
+var ErrMarble = errors.New("error")
+
+// MarbleScheduler runs marble tests of observables on a fake clock (like TestScheduler in RxJS).
+// Marble strings describe notifications frame by frame:
+//
+//	'-'   one frame passes
+//	'a'   a value; it is looked up in the values map, or used as is for string observables
+//	'|'   completion
+//	'#'   an error (ErrMarble)
+//	'(ab)' notifications in the same frame; unlike RxJS the group takes a single frame
+//	'^'   the subscription point of a hot observable
+//
+// Spaces are ignored. Pass s.Clock to the time-based operators under test.
+type MarbleScheduler struct {
+	Clock *timers.FakeClock
+	// Frame is the virtual time of one frame
+	Frame time.Duration
+	// MaxFrames bounds Flush
+	MaxFrames int
+	// Error is emitted for '#'
+	Error error
+	start time.Time
+}
+
+// NewMarbleScheduler creates a scheduler on a new fake clock with an optional frame duration
+// (default DefaultMarbleFrame)
+func NewMarbleScheduler(frame ...time.Duration) *MarbleScheduler {
+	duration := DefaultMarbleFrame
+	if len(frame) > 0 && frame[0] > 0 {
+		duration = frame[0]
+	}
+	clock := timers.NewFakeClock()
+	return &MarbleScheduler{
+		Clock:     clock,
+		Frame:     duration,
+		MaxFrames: DefaultMarbleMaxFrames,
+		Error:     ErrMarble,
+		start:     clock.Now(),
+	}
+}
+
+// Frames converts a number of frames to virtual time, e.g. for the span of BufferTime
+func (s *MarbleScheduler) Frames(n int) time.Duration {
+	return time.Duration(n) * s.Frame
+}
+
+// now returns the current frame
+func (s *MarbleScheduler) now() int {
+	return int(s.Clock.Since(s.start) / s.Frame)
+}
+
+// Flush advances the clock frame by frame until no timers are left, or MaxFrames have passed
+// (like testScheduler.flush() in RxJS)
+func (s *MarbleScheduler) Flush() {
+	for i := 0; i < s.MaxFrames && s.Clock.TimerCount() > 0; i++ {
+		s.Clock.Advance(s.Frame)
+	}
+}
+
+// marbleEvent is one notification of a marble string
+type marbleEvent struct {
+	frame  int
+	symbol string
+}
+
+// parseMarbles returns the notifications of a marble string and its subscription frame
+func parseMarbles(marbles string) ([]marbleEvent, int) {
+	var events []marbleEvent
+	frame, subscribed := 0, 0
+	inGroup := false
+
+	for _, r := range marbles {
+		switch r {
+		case ' ':
+			continue
+		case '-':
+		case '(':
+			inGroup = true
+			continue
+		case ')':
+			inGroup = false
+		case '^':
+			subscribed = frame
+		default:
+			events = append(events, marbleEvent{frame: frame, symbol: string(r)})
+		}
+		if !inGroup {
+			frame++
+		}
+	}
+	return events, subscribed
+}
+
+// marbleValue returns the value a marble symbol stands for
+func marbleValue[T any](symbol string, values []map[string]T) T {
+	if len(values) > 0 {
+		if value, exists := values[0][symbol]; exists {
+			return value
+		}
+	}
+	if value, ok := interface{}(symbol).(T); ok {
+		return value
+	}
+	var zero T
+	return zero
+}
+
+// notify pushes the notification of a marble symbol
+func notify[T any](s *MarbleScheduler, subscriber interface {
+	Next(value T)
+	Error(err error)
+	Complete()
+}, symbol string, values []map[string]T) {
+	switch symbol {
+	case "|":
+		subscriber.Complete()
+	case "#":
+		subscriber.Error(s.Error)
+	default:
+		subscriber.Next(marbleValue(symbol, values))
+	}
+}
+
+// Cold creates an observable that plays the marbles from the frame it is subscribed at (like
+// cold() in RxJS)
+func Cold[T any](s *MarbleScheduler, marbles string, values ...map[string]T) *types.Observable[T] {
+	events, _ := parseMarbles(marbles)
+	return types.NewObservableFrom(func(subscriber types.Subscriber[T]) types.Teardown {
+		scheduled := make([]timers.Timer, 0, len(events))
+		for _, event := range events {
+			symbol := event.symbol
+			scheduled = append(scheduled, s.Clock.AfterFunc(s.Frames(event.frame), func() {
+				notify[T](s, subscriber, symbol, values)
+			}))
+		}
+		return func() {
+			for _, timer := range scheduled {
+				timer.Stop()
+			}
+		}
+	})
+}
+
+// Hot creates an observable that plays the marbles from now, with '^' marking the current
+// frame (like hot() in RxJS). Notifications before '^' are dropped.
+func Hot[T any](s *MarbleScheduler, marbles string, values ...map[string]T) *types.Observable[T] {
+	events, subscribed := parseMarbles(marbles)
+	subject := types.NewSubject[T]()
+	for _, event := range events {
+		if event.frame < subscribed {
+			continue
+		}
+		symbol := event.symbol
+		s.Clock.AfterFunc(s.Frames(event.frame-subscribed), func() {
+			notify[T](s, subject, symbol, values)
+		})
+	}
+	return subject.AsObservable()
+}
+
+// MarbleRecording records the notifications of an observable for comparison with a marble
+// string (like expectObservable() in RxJS)
+type MarbleRecording[T any] struct {
+	scheduler    *MarbleScheduler
+	values       []map[string]T
+	subscription *types.Subscription
+	mu           sync.Mutex
+	events       []marbleEvent
+	received     []T
+}
+
+// Record subscribes to an observable and records its notifications. Values are rendered with
+// their key in the values map, or with fmt.Sprint.
+func Record[T any](s *MarbleScheduler, observable *types.Observable[T], values ...map[string]T) *MarbleRecording[T] {
+	r := &MarbleRecording[T]{scheduler: s, values: values}
+	r.subscription = observable.SubscribeObserver(types.Observer[T]{
+		Next: func(value T) {
+			r.record(r.symbol(value), &value)
+		},
+		Error: func(err error) {
+			r.record("#", nil)
+		},
+		Complete: func() {
+			r.record("|", nil)
+		},
+	})
+	return r
+}
+
+// record appends a notification at the current frame
+func (r *MarbleRecording[T]) record(symbol string, value *T) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, marbleEvent{frame: r.scheduler.now(), symbol: symbol})
+	if value != nil {
+		r.received = append(r.received, *value)
+	}
+}
+
+// symbol returns the marble symbol of a value
+func (r *MarbleRecording[T]) symbol(value T) string {
+	if len(r.values) > 0 {
+		keys := make([]string, 0, len(r.values[0]))
+		for key := range r.values[0] {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if reflect.DeepEqual(r.values[0][key], value) {
+				return key
+			}
+		}
+	}
+	return fmt.Sprint(value)
+}
+
+// Unsubscribe stops recording
+func (r *MarbleRecording[T]) Unsubscribe() {
+	r.subscription.Unsubscribe()
+}
+
+// Values returns the recorded values
+func (r *MarbleRecording[T]) Values() []T {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]T(nil), r.received...)
+}
+
+// Marbles renders the recorded notifications as a marble string
+func (r *MarbleRecording[T]) Marbles() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var b strings.Builder
+	frame := 0
+	for i := 0; i < len(r.events); {
+		event := r.events[i]
+		for ; frame < event.frame; frame++ {
+			b.WriteByte('-')
+		}
+		j := i
+		for j < len(r.events) && r.events[j].frame == event.frame {
+			j++
+		}
+		if j-i == 1 {
+			b.WriteString(event.symbol)
+		} else {
+			b.WriteByte('(')
+			for _, grouped := range r.events[i:j] {
+				b.WriteString(grouped.symbol)
+			}
+			b.WriteByte(')')
+		}
+		frame++
+		i = j
+	}
+	return b.String()
+}
+
+// ToBe flushes the scheduler and checks the recorded notifications against a marble string
+// (like expectObservable().toBe() in RxJS). Trailing frames without notifications are ignored.
+func (r *MarbleRecording[T]) ToBe(expected string) {
+	r.scheduler.Flush()
+
+	want := strings.TrimRight(strings.ReplaceAll(expected, " ", ""), "-")
+	got := r.Marbles()
+	if got != want {
+		panic(types.NewValidationError(fmt.Sprintf("Expected marbles %q to be %q", got, want)))
+	}
+}
@@ -0,0 +1,251 @@
+package timers
+
+import (
+	"container/heap"
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxFakeTimerRuns bounds RunAllTimers so self-rescheduling intervals cannot loop forever
+// (Jest aborts after the same number of timers)
+const maxFakeTimerRuns = 100000
+
+// fakeTimer is a timer scheduled on a FakeClock
+type fakeTimer struct {
+	clock *FakeClock
+	when  time.Time
+	seq   uint64
+	index int
+	ch    chan time.Time
+	fn    func()
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	return t.clock.remove(t)
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	active := t.clock.remove(t)
+	t.clock.schedule(t, d)
+	return active
+}
+
+type fakeTimerHeap []*fakeTimer
+
+func (h fakeTimerHeap) Len() int { return len(h) }
+func (h fakeTimerHeap) Less(i, j int) bool {
+	if !h[i].when.Equal(h[j].when) {
+		return h[i].when.Before(h[j].when)
+	}
+	return h[i].seq < h[j].seq
+}
+func (h fakeTimerHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+func (h *fakeTimerHeap) Push(x interface{}) {
+	timer := x.(*fakeTimer)
+	timer.index = len(*h)
+	*h = append(*h, timer)
+}
+func (h *fakeTimerHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	timer := old[n-1]
+	old[n-1] = nil
+	timer.index = -1
+	*h = old[:n-1]
+	return timer
+}
+
+// FakeClock is a manually advanced Clock for tests (like Jest's fake timers).
+// Time only moves when Advance, RunAllTimers or Set is called; due AfterFunc callbacks
+// run synchronously on the goroutine that advances the clock.
+type FakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	timers  fakeTimerHeap
+	seq     uint64
+	changed *sync.Cond
+}
+
+// NewFakeClock creates a FakeClock starting at the given time, or at a fixed epoch
+func NewFakeClock(start ...time.Time) *FakeClock {
+	now := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	if len(start) > 0 {
+		now = start[0]
+	}
+	c := &FakeClock{now: now}
+	c.changed = sync.NewCond(&c.mu)
+	return c
+}
+
+// schedule adds a timer firing d after the current fake time. Must be called with c.mu held.
+func (c *FakeClock) schedule(t *fakeTimer, d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	t.when = c.now.Add(d)
+	t.seq = c.seq
+	c.seq++
+	heap.Push(&c.timers, t)
+	c.changed.Broadcast()
+}
+
+// remove unschedules a timer. Must be called with c.mu held.
+func (c *FakeClock) remove(t *fakeTimer) bool {
+	if t.index >= 0 && t.index < c.timers.Len() && c.timers[t.index] == t {
+		heap.Remove(&c.timers, t.index)
+		return true
+	}
+	return false
+}
+
+// Now returns the current fake time
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Since returns the fake time elapsed since t
+func (c *FakeClock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+// NewTimer creates a timer that fires once the fake time reaches now+d
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{clock: c, ch: make(chan time.Time, 1), index: -1}
+	c.schedule(t, d)
+	return t
+}
+
+// AfterFunc calls fn synchronously during the Advance that reaches now+d
+func (c *FakeClock) AfterFunc(d time.Duration, fn func()) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{clock: c, fn: fn, index: -1}
+	c.schedule(t, d)
+	return t
+}
+
+// Sleep blocks until another goroutine advances the fake time by d
+func (c *FakeClock) Sleep(d time.Duration) {
+	<-c.NewTimer(d).C()
+}
+
+// fireNext fires the earliest timer due at or before deadline, moving the clock to its time
+func (c *FakeClock) fireNext(deadline time.Time, unbounded bool) bool {
+	c.mu.Lock()
+	if c.timers.Len() == 0 || (!unbounded && c.timers[0].when.After(deadline)) {
+		c.mu.Unlock()
+		return false
+	}
+	c.fire(heap.Pop(&c.timers).(*fakeTimer))
+	return true
+}
+
+// fire moves the clock to an unscheduled timer's time and runs it. It must be called
+// with c.mu held and releases it before running callbacks.
+func (c *FakeClock) fire(t *fakeTimer) {
+	if t.when.After(c.now) {
+		c.now = t.when
+	}
+	now := c.now
+	c.mu.Unlock()
+
+	if t.fn != nil {
+		t.fn()
+		return
+	}
+	select {
+	case t.ch <- now:
+	default:
+	}
+}
+
+// Advance moves the fake time forward by d, firing due timers in order
+// (like jest.advanceTimersByTime() in TypeScript)
+func (c *FakeClock) Advance(d time.Duration) {
+	deadline := c.Now().Add(d)
+	for c.fireNext(deadline, false) {
+	}
+
+	c.mu.Lock()
+	if deadline.After(c.now) {
+		c.now = deadline
+	}
+	c.mu.Unlock()
+}
+
+// Set moves the fake time to t, firing timers due before it
+func (c *FakeClock) Set(t time.Time) {
+	c.Advance(t.Sub(c.Now()))
+}
+
+// RunAllTimers fires timers until none remain, including ones scheduled meanwhile
+// (like jest.runAllTimers() in TypeScript). It returns the number of timers fired.
+func (c *FakeClock) RunAllTimers() int {
+	fired := 0
+	for fired < maxFakeTimerRuns && c.fireNext(time.Time{}, true) {
+		fired++
+	}
+	return fired
+}
+
+// RunOnlyPendingTimers fires the timers that are currently scheduled but not ones they create
+// (like jest.runOnlyPendingTimers() in TypeScript). It returns the number of timers fired.
+func (c *FakeClock) RunOnlyPendingTimers() int {
+	c.mu.Lock()
+	pending := make(fakeTimerHeap, len(c.timers))
+	copy(pending, c.timers)
+	c.mu.Unlock()
+	sort.Slice(pending, func(i, j int) bool {
+		if !pending[i].when.Equal(pending[j].when) {
+			return pending[i].when.Before(pending[j].when)
+		}
+		return pending[i].seq < pending[j].seq
+	})
+
+	fired := 0
+	for _, t := range pending {
+		c.mu.Lock()
+		if !c.remove(t) {
+			// Stopped by an earlier callback
+			c.mu.Unlock()
+			continue
+		}
+		c.fire(t)
+		fired++
+	}
+	return fired
+}
+
+// TimerCount returns the number of scheduled timers (like jest.getTimerCount() in TypeScript)
+func (c *FakeClock) TimerCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.timers.Len()
+}
+
+// BlockUntil waits until at least n timers are scheduled, e.g. until a goroutine under test
+// has reached its Sleep
+func (c *FakeClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for c.timers.Len() < n {
+		c.changed.Wait()
+	}
+}
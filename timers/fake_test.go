@@ -0,0 +1,170 @@
+package timers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// elapsed records the fake time elapsed since start whenever its callback runs
+func elapsed(clock *FakeClock, start time.Time, log *[]time.Duration) func() {
+	return func() { *log = append(*log, clock.Since(start)) }
+}
+
+func TestFakeClockAdvanceFiresInDeadlineOrder(t *testing.T) {
+	clock := NewFakeClock()
+	timers := NewTimers(clock)
+	var calls []string
+	log := func(name string) func() {
+		return func() { calls = append(calls, name) }
+	}
+
+	timers.SetTimeout(log("30ms"), 30*time.Millisecond)
+	timers.SetTimeout(log("10ms"), 10*time.Millisecond)
+	timers.SetTimeout(log("20ms first"), 20*time.Millisecond)
+	timers.SetTimeout(log("20ms second"), 20*time.Millisecond)
+
+	clock.Advance(25 * time.Millisecond)
+	if want := []string{"10ms", "20ms first", "20ms second"}; !reflect.DeepEqual(calls, want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+	if clock.TimerCount() != 1 || timers.Active() != 1 {
+		t.Fatalf("%d timers scheduled, %d active, want 1", clock.TimerCount(), timers.Active())
+	}
+	clock.Advance(5 * time.Millisecond)
+	if len(calls) != 4 || calls[3] != "30ms" {
+		t.Fatalf("calls = %v, want 30ms last", calls)
+	}
+}
+
+func TestFakeClockTimeDuringCallbacks(t *testing.T) {
+	clock := NewFakeClock()
+	start := clock.Now()
+	var at []time.Duration
+	clock.AfterFunc(10*time.Millisecond, elapsed(clock, start, &at))
+	clock.AfterFunc(40*time.Millisecond, elapsed(clock, start, &at))
+
+	clock.Advance(time.Minute)
+	if want := []time.Duration{10 * time.Millisecond, 40 * time.Millisecond}; !reflect.DeepEqual(at, want) {
+		t.Fatalf("callbacks ran at %v, want %v", at, want)
+	}
+	if clock.Since(start) != time.Minute {
+		t.Fatalf("clock at %v after Advance, want 1m", clock.Since(start))
+	}
+}
+
+func TestClearTimeoutAndInterval(t *testing.T) {
+	clock := NewFakeClock()
+	timers := NewTimers(clock)
+	calls := 0
+	timeout := timers.SetTimeout(func() { calls++ }, 10*time.Millisecond)
+	interval := timers.SetInterval(func() { calls++ }, 10*time.Millisecond)
+
+	timers.ClearTimeout(timeout)
+	timers.ClearInterval(interval)
+	clock.Advance(time.Second)
+	if calls != 0 || timers.Active() != 0 || clock.TimerCount() != 0 {
+		t.Fatalf("calls = %d, active = %d, scheduled = %d after clearing", calls, timers.Active(), clock.TimerCount())
+	}
+
+	// An interval can clear itself from its own callback
+	interval = timers.SetInterval(func() {
+		calls++
+		if calls == 2 {
+			timers.ClearInterval(interval)
+		}
+	}, 10*time.Millisecond)
+	clock.Advance(time.Second)
+	if calls != 2 || clock.TimerCount() != 0 {
+		t.Fatalf("calls = %d, scheduled = %d, want 2 and 0", calls, clock.TimerCount())
+	}
+}
+
+func TestIntervalReschedulesWithinOneAdvance(t *testing.T) {
+	clock := NewFakeClock()
+	timers := NewTimers(clock)
+	start := clock.Now()
+	var at []time.Duration
+	timers.SetInterval(elapsed(clock, start, &at), 10*time.Millisecond)
+
+	clock.Advance(35 * time.Millisecond)
+	want := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 30 * time.Millisecond}
+	if !reflect.DeepEqual(at, want) {
+		t.Fatalf("interval ran at %v, want %v", at, want)
+	}
+	clock.Advance(5 * time.Millisecond)
+	if len(at) != 4 || at[3] != 40*time.Millisecond {
+		t.Fatalf("interval ran at %v, want a fourth tick at 40ms", at)
+	}
+}
+
+func TestRunOnlyPendingTimersSkipsNewTimers(t *testing.T) {
+	clock := NewFakeClock()
+	timers := NewTimers(clock)
+	var calls []string
+	// Each timeout schedules the next one, like a recursive setTimeout
+	var chain func(n int)
+	chain = func(n int) {
+		timers.SetTimeout(func() {
+			calls = append(calls, "chain")
+			if n > 1 {
+				chain(n - 1)
+			}
+		}, time.Second)
+	}
+	chain(3)
+	timers.SetTimeout(func() { calls = append(calls, "other") }, time.Minute)
+
+	if fired := clock.RunOnlyPendingTimers(); fired != 2 {
+		t.Fatalf("RunOnlyPendingTimers fired %d timers, want 2", fired)
+	}
+	if want := []string{"chain", "other"}; !reflect.DeepEqual(calls, want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+	if clock.TimerCount() != 1 {
+		t.Fatalf("%d timers scheduled, want the one created meanwhile", clock.TimerCount())
+	}
+
+	if fired := clock.RunAllTimers(); fired != 2 {
+		t.Fatalf("RunAllTimers fired %d timers, want 2", fired)
+	}
+	if len(calls) != 4 || clock.TimerCount() != 0 {
+		t.Fatalf("calls = %v with %d timers left, want the whole chain", calls, clock.TimerCount())
+	}
+}
+
+func TestRunOnlyPendingTimersSkipsStoppedTimers(t *testing.T) {
+	clock := NewFakeClock()
+	calls := 0
+	var second Timer
+	clock.AfterFunc(time.Second, func() {
+		calls++
+		second.Stop()
+	})
+	second = clock.AfterFunc(2*time.Second, func() { calls++ })
+
+	if fired := clock.RunOnlyPendingTimers(); fired != 1 || calls != 1 {
+		t.Fatalf("fired %d timers with %d calls, want the stopped timer skipped", fired, calls)
+	}
+}
+
+func TestBlockUntil(t *testing.T) {
+	clock := NewFakeClock()
+	woke := make(chan time.Time, 1)
+	go func() {
+		clock.Sleep(time.Minute)
+		woke <- clock.Now()
+	}()
+
+	// Advancing before the goroutine sleeps would not wake it
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+	select {
+	case now := <-woke:
+		if want := NewFakeClock().Now().Add(time.Minute); !now.Equal(want) {
+			t.Fatalf("woke at %v, want %v", now, want)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the sleeping goroutine was not woken")
+	}
+}
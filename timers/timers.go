@@ -0,0 +1,218 @@
+package timers
+
+import (
+	"sync"
+	"time"
+)
+
+// Timer is a stoppable timer created by a Clock
+type Timer interface {
+	// C returns the channel on which the fire time is delivered
+	C() <-chan time.Time
+	// Stop prevents the timer from firing; it returns false if it already fired or was stopped
+	Stop() bool
+	// Reset changes the timer to fire after d; it returns true if the timer was active
+	Reset(d time.Duration) bool
+}
+
+// Clock is a source of time and timers, so time-dependent code can run against a FakeClock in tests
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	Sleep(d time.Duration)
+	NewTimer(d time.Duration) Timer
+	// AfterFunc calls fn on its own goroutine (or synchronously for a FakeClock) after d
+	AfterFunc(d time.Duration, fn func()) Timer
+}
+
+// realClock implements Clock with the time package
+type realClock struct{}
+
+type realTimer struct {
+	timer *time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time           { return t.timer.C }
+func (t realTimer) Stop() bool                    { return t.timer.Stop() }
+func (t realTimer) Reset(d time.Duration) bool    { return t.timer.Reset(d) }
+func (realClock) Now() time.Time                  { return time.Now() }
+func (realClock) Since(t time.Time) time.Duration { return time.Since(t) }
+func (realClock) Sleep(d time.Duration)           { time.Sleep(d) }
+func (realClock) NewTimer(d time.Duration) Timer  { return realTimer{time.NewTimer(d)} }
+func (realClock) AfterFunc(d time.Duration, fn func()) Timer {
+	return realTimer{time.AfterFunc(d, fn)}
+}
+
+// Real is the Clock backed by the system time
+var Real Clock = realClock{}
+
+var (
+	defaultMu    sync.RWMutex
+	defaultClock = Real
+)
+
+// Default returns the clock used when no clock is passed explicitly
+func Default() Clock {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultClock
+}
+
+// SetDefault replaces the clock used when no clock is passed explicitly
+func SetDefault(clock Clock) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if clock == nil {
+		clock = Real
+	}
+	defaultClock = clock
+}
+
+// UseFakeTimers installs a new FakeClock as the default clock and returns it
+// (like jest.useFakeTimers() in TypeScript)
+func UseFakeTimers(start ...time.Time) *FakeClock {
+	clock := NewFakeClock(start...)
+	SetDefault(clock)
+	return clock
+}
+
+// UseRealTimers restores the system clock as the default (like jest.useRealTimers() in TypeScript)
+func UseRealTimers() {
+	SetDefault(Real)
+}
+
+// Resolve returns the first non-nil clock, or the default clock. It lets APIs accept
+// an optional trailing clock argument.
+func Resolve(clock ...Clock) Clock {
+	for _, c := range clock {
+		if c != nil {
+			return c
+		}
+	}
+	return Default()
+}
+
+// TimerID identifies a timeout or interval created by Timers
+type TimerID int
+
+// Timers provides setTimeout/setInterval with handle IDs on top of a Clock
+type Timers struct {
+	mu     sync.Mutex
+	clock  Clock
+	timers map[TimerID]Timer
+	nextID TimerID
+}
+
+// NewTimers creates a Timers instance. Without a clock it uses the default clock at call time.
+func NewTimers(clock ...Clock) *Timers {
+	t := &Timers{timers: make(map[TimerID]Timer)}
+	if len(clock) > 0 {
+		t.clock = clock[0]
+	}
+	return t
+}
+
+func (t *Timers) getClock() Clock {
+	if t.clock != nil {
+		return t.clock
+	}
+	return Default()
+}
+
+// SetTimeout calls fn once after delay (like setTimeout() in TypeScript)
+func (t *Timers) SetTimeout(fn func(), delay time.Duration) TimerID {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.nextID++
+	id := t.nextID
+	t.timers[id] = t.getClock().AfterFunc(delay, func() {
+		t.mu.Lock()
+		_, active := t.timers[id]
+		delete(t.timers, id)
+		t.mu.Unlock()
+
+		if active {
+			fn()
+		}
+	})
+	return id
+}
+
+// SetInterval calls fn every interval until cleared (like setInterval() in TypeScript)
+func (t *Timers) SetInterval(fn func(), interval time.Duration) TimerID {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.nextID++
+	id := t.nextID
+	clock := t.getClock()
+
+	var tick func()
+	tick = func() {
+		t.mu.Lock()
+		if _, active := t.timers[id]; !active {
+			t.mu.Unlock()
+			return
+		}
+		// Schedule the next tick before running fn so fn can clear the interval
+		t.timers[id] = clock.AfterFunc(interval, tick)
+		t.mu.Unlock()
+
+		fn()
+	}
+	t.timers[id] = clock.AfterFunc(interval, tick)
+	return id
+}
+
+// clear stops a timer by ID
+func (t *Timers) clear(id TimerID) {
+	t.mu.Lock()
+	timer, exists := t.timers[id]
+	delete(t.timers, id)
+	t.mu.Unlock()
+
+	if exists {
+		timer.Stop()
+	}
+}
+
+// ClearTimeout cancels a timeout (like clearTimeout() in TypeScript)
+func (t *Timers) ClearTimeout(id TimerID) {
+	t.clear(id)
+}
+
+// ClearInterval cancels an interval (like clearInterval() in TypeScript)
+func (t *Timers) ClearInterval(id TimerID) {
+	t.clear(id)
+}
+
+// Active returns the number of scheduled timeouts and intervals
+func (t *Timers) Active() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.timers)
+}
+
+// global is the Timers instance behind the package-level functions
+var global = NewTimers()
+
+// SetTimeout calls fn once after delay using the default clock (like setTimeout() in TypeScript)
+func SetTimeout(fn func(), delay time.Duration) TimerID {
+	return global.SetTimeout(fn, delay)
+}
+
+// SetInterval calls fn every interval using the default clock (like setInterval() in TypeScript)
+func SetInterval(fn func(), interval time.Duration) TimerID {
+	return global.SetInterval(fn, interval)
+}
+
+// ClearTimeout cancels a timeout created by SetTimeout (like clearTimeout() in TypeScript)
+func ClearTimeout(id TimerID) {
+	global.ClearTimeout(id)
+}
+
+// ClearInterval cancels an interval created by SetInterval (like clearInterval() in TypeScript)
+func ClearInterval(id TimerID) {
+	global.ClearInterval(id)
+}
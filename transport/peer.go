@@ -0,0 +1,506 @@
+package transport
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"typescript-golang/timers"
+	"typescript-golang/types"
+)
+
+// Options configures a Peer or Server
+type Options struct {
+	// Node names this process in forwarded messages (default hostname:pid)
+	Node string
+	// ReconnectDelay is the wait before the first reconnect attempt (default 100ms); it doubles
+	// up to MaxReconnectDelay (default 5s)
+	ReconnectDelay    time.Duration
+	MaxReconnectDelay time.Duration
+	// WriteTimeout bounds the write of one frame on connections with write deadlines, such as
+	// sockets (default 10s). A peer that stops reading then fails sends instead of blocking them.
+	WriteTimeout time.Duration
+	// Clock replaces the default clock for reconnect delays, e.g. a timers.FakeClock in tests
+	Clock timers.Clock
+}
+
+// resolveOptions fills in the option defaults
+func resolveOptions(options []Options) Options {
+	var opts Options
+	if len(options) > 0 {
+		opts = options[0]
+	}
+	if opts.Node == "" {
+		host, _ := os.Hostname()
+		opts.Node = fmt.Sprintf("%s:%d", host, os.Getpid())
+	}
+	if opts.ReconnectDelay <= 0 {
+		opts.ReconnectDelay = 100 * time.Millisecond
+	}
+	if opts.MaxReconnectDelay <= 0 {
+		opts.MaxReconnectDelay = 5 * time.Second
+	}
+	if opts.WriteTimeout <= 0 {
+		opts.WriteTimeout = 10 * time.Second
+	}
+	opts.Clock = timers.Resolve(opts.Clock)
+	return opts
+}
+
+// Peer links a local EventBus with the bus at the other end of a connection. Topics passed to
+// Subscribe are forwarded from the remote bus to the local one and republished there with the
+// OriginHeader set. Only messages published locally are forwarded, so linked buses never echo
+// messages back and forth. A peer created by Connect reconnects when the connection drops and
+// restores its subscriptions; messages published while it is down are not forwarded.
+type Peer struct {
+	bus      *types.EventBus
+	options  Options
+	dial     func() (Conn, error)
+	mu       sync.Mutex
+	conn     Conn
+	remote   string
+	ready    chan struct{}
+	wanted   map[string]bool
+	forwards map[string]*types.Subscription
+	closed   bool
+	done     chan struct{}
+}
+
+// newPeer creates an unconnected peer
+func newPeer(bus *types.EventBus, options Options, dial func() (Conn, error)) *Peer {
+	return &Peer{
+		bus:      bus,
+		options:  options,
+		dial:     dial,
+		ready:    make(chan struct{}),
+		wanted:   make(map[string]bool),
+		forwards: make(map[string]*types.Subscription),
+		done:     make(chan struct{}),
+	}
+}
+
+// Connect links a bus to the bus listening at the transport. It returns immediately and keeps
+// (re)connecting in the background until Close; use WaitConnected to wait for the link.
+func Connect(bus *types.EventBus, transport Transport, options ...Options) *Peer {
+	p := newPeer(bus, resolveOptions(options), transport.Dial)
+	go p.reconnectLoop()
+	return p
+}
+
+// Node returns the name of the remote node once connected
+func (p *Peer) Node() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.remote
+}
+
+// Connected reports whether the peer has a live connection
+func (p *Peer) Connected() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.conn != nil
+}
+
+// WaitConnected waits until the peer is connected, or fails with a TimeoutError
+func (p *Peer) WaitConnected(timeout time.Duration) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return ErrClosed
+	}
+	ready := p.ready
+	p.mu.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-ready:
+		return nil
+	case <-p.done:
+		return ErrClosed
+	case <-timer.C:
+		return types.NewError(fmt.Sprintf("not connected after %v", timeout), types.TimeoutError)
+	}
+}
+
+// Subscribe asks the remote bus to forward the messages of topics or patterns to the local bus
+func (p *Peer) Subscribe(topics ...string) error {
+	p.mu.Lock()
+	for _, topic := range topics {
+		p.wanted[topic] = true
+	}
+	p.mu.Unlock()
+
+	for _, topic := range topics {
+		if err := p.send(frame[interface{}]{Kind: frameSubscribe, Topic: topic}); err != nil && err != ErrNotConnected {
+			return err
+		}
+	}
+	return nil
+}
+
+// Unsubscribe stops forwarding topics from the remote bus
+func (p *Peer) Unsubscribe(topics ...string) error {
+	p.mu.Lock()
+	for _, topic := range topics {
+		delete(p.wanted, topic)
+	}
+	p.mu.Unlock()
+
+	for _, topic := range topics {
+		if err := p.send(frame[interface{}]{Kind: frameUnsubscribe, Topic: topic}); err != nil && err != ErrNotConnected {
+			return err
+		}
+	}
+	return nil
+}
+
+// Subscriptions returns the topics the peer receives from the remote bus
+func (p *Peer) Subscriptions() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	topics := make([]string, 0, len(p.wanted))
+	for topic := range p.wanted {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+	return topics
+}
+
+// Close disconnects the peer and stops reconnecting
+func (p *Peer) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	close(p.done)
+	conn := p.conn
+	p.mu.Unlock()
+
+	if conn != nil {
+		conn.Close()
+	}
+	p.dropForwards()
+	return nil
+}
+
+// reconnectLoop dials until connected, serves the connection, and repeats after it drops
+func (p *Peer) reconnectLoop() {
+	delay := p.options.ReconnectDelay
+	for {
+		conn, err := p.dial()
+		if err == nil {
+			delay = p.options.ReconnectDelay
+			p.serve(conn)
+		}
+
+		timer := p.options.Clock.NewTimer(delay)
+		select {
+		case <-p.done:
+			timer.Stop()
+			return
+		case <-timer.C():
+		}
+		if delay *= 2; delay > p.options.MaxReconnectDelay {
+			delay = p.options.MaxReconnectDelay
+		}
+	}
+}
+
+// serve runs a connection until it fails or the peer is closed
+func (p *Peer) serve(conn Conn) {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		conn.Close()
+		return
+	}
+	p.conn = conn
+	wanted := make([]string, 0, len(p.wanted))
+	for topic := range p.wanted {
+		wanted = append(wanted, topic)
+	}
+	p.mu.Unlock()
+
+	// Introduce this node and restore the subscriptions before anything else is read
+	p.send(frame[interface{}]{Kind: frameHello, Node: p.options.Node})
+	sort.Strings(wanted)
+	for _, topic := range wanted {
+		p.send(frame[interface{}]{Kind: frameSubscribe, Topic: topic})
+	}
+
+	p.mu.Lock()
+	close(p.ready)
+	p.mu.Unlock()
+
+	for {
+		data, err := conn.ReadFrame()
+		if err != nil {
+			break
+		}
+		p.receive(data)
+	}
+
+	p.mu.Lock()
+	p.conn = nil
+	p.ready = make(chan struct{})
+	p.mu.Unlock()
+	conn.Close()
+	p.dropForwards()
+}
+
+// receive handles one frame from the remote bus
+func (p *Peer) receive(data []byte) {
+	f, err := decodeFrame(data)
+	if err != nil {
+		fmt.Printf("Warning: dropping malformed transport frame: %v\n", err)
+		return
+	}
+
+	switch f.Kind {
+	case frameHello:
+		p.mu.Lock()
+		p.remote = f.Node
+		p.mu.Unlock()
+	case frameSubscribe:
+		p.forward(f.Topic)
+	case frameUnsubscribe:
+		p.mu.Lock()
+		subscription := p.forwards[f.Topic]
+		delete(p.forwards, f.Topic)
+		p.mu.Unlock()
+		if subscription != nil {
+			subscription.Unsubscribe()
+		}
+	case frameEvent:
+		if f.Event == nil {
+			return
+		}
+		payload, err := p.bus.DecodePayload(f.Event.Type, f.Event.Data)
+		if err != nil {
+			fmt.Printf("Warning: dropping transport event '%s': %v\n", f.Event.Type, err)
+			return
+		}
+		headers := f.Headers
+		if headers == nil {
+			headers = make(map[string]string)
+		}
+		if headers[OriginHeader] == "" {
+			headers[OriginHeader] = fmt.Sprint(f.Event.Source)
+		}
+		err = p.bus.PublishMessage(&types.BusMessage{
+			ID:        f.ID,
+			Topic:     f.Event.Type,
+			Payload:   payload,
+			Headers:   headers,
+			Timestamp: f.Event.Timestamp,
+		})
+		if err != nil {
+			fmt.Printf("Warning: dropping transport event '%s': %v\n", f.Event.Type, err)
+		}
+	}
+}
+
+// forward starts forwarding a topic of the local bus to the remote bus
+func (p *Peer) forward(topic string) {
+	p.mu.Lock()
+	if _, exists := p.forwards[topic]; exists || p.closed {
+		p.mu.Unlock()
+		return
+	}
+	// Reserve the topic so concurrent subscribe frames add one handler
+	p.forwards[topic] = nil
+	p.mu.Unlock()
+
+	subscription := p.bus.Handle(topic, func(msg *types.BusMessage) error {
+		if msg.Headers[OriginHeader] != "" {
+			return nil
+		}
+		event := types.NewEvent[interface{}](msg.Topic, msg.Payload, p.options.Node)
+		event.Timestamp = msg.Timestamp
+		return p.send(frame[interface{}]{Kind: frameEvent, ID: msg.ID, Headers: msg.Headers, Event: event})
+	}, types.SubscribeOptions{Name: "transport:" + topic})
+
+	p.mu.Lock()
+	if current, exists := p.forwards[topic]; exists && current == nil {
+		p.forwards[topic] = subscription
+		subscription = nil
+	}
+	p.mu.Unlock()
+	// The topic was unsubscribed or the connection dropped meanwhile
+	if subscription != nil {
+		subscription.Unsubscribe()
+	}
+}
+
+// dropForwards stops forwarding every topic
+func (p *Peer) dropForwards() {
+	p.mu.Lock()
+	forwards := p.forwards
+	p.forwards = make(map[string]*types.Subscription)
+	p.mu.Unlock()
+
+	for _, subscription := range forwards {
+		if subscription != nil {
+			subscription.Unsubscribe()
+		}
+	}
+}
+
+// deadlineConn is a Conn whose writes can time out, like a socket
+type deadlineConn interface {
+	SetWriteDeadline(t time.Time) error
+}
+
+// send encodes and writes a frame. A failed write may leave part of a frame on the
+// connection, so the connection is closed and the peer reconnects.
+func (p *Peer) send(f frame[interface{}]) error {
+	p.mu.Lock()
+	conn, closed := p.conn, p.closed
+	p.mu.Unlock()
+
+	if closed {
+		return ErrClosed
+	}
+	if conn == nil {
+		return ErrNotConnected
+	}
+	data, err := encodeFrame(f)
+	if err != nil {
+		return err
+	}
+	// Socket deadlines are wall-clock times, so they do not use the options' clock
+	if dc, ok := conn.(deadlineConn); ok {
+		dc.SetWriteDeadline(time.Now().Add(p.options.WriteTimeout))
+	}
+	if err := conn.WriteFrame(data); err != nil {
+		conn.Close()
+		return err
+	}
+	return nil
+}
+
+// Server accepts peers for a bus from other processes
+type Server struct {
+	bus      *types.EventBus
+	listener Listener
+	options  Options
+	mu       sync.Mutex
+	peers    map[*Peer]bool
+	wanted   []string
+	closed   bool
+	done     chan struct{}
+}
+
+// Serve listens on the transport and links every connecting process to the bus
+func Serve(bus *types.EventBus, transport Transport, options ...Options) (*Server, error) {
+	listener, err := transport.Listen()
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		bus:      bus,
+		listener: listener,
+		options:  resolveOptions(options),
+		peers:    make(map[*Peer]bool),
+		done:     make(chan struct{}),
+	}
+	go s.acceptLoop()
+	return s, nil
+}
+
+// Addr returns the address the server listens on
+func (s *Server) Addr() string {
+	return s.listener.Addr()
+}
+
+// acceptLoop serves every accepted connection with its own peer
+func (s *Server) acceptLoop() {
+	defer close(s.done)
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		p := newPeer(s.bus, s.options, nil)
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return
+		}
+		for _, topic := range s.wanted {
+			p.wanted[topic] = true
+		}
+		s.peers[p] = true
+		s.mu.Unlock()
+
+		go func() {
+			p.serve(conn)
+			p.Close()
+			s.mu.Lock()
+			delete(s.peers, p)
+			s.mu.Unlock()
+		}()
+	}
+}
+
+// Subscribe asks every current and future peer to forward topics to the server's bus
+func (s *Server) Subscribe(topics ...string) error {
+	s.mu.Lock()
+	s.wanted = append(s.wanted, topics...)
+	peers := s.snapshot()
+	s.mu.Unlock()
+
+	for _, p := range peers {
+		if err := p.Subscribe(topics...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Peers returns the connected peers
+func (s *Server) Peers() []*Peer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.snapshot()
+}
+
+// snapshot returns the peers; s.mu must be held
+func (s *Server) snapshot() []*Peer {
+	peers := make([]*Peer, 0, len(s.peers))
+	for p := range s.peers {
+		peers = append(peers, p)
+	}
+	return peers
+}
+
+// Close stops listening and disconnects every peer
+func (s *Server) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	peers := s.snapshot()
+	s.mu.Unlock()
+
+	err := s.listener.Close()
+	<-s.done
+	for _, p := range peers {
+		p.Close()
+	}
+	return err
+}
@@ -0,0 +1,137 @@
+package transport
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"typescript-golang/types"
+)
+
+// MaxFrameSize bounds the size of a frame read from a socket
+const MaxFrameSize = 16 << 20
+
+// SocketTransport connects processes over a Unix-domain or TCP socket, framing each message
+// as a 4-byte big-endian length followed by JSON
+type SocketTransport struct {
+	Network string
+	Address string
+}
+
+// NewUnixTransport creates a transport over the Unix-domain socket at path
+func NewUnixTransport(path string) *SocketTransport {
+	return &SocketTransport{Network: "unix", Address: path}
+}
+
+// NewTCPTransport creates a transport over TCP, e.g. "127.0.0.1:7070"
+func NewTCPTransport(address string) *SocketTransport {
+	return &SocketTransport{Network: "tcp", Address: address}
+}
+
+// Dial connects to a listening transport
+func (t *SocketTransport) Dial() (Conn, error) {
+	conn, err := net.Dial(t.Network, t.Address)
+	if err != nil {
+		return nil, types.WrapError(err, fmt.Sprintf("failed to dial %s %s", t.Network, t.Address), types.NetworkError)
+	}
+	return NewStreamConn(conn), nil
+}
+
+// Listen listens for connections. A stale Unix socket file at the address is removed first.
+func (t *SocketTransport) Listen() (Listener, error) {
+	if t.Network == "unix" {
+		if info, err := os.Stat(t.Address); err == nil && info.Mode()&os.ModeSocket != 0 {
+			os.Remove(t.Address)
+		}
+	}
+	listener, err := net.Listen(t.Network, t.Address)
+	if err != nil {
+		return nil, types.WrapError(err, fmt.Sprintf("failed to listen on %s %s", t.Network, t.Address), types.NetworkError)
+	}
+	return &socketListener{listener: listener}, nil
+}
+
+// socketListener adapts a net.Listener
+type socketListener struct {
+	listener net.Listener
+}
+
+func (l *socketListener) Accept() (Conn, error) {
+	conn, err := l.listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return NewStreamConn(conn), nil
+}
+
+func (l *socketListener) Close() error {
+	return l.listener.Close()
+}
+
+func (l *socketListener) Addr() string {
+	return l.listener.Addr().String()
+}
+
+// streamConn frames messages over a byte stream
+type streamConn struct {
+	stream io.ReadWriteCloser
+	reader *bufio.Reader
+	mu     sync.Mutex
+}
+
+// NewStreamConn frames messages with a 4-byte length prefix over a byte stream, such as a
+// net.Conn or one end of a net.Pipe
+func NewStreamConn(stream io.ReadWriteCloser) Conn {
+	return &streamConn{stream: stream, reader: bufio.NewReader(stream)}
+}
+
+// WriteFrame writes one frame
+func (c *streamConn) WriteFrame(frame []byte) error {
+	if len(frame) > MaxFrameSize {
+		return types.NewError(fmt.Sprintf("frame of %d bytes exceeds %d", len(frame), MaxFrameSize), types.ValidationError)
+	}
+	buf := make([]byte, 4+len(frame))
+	binary.BigEndian.PutUint32(buf, uint32(len(frame)))
+	copy(buf[4:], frame)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, err := c.stream.Write(buf)
+	return err
+}
+
+// ReadFrame reads one frame
+func (c *streamConn) ReadFrame() ([]byte, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(header[:])
+	if size > MaxFrameSize {
+		return nil, types.NewError(fmt.Sprintf("frame of %d bytes exceeds %d", size, MaxFrameSize), types.ValidationError)
+	}
+	frame := make([]byte, size)
+	if _, err := io.ReadFull(c.reader, frame); err != nil {
+		return nil, err
+	}
+	return frame, nil
+}
+
+// SetWriteDeadline sets the write deadline of streams that support one, like net.Conn
+func (c *streamConn) SetWriteDeadline(t time.Time) error {
+	if dc, ok := c.stream.(deadlineConn); ok {
+		return dc.SetWriteDeadline(t)
+	}
+	return nil
+}
+
+// Close closes the stream
+func (c *streamConn) Close() error {
+	return c.stream.Close()
+}
@@ -0,0 +1,76 @@
+package transport
+
+import (
+	"encoding/json"
+	"errors"
+
+	"typescript-golang/types"
+	"typescript-golang/utils"
+)
+
+// OriginHeader is the bus message header naming the node a remote message was published on
+const OriginHeader = "x-origin"
+
+var (
+	// ErrNotConnected is returned when sending while the connection is down
+	ErrNotConnected = errors.New("transport is not connected")
+	// ErrClosed is returned by a closed peer or server
+	ErrClosed = errors.New("transport is closed")
+)
+
+// Conn is a connection that carries whole frames in both directions. WriteFrame may be called
+// concurrently with ReadFrame. A Conn with a SetWriteDeadline(time.Time) error method, like the
+// socket connections, gets a deadline of Options.WriteTimeout before every frame.
+type Conn interface {
+	WriteFrame(frame []byte) error
+	ReadFrame() ([]byte, error)
+	Close() error
+}
+
+// Listener accepts connections from other processes
+type Listener interface {
+	Accept() (Conn, error)
+	Close() error
+	Addr() string
+}
+
+// Transport opens connections that link EventBus instances in different processes (like a
+// socket.io bridge between Node.js processes). SocketTransport is the built-in implementation;
+// implement Transport to carry bus traffic over other channels, e.g. WebSockets.
+type Transport interface {
+	Dial() (Conn, error)
+	Listen() (Listener, error)
+}
+
+// Frame kinds
+const (
+	frameHello       = "hello"
+	frameSubscribe   = "subscribe"
+	frameUnsubscribe = "unsubscribe"
+	frameEvent       = "event"
+)
+
+// frame is the JSON message exchanged between peers. Event payloads travel as types.Event,
+// with the publishing node as Source.
+type frame[T any] struct {
+	Kind    string            `json:"kind"`
+	Node    string            `json:"node,omitempty"`
+	Topic   string            `json:"topic,omitempty"`
+	ID      string            `json:"id,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Event   *types.Event[T]   `json:"event,omitempty"`
+}
+
+// encodeFrame encodes a frame as JSON
+func encodeFrame(f frame[interface{}]) ([]byte, error) {
+	encoded, err := utils.JSON.StringifyCompact(f)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(encoded), nil
+}
+
+// decodeFrame decodes a frame, leaving the event payload raw until its topic type is known
+func decodeFrame(data []byte) (frame[json.RawMessage], error) {
+	return utils.ParseTo[frame[json.RawMessage]](string(data))
+}
@@ -0,0 +1,138 @@
+package transport
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"typescript-golang/types"
+)
+
+// eventually polls cond until it holds or a second has passed
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// receive subscribes to a topic and returns the channel of its messages
+func receive(bus *types.EventBus, topic string) <-chan *types.BusMessage {
+	messages := make(chan *types.BusMessage, 16)
+	bus.Handle(topic, func(msg *types.BusMessage) error {
+		messages <- msg
+		return nil
+	})
+	return messages
+}
+
+// expectMessage waits for a message with the given payload
+func expectMessage(t *testing.T, messages <-chan *types.BusMessage, payload interface{}) *types.BusMessage {
+	t.Helper()
+	select {
+	case msg := <-messages:
+		if msg.Payload != payload {
+			t.Fatalf("payload = %v, want %v", msg.Payload, payload)
+		}
+		return msg
+	case <-time.After(time.Second):
+		t.Fatalf("no message with payload %v", payload)
+		return nil
+	}
+}
+
+func TestUnixSocketPeers(t *testing.T) {
+	socket := NewUnixTransport(filepath.Join(t.TempDir(), "bus.sock"))
+	serverBus := types.NewEventBus()
+	clientBus := types.NewEventBus()
+	defer serverBus.Close()
+	defer clientBus.Close()
+
+	server, err := Serve(serverBus, socket, Options{Node: "server"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	if err := server.Subscribe("metrics:*"); err != nil {
+		t.Fatal(err)
+	}
+
+	peer := Connect(clientBus, socket, Options{Node: "client", ReconnectDelay: 10 * time.Millisecond})
+	defer peer.Close()
+	if err := peer.WaitConnected(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := peer.Subscribe("orders:*"); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "the subscriptions to be forwarded", func() bool {
+		return serverBus.SubscriberCount("orders:created") == 1 && clientBus.SubscriberCount("metrics:cpu") == 1
+	})
+
+	orders := receive(clientBus, "orders:created")
+	metrics := receive(serverBus, "metrics:cpu")
+
+	// Server to client
+	serverBus.Publish("orders:created", "order-1")
+	if msg := expectMessage(t, orders, "order-1"); msg.Headers[OriginHeader] != "server" {
+		t.Fatalf("origin = %q, want server", msg.Headers[OriginHeader])
+	}
+	if peer.Node() != "server" {
+		t.Fatalf("remote node = %q, want server", peer.Node())
+	}
+
+	// Client to server
+	clientBus.Publish("metrics:cpu", "42%")
+	if msg := expectMessage(t, metrics, "42%"); msg.Headers[OriginHeader] != "client" {
+		t.Fatalf("origin = %q, want client", msg.Headers[OriginHeader])
+	}
+
+	// Republished messages are not echoed back
+	select {
+	case msg := <-metrics:
+		t.Fatalf("unexpected message %v", msg.Payload)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	// The client reconnects after the server drops its end and restores its subscriptions
+	peers := server.Peers()
+	if len(peers) != 1 {
+		t.Fatalf("server has %d peers, want 1", len(peers))
+	}
+	peers[0].Close()
+	eventually(t, "the client to reconnect", func() bool {
+		current := server.Peers()
+		return len(current) == 1 && current[0] != peers[0] &&
+			serverBus.SubscriberCount("orders:created") == 1 && clientBus.SubscriberCount("metrics:cpu") == 1
+	})
+
+	serverBus.Publish("orders:created", "order-2")
+	expectMessage(t, orders, "order-2")
+	clientBus.Publish("metrics:cpu", "43%")
+	expectMessage(t, metrics, "43%")
+}
+
+func TestSendTimesOutWhenRemoteStopsReading(t *testing.T) {
+	local, remote := net.Pipe()
+	defer remote.Close()
+
+	p := newPeer(types.NewEventBus(), resolveOptions([]Options{{WriteTimeout: 20 * time.Millisecond}}), nil)
+	p.conn = NewStreamConn(local)
+
+	start := time.Now()
+	if err := p.send(frame[interface{}]{Kind: frameHello, Node: "local"}); err == nil {
+		t.Fatal("send succeeded without a reader")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("send blocked for %v", elapsed)
+	}
+	// The connection is closed, so the read loop ends and the peer reconnects
+	if _, err := p.conn.ReadFrame(); err == nil {
+		t.Fatal("connection still open after a failed write")
+	}
+}
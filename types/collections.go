@@ -2,20 +2,146 @@ package types
 
 import (
 	"fmt"
-	"reflect"
 )
 
-// Map represents TypeScript's Map<K, V> data structure
+// orderedEntry is an entry of an orderedEntries list. A deleted entry keeps its links so that
+// iterators positioned on it can continue.
+type orderedEntry[K comparable, V any] struct {
+	key     K
+	value   V
+	prev    *orderedEntry[K, V]
+	next    *orderedEntry[K, V]
+	deleted bool
+}
+
+// orderedEntries is a hash map whose entries are linked in insertion order, giving O(1)
+// set and delete with the iteration order of a JavaScript Map
+type orderedEntries[K comparable, V any] struct {
+	index map[K]*orderedEntry[K, V]
+	head  *orderedEntry[K, V]
+	tail  *orderedEntry[K, V]
+}
+
+func newOrderedEntries[K comparable, V any]() orderedEntries[K, V] {
+	return orderedEntries[K, V]{index: make(map[K]*orderedEntry[K, V])}
+}
+
+// set updates an existing entry in place or appends a new one; it reports whether it appended
+func (o *orderedEntries[K, V]) set(key K, value V) bool {
+	if entry, exists := o.index[key]; exists {
+		entry.value = value
+		return false
+	}
+	entry := &orderedEntry[K, V]{key: key, value: value, prev: o.tail}
+	if o.tail != nil {
+		o.tail.next = entry
+	} else {
+		o.head = entry
+	}
+	o.tail = entry
+	o.index[key] = entry
+	return true
+}
+
+// get returns the entry of a key, or nil
+func (o *orderedEntries[K, V]) get(key K) *orderedEntry[K, V] {
+	return o.index[key]
+}
+
+// delete unlinks the entry of a key
+func (o *orderedEntries[K, V]) delete(key K) bool {
+	entry, exists := o.index[key]
+	if !exists {
+		return false
+	}
+	delete(o.index, key)
+	entry.deleted = true
+	if entry.prev != nil {
+		entry.prev.next = entry.next
+	} else {
+		o.head = entry.next
+	}
+	if entry.next != nil {
+		entry.next.prev = entry.prev
+	} else {
+		o.tail = entry.prev
+	}
+	return true
+}
+
+// clear deletes all entries
+func (o *orderedEntries[K, V]) clear() {
+	for entry := o.head; entry != nil; entry = entry.next {
+		entry.deleted = true
+	}
+	o.index = make(map[K]*orderedEntry[K, V])
+	o.head, o.tail = nil, nil
+}
+
+// size returns the number of entries
+func (o *orderedEntries[K, V]) size() int {
+	return len(o.index)
+}
+
+// after returns the first live entry after cursor, or the first entry for a nil cursor. A
+// deleted cursor resumes after its nearest live predecessor, so entries added and deleted
+// during an iteration are visited and skipped like in JavaScript.
+func (o *orderedEntries[K, V]) after(cursor *orderedEntry[K, V]) *orderedEntry[K, V] {
+	for cursor != nil && cursor.deleted {
+		cursor = cursor.prev
+	}
+	if cursor == nil {
+		return o.head
+	}
+	return cursor.next
+}
+
+// each calls fn with every entry in insertion order, including entries added by fn
+func (o *orderedEntries[K, V]) each(fn func(entry *orderedEntry[K, V])) {
+	for entry := o.after(nil); entry != nil; entry = o.after(entry) {
+		fn(entry)
+	}
+}
+
+// orderedIterator iterates over orderedEntries, projecting each entry to a value
+type orderedIterator[K comparable, V, T any] struct {
+	entries *orderedEntries[K, V]
+	cursor  *orderedEntry[K, V]
+	done    bool
+	project func(entry *orderedEntry[K, V]) T
+}
+
+// Next returns the next value, or false when the iteration is done
+func (it *orderedIterator[K, V, T]) Next() (T, bool) {
+	var zero T
+	if it.done {
+		return zero, false
+	}
+	next := it.entries.after(it.cursor)
+	if next == nil {
+		// Like a JavaScript iterator, a finished iterator stays finished
+		it.done = true
+		return zero, false
+	}
+	it.cursor = next
+	return it.project(next), true
+}
+
+// HasNext reports whether Next returns a value
+func (it *orderedIterator[K, V, T]) HasNext() bool {
+	return !it.done && it.entries.after(it.cursor) != nil
+}
+
+// Map represents TypeScript's Map<K, V> data structure. Iteration follows insertion order, and
+// setting an existing key keeps its position.
 type Map[K comparable, V any] struct {
-	data map[K]V
-	size int
+	entries orderedEntries[K, V]
 }
 
 // NewMap creates a new Map instance (like new Map() in TypeScript)
 func NewMap[K comparable, V any]() *Map[K, V] {
 	return &Map[K, V]{
-		data: make(map[K]V),
-		size: 0,
+		entries: newOrderedEntries[K, V](),
 	}
 }
 
@@ -30,93 +156,107 @@ func NewMapWithEntries[K comparable, V any](entries []Tuple2[K, V]) *Map[K, V] {
 
 // Set adds or updates a key-value pair (like map.set() in TypeScript)
 func (m *Map[K, V]) Set(key K, value V) *Map[K, V] {
-	if _, exists := m.data[key]; !exists {
-		m.size++
-	}
-	m.data[key] = value
+	m.entries.set(key, value)
 	return m
 }
 
 // Get retrieves a value by key (like map.get() in TypeScript)
 func (m *Map[K, V]) Get(key K) Optional[V] {
-	if value, exists := m.data[key]; exists {
-		return Some(value)
+	if entry := m.entries.get(key); entry != nil {
+		return Some(entry.value)
 	}
 	return None[V]()
 }
 
 // Has checks if a key exists (like map.has() in TypeScript)
 func (m *Map[K, V]) Has(key K) bool {
-	_, exists := m.data[key]
-	return exists
+	return m.entries.get(key) != nil
 }
 
 // Delete removes a key-value pair (like map.delete() in TypeScript)
 func (m *Map[K, V]) Delete(key K) bool {
-	if _, exists := m.data[key]; exists {
-		delete(m.data, key)
-		m.size--
-		return true
-	}
-	return false
+	return m.entries.delete(key)
 }
 
 // Clear removes all entries (like map.clear() in TypeScript)
 func (m *Map[K, V]) Clear() {
-	m.data = make(map[K]V)
-	m.size = 0
+	m.entries.clear()
 }
 
 // Size returns the number of entries (like map.size in TypeScript)
 func (m *Map[K, V]) Size() int {
-	return m.size
+	return m.entries.size()
 }
 
 // IsEmpty checks if the map is empty
 func (m *Map[K, V]) IsEmpty() bool {
-	return m.size == 0
+	return m.entries.size() == 0
 }
 
-// Keys returns all keys (like map.keys() in TypeScript)
+// Keys returns all keys in insertion order (like map.keys() in TypeScript)
 func (m *Map[K, V]) Keys() []K {
-	keys := make([]K, 0, m.size)
-	for key := range m.data {
-		keys = append(keys, key)
+	keys := make([]K, 0, m.entries.size())
+	for entry := m.entries.head; entry != nil; entry = entry.next {
+		keys = append(keys, entry.key)
 	}
 	return keys
 }
 
-// Values returns all values (like map.values() in TypeScript)
+// Values returns all values in insertion order (like map.values() in TypeScript)
 func (m *Map[K, V]) Values() []V {
-	values := make([]V, 0, m.size)
-	for _, value := range m.data {
-		values = append(values, value)
+	values := make([]V, 0, m.entries.size())
+	for entry := m.entries.head; entry != nil; entry = entry.next {
+		values = append(values, entry.value)
 	}
 	return values
 }
 
-// Entries returns all key-value pairs (like map.entries() in TypeScript)
+// Entries returns all key-value pairs in insertion order (like map.entries() in TypeScript)
 func (m *Map[K, V]) Entries() []Tuple2[K, V] {
-	entries := make([]Tuple2[K, V], 0, m.size)
-	for key, value := range m.data {
-		entries = append(entries, NewTuple2(key, value))
+	entries := make([]Tuple2[K, V], 0, m.entries.size())
+	for entry := m.entries.head; entry != nil; entry = entry.next {
+		entries = append(entries, NewTuple2(entry.key, entry.value))
 	}
 	return entries
 }
 
-// ForEach iterates over all entries (like map.forEach() in TypeScript)
+// KeysIter returns a live iterator over the keys (like map.keys() in TypeScript). It visits keys
+// added during the iteration and skips deleted ones.
+func (m *Map[K, V]) KeysIter() Iterator[K] {
+	return &orderedIterator[K, V, K]{entries: &m.entries, project: func(entry *orderedEntry[K, V]) K {
+		return entry.key
+	}}
+}
+
+// ValuesIter returns a live iterator over the values (like map.values() in TypeScript)
+func (m *Map[K, V]) ValuesIter() Iterator[V] {
+	return &orderedIterator[K, V, V]{entries: &m.entries, project: func(entry *orderedEntry[K, V]) V {
+		return entry.value
+	}}
+}
+
+// EntriesIter returns a live iterator over the key-value pairs (like map.entries() in
+// TypeScript)
+func (m *Map[K, V]) EntriesIter() Iterator[Tuple2[K, V]] {
+	return &orderedIterator[K, V, Tuple2[K, V]]{entries: &m.entries, project: func(entry *orderedEntry[K, V]) Tuple2[K, V] {
+		return NewTuple2(entry.key, entry.value)
+	}}
+}
+
+// ForEach iterates over all entries in insertion order (like map.forEach() in TypeScript).
+// Entries added by fn are visited and entries deleted by fn are skipped.
 func (m *Map[K, V]) ForEach(fn func(value V, key K, map_ *Map[K, V])) {
-	for key, value := range m.data {
-		fn(value, key, m)
-	}
+	m.entries.each(func(entry *orderedEntry[K, V]) {
+		fn(entry.value, entry.key, m)
+	})
 }
 
 // Filter creates a new map with entries that pass the test
 func (m *Map[K, V]) Filter(predicate func(value V, key K) bool) *Map[K, V] {
 	result := NewMap[K, V]()
-	for key, value := range m.data {
-		if predicate(value, key) {
-			result.Set(key, value)
+	for entry := m.entries.head; entry != nil; entry = entry.next {
+		if predicate(entry.value, entry.key) {
+			result.Set(entry.key, entry.value)
 		}
 	}
 	return result
@@ -125,8 +265,8 @@ func (m *Map[K, V]) Filter(predicate func(value V, key K) bool) *Map[K, V] {
 // Map transforms values and returns a new map
 func MapTransform[K comparable, V, U any](m *Map[K, V], fn func(value V, key K) U) *Map[K, U] {
 	result := NewMap[K, U]()
-	for key, value := range m.data {
-		result.Set(key, fn(value, key))
+	for entry := m.entries.head; entry != nil; entry = entry.next {
+		result.Set(entry.key, fn(entry.value, entry.key))
 	}
 	return result
 }
@@ -134,28 +274,26 @@ func MapTransform[K comparable, V, U any](m *Map[K, V], fn func(value V, key K)
 // Clone creates a shallow copy of the map
 func (m *Map[K, V]) Clone() *Map[K, V] {
 	result := NewMap[K, V]()
-	for key, value := range m.data {
-		result.Set(key, value)
+	for entry := m.entries.head; entry != nil; entry = entry.next {
+		result.Set(entry.key, entry.value)
 	}
 	return result
 }
 
 // String returns string representation
 func (m *Map[K, V]) String() string {
-	return fmt.Sprintf("Map{size: %d}", m.size)
+	return fmt.Sprintf("Map{size: %d}", m.entries.size())
 }
 
-// Set represents TypeScript's Set<T> data structure
+// Set represents TypeScript's Set<T> data structure. Iteration follows insertion order.
 type Set[T comparable] struct {
-	data map[T]struct{}
-	size int
+	entries orderedEntries[T, struct{}]
 }
 
 // NewSet creates a new Set instance (like new Set() in TypeScript)
 func NewSet[T comparable]() *Set[T] {
 	return &Set[T]{
-		data: make(map[T]struct{}),
-		size: 0,
+		entries: newOrderedEntries[T, struct{}](),
 	}
 }
 
@@ -170,69 +308,68 @@ func NewSetWithValues[T comparable](values []T) *Set[T] {
 
 // Add adds a value to the set (like set.add() in TypeScript)
 func (s *Set[T]) Add(value T) *Set[T] {
-	if _, exists := s.data[value]; !exists {
-		s.data[value] = struct{}{}
-		s.size++
-	}
+	s.entries.set(value, struct{}{})
 	return s
 }
 
 // Has checks if a value exists in the set (like set.has() in TypeScript)
 func (s *Set[T]) Has(value T) bool {
-	_, exists := s.data[value]
-	return exists
+	return s.entries.get(value) != nil
 }
 
 // Delete removes a value from the set (like set.delete() in TypeScript)
 func (s *Set[T]) Delete(value T) bool {
-	if _, exists := s.data[value]; exists {
-		delete(s.data, value)
-		s.size--
-		return true
-	}
-	return false
+	return s.entries.delete(value)
 }
 
 // Clear removes all values (like set.clear() in TypeScript)
 func (s *Set[T]) Clear() {
-	s.data = make(map[T]struct{})
-	s.size = 0
+	s.entries.clear()
 }
 
 // Size returns the number of values (like set.size in TypeScript)
 func (s *Set[T]) Size() int {
-	return s.size
+	return s.entries.size()
 }
 
 // IsEmpty checks if the set is empty
 func (s *Set[T]) IsEmpty() bool {
-	return s.size == 0
+	return s.entries.size() == 0
 }
 
-// Values returns all values (like set.values() in TypeScript)
+// Values returns all values in insertion order (like set.values() in TypeScript)
 func (s *Set[T]) Values() []T {
-	values := make([]T, 0, s.size)
-	for value := range s.data {
-		values = append(values, value)
+	values := make([]T, 0, s.entries.size())
+	for entry := s.entries.head; entry != nil; entry = entry.next {
+		values = append(values, entry.key)
 	}
 	return values
 }
 
-// ForEach iterates over all values (like set.forEach() in TypeScript)
+// ValuesIter returns a live iterator over the values (like set.values() in TypeScript). It
+// visits values added during the iteration and skips deleted ones.
+func (s *Set[T]) ValuesIter() Iterator[T] {
+	return &orderedIterator[T, struct{}, T]{entries: &s.entries, project: func(entry *orderedEntry[T, struct{}]) T {
+		return entry.key
+	}}
+}
+
+// ForEach iterates over all values in insertion order (like set.forEach() in TypeScript).
+// Values added by fn are visited and values deleted by fn are skipped.
 func (s *Set[T]) ForEach(fn func(value T, index int, set *Set[T])) {
 	index := 0
-	for value := range s.data {
-		fn(value, index, s)
+	s.entries.each(func(entry *orderedEntry[T, struct{}]) {
+		fn(entry.key, index, s)
 		index++
-	}
+	})
 }
 
 // Filter creates a new set with values that pass the test
 func (s *Set[T]) Filter(predicate func(value T) bool) *Set[T] {
 	result := NewSet[T]()
-	for value := range s.data {
-		if predicate(value) {
-			result.Add(value)
+	for entry := s.entries.head; entry != nil; entry = entry.next {
+		if predicate(entry.key) {
+			result.Add(entry.key)
 		}
 	}
 	return result
@@ -241,8 +378,8 @@ func (s *Set[T]) Filter(predicate func(value T) bool) *Set[T] {
 // Map transforms values and returns a new set
 func SetMap[T, U comparable](s *Set[T], fn func(value T) U) *Set[U] {
 	result := NewSet[U]()
-	for value := range s.data {
-		result.Add(fn(value))
+	for entry := s.entries.head; entry != nil; entry = entry.next {
+		result.Add(fn(entry.key))
 	}
 	return result
 }
@@ -250,8 +387,8 @@ func SetMap[T, U comparable](s *Set[T], fn func(value T) U) *Set[U] {
 // Union returns a new set with all values from both sets (like set union)
 func (s *Set[T]) Union(other *Set[T]) *Set[T] {
 	result := s.Clone()
-	for value := range other.data {
-		result.Add(value)
+	for entry := other.entries.head; entry != nil; entry = entry.next {
+		result.Add(entry.key)
 	}
 	return result
 }
@@ -259,9 +396,9 @@ func (s *Set[T]) Union(other *Set[T]) *Set[T] {
 // Intersection returns a new set with values present in both sets
 func (s *Set[T]) Intersection(other *Set[T]) *Set[T] {
 	result := NewSet[T]()
-	for value := range s.data {
-		if other.Has(value) {
-			result.Add(value)
+	for entry := s.entries.head; entry != nil; entry = entry.next {
+		if other.Has(entry.key) {
+			result.Add(entry.key)
 		}
 	}
 	return result
@@ -270,9 +407,9 @@ func (s *Set[T]) Intersection(other *Set[T]) *Set[T] {
 // Difference returns a new set with values in this set but not in other
 func (s *Set[T]) Difference(other *Set[T]) *Set[T] {
 	result := NewSet[T]()
-	for value := range s.data {
-		if !other.Has(value) {
-			result.Add(value)
+	for entry := s.entries.head; entry != nil; entry = entry.next {
+		if !other.Has(entry.key) {
+			result.Add(entry.key)
 		}
 	}
 	return result
@@ -283,16 +420,16 @@ func (s *Set[T]) SymmetricDifference(other *Set[T]) *Set[T] {
 	result := NewSet[T]()
 	
 	// Add values from this set that are not in other
-	for value := range s.data {
-		if !other.Has(value) {
-			result.Add(value)
+	for entry := s.entries.head; entry != nil; entry = entry.next {
+		if !other.Has(entry.key) {
+			result.Add(entry.key)
 		}
 	}
 	
 	// Add values from other set that are not in this
-	for value := range other.data {
-		if !s.Has(value) {
-			result.Add(value)
+	for entry := other.entries.head; entry != nil; entry = entry.next {
+		if !s.Has(entry.key) {
+			result.Add(entry.key)
 		}
 	}
 	
@@ -301,11 +438,11 @@ func (s *Set[T]) SymmetricDifference(other *Set[T]) *Set[T] {
 
 // IsSubsetOf checks if this set is a subset of other
 func (s *Set[T]) IsSubsetOf(other *Set[T]) bool {
-	if s.size > other.size {
+	if s.Size() > other.Size() {
 		return false
 	}
-	for value := range s.data {
-		if !other.Has(value) {
+	for entry := s.entries.head; entry != nil; entry = entry.next {
+		if !other.Has(entry.key) {
 			return false
 		}
 	}
@@ -319,8 +456,8 @@ func (s *Set[T]) IsSupersetOf(other *Set[T]) bool {
 
 // IsDisjoint checks if this set has no common elements with other
 func (s *Set[T]) IsDisjoint(other *Set[T]) bool {
-	for value := range s.data {
-		if other.Has(value) {
+	for entry := s.entries.head; entry != nil; entry = entry.next {
+		if other.Has(entry.key) {
 			return false
 		}
 	}
@@ -330,8 +467,8 @@ func (s *Set[T]) IsDisjoint(other *Set[T]) bool {
 // Clone creates a shallow copy of the set
 func (s *Set[T]) Clone() *Set[T] {
 	result := NewSet[T]()
-	for value := range s.data {
-		result.Add(value)
+	for entry := s.entries.head; entry != nil; entry = entry.next {
+		result.Add(entry.key)
 	}
 	return result
 }
@@ -343,86 +480,5 @@ func (s *Set[T]) ToSlice() []T {
 
 // String returns string representation
 func (s *Set[T]) String() string {
-	return fmt.Sprintf("Set{size: %d}", s.size)
-}
-
-// WeakMap represents a weak reference map (simplified version)
-// Note: Go doesn't have true weak references, so this is a simplified implementation
-type WeakMap[K, V any] struct {
-	data map[uintptr]V
-}
-
-// NewWeakMap creates a new WeakMap
-func NewWeakMap[K, V any]() *WeakMap[K, V] {
-	return &WeakMap[K, V]{
-		data: make(map[uintptr]V),
-	}
-}
-
-// Set sets a value for an object key
-func (wm *WeakMap[K, V]) Set(key K, value V) {
-	ptr := reflect.ValueOf(key).Pointer()
-	wm.data[ptr] = value
+	return fmt.Sprintf("Set{size: %d}", s.entries.size())
 }
-
-// Get retrieves a value by object key
-func (wm *WeakMap[K, V]) Get(key K) Optional[V] {
-	ptr := reflect.ValueOf(key).Pointer()
-	if value, exists := wm.data[ptr]; exists {
-		return Some(value)
-	}
-	return None[V]()
-}
-
-// Has checks if an object key exists
-func (wm *WeakMap[K, V]) Has(key K) bool {
-	ptr := reflect.ValueOf(key).Pointer()
-	_, exists := wm.data[ptr]
-	return exists
-}
-
-// Delete removes an entry by object key
-func (wm *WeakMap[K, V]) Delete(key K) bool {
-	ptr := reflect.ValueOf(key).Pointer()
-	if _, exists := wm.data[ptr]; exists {
-		delete(wm.data, ptr)
-		return true
-	}
-	return false
-}
-
-// WeakSet represents a weak reference set (simplified version)
-type WeakSet[T any] struct {
-	data map[uintptr]struct{}
-}
-
-// NewWeakSet creates a new WeakSet
-func NewWeakSet[T any]() *WeakSet[T] {
-	return &WeakSet[T]{
-		data: make(map[uintptr]struct{}),
-	}
-}
-
-// Add adds an object to the set
-func (ws *WeakSet[T]) Add(value T) *WeakSet[T] {
-	ptr := reflect.ValueOf(value).Pointer()
-	ws.data[ptr] = struct{}{}
-	return ws
-}
-
-// Has checks if an object is in the set
-func (ws *WeakSet[T]) Has(value T) bool {
-	ptr := reflect.ValueOf(value).Pointer()
-	_, exists := ws.data[ptr]
-	return exists
-}
-
-// Delete removes an object from the set
-func (ws *WeakSet[T]) Delete(value T) bool {
-	ptr := reflect.ValueOf(value).Pointer()
-	if _, exists := ws.data[ptr]; exists {
-		delete(ws.data, ptr)
-		return true
-	}
-	return false
-}
\ No newline at end of file
@@ -0,0 +1,98 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+// drain returns the remaining keys of an iterator
+func drain[T any](it Iterator[T]) []T {
+	var values []T
+	for value, ok := it.Next(); ok; value, ok = it.Next() {
+		values = append(values, value)
+	}
+	return values
+}
+
+func TestMapIteratorSkipsDeletedEntries(t *testing.T) {
+	m := NewMap[string, int]()
+	m.Set("a", 1).Set("b", 2).Set("c", 3).Set("d", 4)
+
+	it := m.KeysIter()
+	first, _ := it.Next()
+	// Delete the current entry and the next one; the iterator resumes after them
+	m.Delete(first)
+	m.Delete("b")
+	if got := drain(it); !reflect.DeepEqual(got, []string{"c", "d"}) {
+		t.Fatalf("keys = %v, want [c d]", got)
+	}
+}
+
+func TestMapIteratorVisitsEntriesAddedAfterClear(t *testing.T) {
+	m := NewMap[string, int]()
+	m.Set("a", 1).Set("b", 2)
+
+	it := m.KeysIter()
+	it.Next()
+	m.Clear()
+	m.Set("x", 1).Set("y", 2)
+	if got := drain(it); !reflect.DeepEqual(got, []string{"x", "y"}) {
+		t.Fatalf("keys = %v, want [x y]", got)
+	}
+	// A finished iterator stays finished
+	m.Set("z", 3)
+	if _, ok := it.Next(); ok {
+		t.Fatal("finished iterator returned a value")
+	}
+}
+
+func TestMapSetKeepsPosition(t *testing.T) {
+	m := NewMap[string, int]()
+	m.Set("a", 1).Set("b", 2).Set("c", 3)
+
+	it := m.EntriesIter()
+	it.Next()
+	// Re-setting a visited key neither moves it nor visits it again
+	m.Set("a", 10)
+	m.Set("d", 4)
+	var keys []string
+	for _, entry := range drain(it) {
+		keys = append(keys, entry.First)
+	}
+	if !reflect.DeepEqual(keys, []string{"b", "c", "d"}) {
+		t.Fatalf("keys = %v, want [b c d]", keys)
+	}
+	if got := m.Keys(); !reflect.DeepEqual(got, []string{"a", "b", "c", "d"}) {
+		t.Fatalf("Keys() = %v, want [a b c d]", got)
+	}
+	if value := m.Get("a"); value.IsNone() || value.Get() != 10 {
+		t.Fatalf("a = %v, want 10", value)
+	}
+}
+
+func TestSetIteratorSkipsDeletedValues(t *testing.T) {
+	s := NewSet[int]()
+	s.Add(1).Add(2).Add(3)
+
+	it := s.ValuesIter()
+	it.Next()
+	s.Delete(2)
+	s.Add(1)
+	s.Add(4)
+	if got := drain(it); !reflect.DeepEqual(got, []int{3, 4}) {
+		t.Fatalf("values = %v, want [3 4]", got)
+	}
+}
+
+func TestCollectionString(t *testing.T) {
+	m := NewMap[string, int]()
+	m.Set("a", 1).Set("b", 2)
+	if got := m.String(); got != "Map{size: 2}" {
+		t.Fatalf("Map.String() = %q", got)
+	}
+	s := NewSet[int]()
+	s.Add(1)
+	if got := s.String(); got != "Set{size: 1}" {
+		t.Fatalf("Set.String() = %q", got)
+	}
+}
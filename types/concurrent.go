@@ -0,0 +1,578 @@
+package types
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/maphash"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultShardCount is the number of shards of a ConcurrentMap unless NewConcurrentMap sets one
+const DefaultShardCount = 32
+
+// concurrentEntry is a value with its insertion sequence number
+type concurrentEntry[V any] struct {
+	value V
+	seq   uint64
+}
+
+// concurrentShard is one lock-protected part of a ConcurrentMap
+type concurrentShard[K comparable, V any] struct {
+	mu   sync.RWMutex
+	data map[K]concurrentEntry[V]
+}
+
+// ConcurrentMap is a Map that is safe for concurrent use. Keys are spread over shards with
+// their own locks, so operations on different keys rarely contend. Snapshots such as Keys,
+// Entries and Range follow insertion order like Map.
+type ConcurrentMap[K comparable, V any] struct {
+	shards []*concurrentShard[K, V]
+	mask   uint64
+	seed   maphash.Seed
+	salt   uint64
+	seq    atomic.Uint64
+	size   atomic.Int64
+}
+
+// NewConcurrentMap creates a ConcurrentMap; the shard count is rounded up to a power of two
+func NewConcurrentMap[K comparable, V any](shards ...int) *ConcurrentMap[K, V] {
+	count := DefaultShardCount
+	if len(shards) > 0 && shards[0] > 0 {
+		count = shards[0]
+	}
+	size := 1
+	for size < count {
+		size <<= 1
+	}
+
+	m := &ConcurrentMap[K, V]{
+		shards: make([]*concurrentShard[K, V], size),
+		mask:   uint64(size - 1),
+		seed:   maphash.MakeSeed(),
+	}
+	m.salt = maphash.String(m.seed, "salt")
+	for i := range m.shards {
+		m.shards[i] = &concurrentShard[K, V]{data: make(map[K]concurrentEntry[V])}
+	}
+	return m
+}
+
+// NewConcurrentMapWithEntries creates a ConcurrentMap from initial entries
+func NewConcurrentMapWithEntries[K comparable, V any](entries []Tuple2[K, V]) *ConcurrentMap[K, V] {
+	m := NewConcurrentMap[K, V]()
+	for _, entry := range entries {
+		m.Set(entry.First, entry.Second)
+	}
+	return m
+}
+
+// shard returns the shard of a key
+func (m *ConcurrentMap[K, V]) shard(key K) *concurrentShard[K, V] {
+	return m.shards[hashComparable(m.seed, m.salt, key)&m.mask]
+}
+
+// Set adds or updates a key-value pair (like map.set() in TypeScript)
+func (m *ConcurrentMap[K, V]) Set(key K, value V) *ConcurrentMap[K, V] {
+	s := m.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m.store(s, key, value)
+	return m
+}
+
+// store sets a key in a locked shard, keeping the sequence number of an existing key
+func (m *ConcurrentMap[K, V]) store(s *concurrentShard[K, V], key K, value V) {
+	entry, exists := s.data[key]
+	if !exists {
+		entry.seq = m.seq.Add(1)
+		m.size.Add(1)
+	}
+	entry.value = value
+	s.data[key] = entry
+}
+
+// remove deletes a key from a locked shard
+func (m *ConcurrentMap[K, V]) remove(s *concurrentShard[K, V], key K) (V, bool) {
+	entry, exists := s.data[key]
+	if exists {
+		delete(s.data, key)
+		m.size.Add(-1)
+	}
+	return entry.value, exists
+}
+
+// Get retrieves a value by key (like map.get() in TypeScript)
+func (m *ConcurrentMap[K, V]) Get(key K) Optional[V] {
+	s := m.shard(key)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if entry, exists := s.data[key]; exists {
+		return Some(entry.value)
+	}
+	return None[V]()
+}
+
+// Has checks if a key exists (like map.has() in TypeScript)
+func (m *ConcurrentMap[K, V]) Has(key K) bool {
+	s := m.shard(key)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, exists := s.data[key]
+	return exists
+}
+
+// Delete removes a key-value pair (like map.delete() in TypeScript)
+func (m *ConcurrentMap[K, V]) Delete(key K) bool {
+	return m.GetAndDelete(key).IsSome()
+}
+
+// GetAndDelete atomically removes a key and returns its value (like sync.Map.LoadAndDelete)
+func (m *ConcurrentMap[K, V]) GetAndDelete(key K) Optional[V] {
+	s := m.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if value, existed := m.remove(s, key); existed {
+		return Some(value)
+	}
+	return None[V]()
+}
+
+// GetOrSet atomically returns the value of a key, or sets it to value if the key is missing.
+// loaded reports whether the value was already present (like sync.Map.LoadOrStore).
+func (m *ConcurrentMap[K, V]) GetOrSet(key K, value V) (actual V, loaded bool) {
+	s := m.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry, exists := s.data[key]; exists {
+		return entry.value, true
+	}
+	m.store(s, key, value)
+	return value, false
+}
+
+// Compute atomically replaces the value of a key with the result of fn, which receives the
+// current value and whether it exists. Returning keep=false deletes the key. It returns the new
+// value, or None if the key was deleted. fn runs under the shard lock and must not use the map.
+func (m *ConcurrentMap[K, V]) Compute(key K, fn func(value V, exists bool) (newValue V, keep bool)) Optional[V] {
+	s := m.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, exists := s.data[key]
+	value, keep := fn(entry.value, exists)
+	if !keep {
+		m.remove(s, key)
+		return None[V]()
+	}
+	m.store(s, key, value)
+	return Some(value)
+}
+
+// Update atomically replaces the value of an existing key with the result of fn. It reports
+// whether the key existed. fn runs under the shard lock and must not use the map.
+func (m *ConcurrentMap[K, V]) Update(key K, fn func(value V) V) bool {
+	s := m.shard(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, exists := s.data[key]
+	if exists {
+		entry.value = fn(entry.value)
+		s.data[key] = entry
+	}
+	return exists
+}
+
+// Clear removes all entries (like map.clear() in TypeScript). Entries set concurrently may
+// survive in shards that were already cleared.
+func (m *ConcurrentMap[K, V]) Clear() {
+	for _, s := range m.shards {
+		s.mu.Lock()
+		m.size.Add(-int64(len(s.data)))
+		s.data = make(map[K]concurrentEntry[V])
+		s.mu.Unlock()
+	}
+}
+
+// Size returns the number of entries (like map.size in TypeScript)
+func (m *ConcurrentMap[K, V]) Size() int {
+	return int(m.size.Load())
+}
+
+// IsEmpty checks if the map is empty
+func (m *ConcurrentMap[K, V]) IsEmpty() bool {
+	return m.Size() == 0
+}
+
+// snapshot copies the entries shard by shard and sorts them in insertion order. Each shard is
+// copied atomically, but the snapshot may mix states of the map from different moments.
+func (m *ConcurrentMap[K, V]) snapshot() []Tuple3[K, V, uint64] {
+	entries := make([]Tuple3[K, V, uint64], 0, m.Size())
+	for _, s := range m.shards {
+		s.mu.RLock()
+		for key, entry := range s.data {
+			entries = append(entries, NewTuple3(key, entry.value, entry.seq))
+		}
+		s.mu.RUnlock()
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Third < entries[j].Third
+	})
+	return entries
+}
+
+// Keys returns a snapshot of the keys in insertion order (like map.keys() in TypeScript)
+func (m *ConcurrentMap[K, V]) Keys() []K {
+	snapshot := m.snapshot()
+	keys := make([]K, len(snapshot))
+	for i, entry := range snapshot {
+		keys[i] = entry.First
+	}
+	return keys
+}
+
+// Values returns a snapshot of the values in insertion order (like map.values() in TypeScript)
+func (m *ConcurrentMap[K, V]) Values() []V {
+	snapshot := m.snapshot()
+	values := make([]V, len(snapshot))
+	for i, entry := range snapshot {
+		values[i] = entry.Second
+	}
+	return values
+}
+
+// Entries returns a snapshot of the key-value pairs in insertion order (like map.entries() in
+// TypeScript)
+func (m *ConcurrentMap[K, V]) Entries() []Tuple2[K, V] {
+	snapshot := m.snapshot()
+	entries := make([]Tuple2[K, V], len(snapshot))
+	for i, entry := range snapshot {
+		entries[i] = NewTuple2(entry.First, entry.Second)
+	}
+	return entries
+}
+
+// Range calls fn with each entry of a snapshot until fn returns false (like sync.Map.Range).
+// fn may use the map.
+func (m *ConcurrentMap[K, V]) Range(fn func(key K, value V) bool) {
+	for _, entry := range m.snapshot() {
+		if !fn(entry.First, entry.Second) {
+			return
+		}
+	}
+}
+
+// ForEach calls fn with each entry of a snapshot (like map.forEach() in TypeScript)
+func (m *ConcurrentMap[K, V]) ForEach(fn func(value V, key K, map_ *ConcurrentMap[K, V])) {
+	for _, entry := range m.snapshot() {
+		fn(entry.Second, entry.First, m)
+	}
+}
+
+// Filter creates a new map with the entries of a snapshot that pass the test
+func (m *ConcurrentMap[K, V]) Filter(predicate func(value V, key K) bool) *ConcurrentMap[K, V] {
+	result := NewConcurrentMap[K, V](len(m.shards))
+	for _, entry := range m.snapshot() {
+		if predicate(entry.Second, entry.First) {
+			result.Set(entry.First, entry.Second)
+		}
+	}
+	return result
+}
+
+// ConcurrentMapTransform transforms the values of a snapshot and returns a new map
+func ConcurrentMapTransform[K comparable, V, U any](m *ConcurrentMap[K, V], fn func(value V, key K) U) *ConcurrentMap[K, U] {
+	result := NewConcurrentMap[K, U](len(m.shards))
+	for _, entry := range m.snapshot() {
+		result.Set(entry.First, fn(entry.Second, entry.First))
+	}
+	return result
+}
+
+// Clone creates a shallow copy of a snapshot
+func (m *ConcurrentMap[K, V]) Clone() *ConcurrentMap[K, V] {
+	return m.Filter(func(V, K) bool { return true })
+}
+
+// ToMap copies a snapshot into an insertion-ordered Map
+func (m *ConcurrentMap[K, V]) ToMap() *Map[K, V] {
+	result := NewMap[K, V]()
+	for _, entry := range m.snapshot() {
+		result.Set(entry.First, entry.Second)
+	}
+	return result
+}
+
+// String returns the entries of a snapshot (like util.inspect(map) in Node.js)
+func (m *ConcurrentMap[K, V]) String() string {
+	snapshot := m.snapshot()
+	parts := make([]string, len(snapshot))
+	for i, entry := range snapshot {
+		parts[i] = fmt.Sprintf("%v => %v", entry.First, entry.Second)
+	}
+	return fmt.Sprintf("ConcurrentMap(%d) {%s}", len(snapshot), strings.Join(parts, ", "))
+}
+
+// ConcurrentSet is a Set that is safe for concurrent use, backed by a ConcurrentMap
+type ConcurrentSet[T comparable] struct {
+	m *ConcurrentMap[T, struct{}]
+}
+
+// NewConcurrentSet creates a ConcurrentSet; the shard count is rounded up to a power of two
+func NewConcurrentSet[T comparable](shards ...int) *ConcurrentSet[T] {
+	return &ConcurrentSet[T]{m: NewConcurrentMap[T, struct{}](shards...)}
+}
+
+// NewConcurrentSetWithValues creates a ConcurrentSet from initial values
+func NewConcurrentSetWithValues[T comparable](values []T) *ConcurrentSet[T] {
+	s := NewConcurrentSet[T]()
+	for _, value := range values {
+		s.Add(value)
+	}
+	return s
+}
+
+// Add adds a value to the set (like set.add() in TypeScript)
+func (s *ConcurrentSet[T]) Add(value T) *ConcurrentSet[T] {
+	s.m.GetOrSet(value, struct{}{})
+	return s
+}
+
+// AddIfAbsent atomically adds a value and reports whether it was missing
+func (s *ConcurrentSet[T]) AddIfAbsent(value T) bool {
+	_, loaded := s.m.GetOrSet(value, struct{}{})
+	return !loaded
+}
+
+// Has checks if a value exists in the set (like set.has() in TypeScript)
+func (s *ConcurrentSet[T]) Has(value T) bool {
+	return s.m.Has(value)
+}
+
+// Delete removes a value from the set (like set.delete() in TypeScript)
+func (s *ConcurrentSet[T]) Delete(value T) bool {
+	return s.m.Delete(value)
+}
+
+// Clear removes all values (like set.clear() in TypeScript)
+func (s *ConcurrentSet[T]) Clear() {
+	s.m.Clear()
+}
+
+// Size returns the number of values (like set.size in TypeScript)
+func (s *ConcurrentSet[T]) Size() int {
+	return s.m.Size()
+}
+
+// IsEmpty checks if the set is empty
+func (s *ConcurrentSet[T]) IsEmpty() bool {
+	return s.m.IsEmpty()
+}
+
+// Values returns a snapshot of the values in insertion order (like set.values() in TypeScript)
+func (s *ConcurrentSet[T]) Values() []T {
+	return s.m.Keys()
+}
+
+// Range calls fn with each value of a snapshot until fn returns false
+func (s *ConcurrentSet[T]) Range(fn func(value T) bool) {
+	s.m.Range(func(value T, _ struct{}) bool {
+		return fn(value)
+	})
+}
+
+// ForEach calls fn with each value of a snapshot (like set.forEach() in TypeScript)
+func (s *ConcurrentSet[T]) ForEach(fn func(value T, index int, set *ConcurrentSet[T])) {
+	for index, value := range s.Values() {
+		fn(value, index, s)
+	}
+}
+
+// Filter creates a new set with the values of a snapshot that pass the test
+func (s *ConcurrentSet[T]) Filter(predicate func(value T) bool) *ConcurrentSet[T] {
+	result := NewConcurrentSet[T](len(s.m.shards))
+	for _, value := range s.Values() {
+		if predicate(value) {
+			result.Add(value)
+		}
+	}
+	return result
+}
+
+// Union returns a new set with all values from both sets (like set union)
+func (s *ConcurrentSet[T]) Union(other *ConcurrentSet[T]) *ConcurrentSet[T] {
+	result := s.Clone()
+	for _, value := range other.Values() {
+		result.Add(value)
+	}
+	return result
+}
+
+// Intersection returns a new set with values present in both sets
+func (s *ConcurrentSet[T]) Intersection(other *ConcurrentSet[T]) *ConcurrentSet[T] {
+	return s.Filter(other.Has)
+}
+
+// Difference returns a new set with values in this set but not in other
+func (s *ConcurrentSet[T]) Difference(other *ConcurrentSet[T]) *ConcurrentSet[T] {
+	return s.Filter(func(value T) bool { return !other.Has(value) })
+}
+
+// SymmetricDifference returns values in either set but not in both
+func (s *ConcurrentSet[T]) SymmetricDifference(other *ConcurrentSet[T]) *ConcurrentSet[T] {
+	result := s.Difference(other)
+	for _, value := range other.Values() {
+		if !s.Has(value) {
+			result.Add(value)
+		}
+	}
+	return result
+}
+
+// IsSubsetOf checks if this set is a subset of other
+func (s *ConcurrentSet[T]) IsSubsetOf(other *ConcurrentSet[T]) bool {
+	for _, value := range s.Values() {
+		if !other.Has(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSupersetOf checks if this set is a superset of other
+func (s *ConcurrentSet[T]) IsSupersetOf(other *ConcurrentSet[T]) bool {
+	return other.IsSubsetOf(s)
+}
+
+// IsDisjoint checks if this set has no common elements with other
+func (s *ConcurrentSet[T]) IsDisjoint(other *ConcurrentSet[T]) bool {
+	for _, value := range s.Values() {
+		if other.Has(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// Clone creates a shallow copy of a snapshot
+func (s *ConcurrentSet[T]) Clone() *ConcurrentSet[T] {
+	return &ConcurrentSet[T]{m: s.m.Clone()}
+}
+
+// ToSet copies a snapshot into an insertion-ordered Set
+func (s *ConcurrentSet[T]) ToSet() *Set[T] {
+	return NewSetWithValues(s.Values())
+}
+
+// ToSlice converts a snapshot of the set to a slice
+func (s *ConcurrentSet[T]) ToSlice() []T {
+	return s.Values()
+}
+
+// String returns the values of a snapshot (like util.inspect(set) in Node.js)
+func (s *ConcurrentSet[T]) String() string {
+	values := s.Values()
+	parts := make([]string, len(values))
+	for i, value := range values {
+		parts[i] = fmt.Sprint(value)
+	}
+	return fmt.Sprintf("ConcurrentSet(%d) {%s}", len(values), strings.Join(parts, ", "))
+}
+
+// hashComparable hashes a comparable key so that equal keys get equal hashes. Strings and
+// integers take a fast path; other keys are hashed field by field with reflection.
+func hashComparable[K comparable](seed maphash.Seed, salt uint64, key K) uint64 {
+	switch k := any(key).(type) {
+	case string:
+		return maphash.String(seed, k)
+	case int:
+		return mixHash(uint64(k) ^ salt)
+	case int64:
+		return mixHash(uint64(k) ^ salt)
+	case int32:
+		return mixHash(uint64(k) ^ salt)
+	case uint:
+		return mixHash(uint64(k) ^ salt)
+	case uint64:
+		return mixHash(k ^ salt)
+	case uint32:
+		return mixHash(uint64(k) ^ salt)
+	case uintptr:
+		return mixHash(uint64(k) ^ salt)
+	}
+
+	var h maphash.Hash
+	h.SetSeed(seed)
+	hashValue(&h, reflect.ValueOf(key))
+	return h.Sum64()
+}
+
+// mixHash scrambles the bits of an integer key (the splitmix64 finalizer)
+func mixHash(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// hashValue writes a comparable value to a hash
+func hashValue(h *maphash.Hash, v reflect.Value) {
+	var buf [8]byte
+	writeUint := func(x uint64) {
+		binary.LittleEndian.PutUint64(buf[:], x)
+		h.Write(buf[:])
+	}
+	writeFloat := func(f float64) {
+		// +0 and -0 are equal keys
+		if f == 0 {
+			f = 0
+		}
+		writeUint(math.Float64bits(f))
+	}
+
+	switch v.Kind() {
+	case reflect.Invalid:
+		h.WriteByte(0)
+	case reflect.Bool:
+		if v.Bool() {
+			h.WriteByte(1)
+		} else {
+			h.WriteByte(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeUint(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeUint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		writeFloat(v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		writeFloat(real(v.Complex()))
+		writeFloat(imag(v.Complex()))
+	case reflect.String:
+		h.WriteString(v.String())
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		writeUint(uint64(v.Pointer()))
+	case reflect.Interface:
+		hashValue(h, v.Elem())
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			hashValue(h, v.Index(i))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			hashValue(h, v.Field(i))
+		}
+	}
+}
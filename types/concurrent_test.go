@@ -0,0 +1,147 @@
+package types
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentMapParallelOperations(t *testing.T) {
+	const workers, keys = 16, 200
+	m := NewConcurrentMap[string, int]()
+	increment := func(value int, exists bool) (int, bool) { return value + 1, true }
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	var readers sync.WaitGroup
+	for r := 0; r < 4; r++ {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				m.Range(func(key string, value int) bool {
+					if value < 0 {
+						t.Errorf("negative value %d for %s", value, key)
+					}
+					return true
+				})
+				m.Size()
+			}
+		}()
+	}
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < keys; i++ {
+				key := fmt.Sprintf("%d-%d", w, i)
+				m.Set(key, 0)
+				m.Compute(key, increment)
+				m.Update(key, func(value int) int { return value + 1 })
+				if value, loaded := m.GetOrSet(key, 99); !loaded || value != 2 {
+					t.Errorf("GetOrSet(%s) = %d, %v", key, value, loaded)
+				}
+				if value := m.Get(key); value.IsNone() || value.Get() != 2 {
+					t.Errorf("Get(%s) = %v", key, value)
+				}
+				if i%2 == 1 && !m.Delete(key) {
+					t.Errorf("Delete(%s) found no entry", key)
+				}
+				m.Compute("shared", increment)
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(stop)
+	readers.Wait()
+
+	if size := m.Size(); size != workers*keys/2+1 {
+		t.Fatalf("Size() = %d, want %d", size, workers*keys/2+1)
+	}
+	if shared := m.Get("shared"); shared.IsNone() || shared.Get() != workers*keys {
+		t.Fatalf("shared = %v, want %d", shared, workers*keys)
+	}
+	for w := 0; w < workers; w++ {
+		for i := 0; i < keys; i++ {
+			key := fmt.Sprintf("%d-%d", w, i)
+			if value := m.Get(key); (i%2 == 0) != value.IsSome() || (value.IsSome() && value.Get() != 2) {
+				t.Fatalf("%s = %v after the run", key, value)
+			}
+		}
+	}
+}
+
+func TestConcurrentSetAddIfAbsentOnce(t *testing.T) {
+	const workers, values = 8, 500
+	s := NewConcurrentSet[int]()
+	added := make([]int, workers)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for v := 0; v < values; v++ {
+				if s.AddIfAbsent(v) {
+					added[w]++
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	total := 0
+	for _, n := range added {
+		total += n
+	}
+	if total != values || s.Size() != values {
+		t.Fatalf("added %d values, size %d, want %d", total, s.Size(), values)
+	}
+}
+
+// BenchmarkConcurrentMap compares ConcurrentMap with sync.Map on a read-heavy parallel load
+func BenchmarkConcurrentMap(b *testing.B) {
+	const keys = 1024
+
+	b.Run("ConcurrentMap", func(b *testing.B) {
+		m := NewConcurrentMap[int, int]()
+		for i := 0; i < keys; i++ {
+			m.Set(i, i)
+		}
+		b.RunParallel(func(pb *testing.PB) {
+			i := 0
+			for pb.Next() {
+				if i%10 == 0 {
+					m.Set(i%keys, i)
+				} else {
+					m.Get(i % keys)
+				}
+				i++
+			}
+		})
+	})
+
+	b.Run("sync.Map", func(b *testing.B) {
+		var m sync.Map
+		for i := 0; i < keys; i++ {
+			m.Store(i, i)
+		}
+		b.RunParallel(func(pb *testing.PB) {
+			i := 0
+			for pb.Next() {
+				if i%10 == 0 {
+					m.Store(i%keys, i)
+				} else {
+					m.Load(i % keys)
+				}
+				i++
+			}
+		})
+	})
+}
@@ -79,6 +79,12 @@ func NewErrorWithCause(message string, cause error, code ...ErrorCode) *Enhanced
 	return err
 }
 
+// CaptureStackTrace captures the stack of the calling function, skipping skip additional
+// frames (like Error.captureStackTrace() in TypeScript)
+func CaptureStackTrace(skip int) StackTrace {
+	return captureStackTrace(skip + 2) // Skip CaptureStackTrace and captureStackTrace
+}
+
 // captureStackTrace captures the current stack trace
 func captureStackTrace(skip int) StackTrace {
 	var frames StackTrace
@@ -415,6 +421,9 @@ func (ErrorFormatter) FormatShort(err error) string {
 
 // FormatDetailed returns a detailed error format
 func (ErrorFormatter) FormatDetailed(err error) string {
+	if e, ok := err.(*AggregateError); ok {
+		return e.String()
+	}
 	if e, ok := err.(*EnhancedError); ok {
 		return e.String()
 	}
@@ -423,6 +432,9 @@ func (ErrorFormatter) FormatDetailed(err error) string {
 
 // FormatJSON returns JSON formatted error
 func (ErrorFormatter) FormatJSON(err error) map[string]interface{} {
+	if e, ok := err.(*AggregateError); ok {
+		return e.ToJSON()
+	}
 	if e, ok := err.(*EnhancedError); ok {
 		return e.ToJSON()
 	}
@@ -447,6 +459,9 @@ func IsErrorCode(err error, code ErrorCode) bool {
 
 // GetErrorCode extracts error code from any error
 func GetErrorCode(err error) ErrorCode {
+	if e, ok := err.(*AggregateError); ok {
+		return e.Code()
+	}
 	if e, ok := err.(*EnhancedError); ok {
 		return e.Code()
 	}
@@ -481,4 +496,115 @@ func NewInternalError(message string) *EnhancedError {
 
 func NewTimeoutError(message string) *EnhancedError {
 	return NewError(message, TimeoutError)
-}
\ No newline at end of file
+}
+// IndexedError is an error tagged with the position of the operation that produced it
+type IndexedError struct {
+	Index int   `json:"index"`
+	Err   error `json:"error"`
+}
+
+// Error implements the error interface
+func (e IndexedError) Error() string {
+	return fmt.Sprintf("[%d] %v", e.Index, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e IndexedError) Unwrap() error {
+	return e.Err
+}
+
+// AggregateError groups several errors into one (like AggregateError in TypeScript).
+// It embeds EnhancedError for code, data, stack and timestamp, and unwraps to every
+// collected error so errors.Is and errors.As match any of them.
+type AggregateError struct {
+	*EnhancedError
+	errors []IndexedError
+}
+
+// NewAggregateError creates an AggregateError from errors indexed by position; nil entries are skipped
+func NewAggregateError(message string, errs []error, code ...ErrorCode) *AggregateError {
+	errorCode := UnknownError
+	if len(code) > 0 {
+		errorCode = code[0]
+	}
+	
+	var indexed []IndexedError
+	for i, err := range errs {
+		if err != nil {
+			indexed = append(indexed, IndexedError{Index: i, Err: err})
+		}
+	}
+	
+	return &AggregateError{
+		EnhancedError: &EnhancedError{
+			message:   message,
+			code:      errorCode,
+			data:      make(map[string]interface{}),
+			stack:     captureStackTrace(2), // Skip NewAggregateError and caller
+			timestamp: time.Now(),
+		},
+		errors: indexed,
+	}
+}
+
+// Error implements the error interface, listing every collected error
+func (e *AggregateError) Error() string {
+	messages := make([]string, len(e.errors))
+	for i, err := range e.errors {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("%s (%d errors): %s", e.message, len(e.errors), strings.Join(messages, "; "))
+}
+
+// Errors returns the collected errors with their indexes (like aggregateError.errors in TypeScript)
+func (e *AggregateError) Errors() []IndexedError {
+	return e.errors
+}
+
+// Unwrap returns the collected errors (Go 1.20 multi-error unwrapping)
+func (e *AggregateError) Unwrap() []error {
+	errs := make([]error, len(e.errors))
+	for i, err := range e.errors {
+		errs[i] = err.Err
+	}
+	return errs
+}
+
+// WithData adds data to the error (fluent interface)
+func (e *AggregateError) WithData(key string, value interface{}) *AggregateError {
+	e.EnhancedError.WithData(key, value)
+	return e
+}
+
+// WithCode sets the error code (fluent interface)
+func (e *AggregateError) WithCode(code ErrorCode) *AggregateError {
+	e.EnhancedError.WithCode(code)
+	return e
+}
+
+// String returns a detailed string representation including every collected error
+func (e *AggregateError) String() string {
+	parts := []string{e.EnhancedError.String(), "Errors:"}
+	for _, err := range e.errors {
+		parts = append(parts, "  "+err.Error())
+	}
+	return strings.Join(parts, "\n")
+}
+
+// ToJSON returns JSON representation of the error and every collected error
+func (e *AggregateError) ToJSON() map[string]interface{} {
+	result := e.EnhancedError.ToJSON()
+	
+	errs := make([]map[string]interface{}, len(e.errors))
+	for i, err := range e.errors {
+		entry := map[string]interface{}{
+			"index":   err.Index,
+			"message": err.Err.Error(),
+			"code":    string(GetErrorCode(err.Err)),
+		}
+		errs[i] = entry
+	}
+	result["errors"] = errs
+	
+	return result
+}
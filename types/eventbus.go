@@ -0,0 +1,738 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"typescript-golang/timers"
+)
+
+// DeadLetterTopic receives a *DeadLetter for every message whose handler kept failing or whose
+// subscriber queue was full, unless SubscribeOptions names another topic
+const DeadLetterTopic = "bus:dead-letter"
+
+// DefaultBusQueueSize is the queue size of a bus subscriber unless SubscribeOptions sets one
+const DefaultBusQueueSize = 64
+
+var (
+	// ErrBusClosed is returned when publishing to a closed EventBus
+	ErrBusClosed = errors.New("event bus is closed")
+	// ErrQueueFull is the dead-letter reason of a message dropped by a full subscriber queue
+	ErrQueueFull = errors.New("subscriber queue is full")
+)
+
+// BusMessage is a message published on an EventBus. Middleware may change its topic, payload
+// and headers; handlers receive their own copy and must treat Headers as read-only.
+type BusMessage struct {
+	ID        string
+	Topic     string
+	Payload   interface{}
+	Headers   map[string]string
+	Timestamp time.Time
+	// Attempt is 1 for the first delivery to a subscriber and grows with every retry
+	Attempt int
+	reply   *busReply
+}
+
+// IsRequest reports whether the publisher waits for a reply
+func (m *BusMessage) IsRequest() bool {
+	return m.reply != nil
+}
+
+// Reply answers a request; only the first reply of all subscribers is used
+func (m *BusMessage) Reply(value interface{}) {
+	if m.reply != nil {
+		m.reply.settle(value, nil)
+	}
+}
+
+// DeadLetter is the payload of a dead-lettered message
+type DeadLetter struct {
+	Message *BusMessage
+	// Subscriber is the name of the failing subscriber
+	Subscriber string
+	Err        error
+}
+
+// BusHandler handles a message; returning an error (or panicking) triggers a retry
+type BusHandler func(msg *BusMessage) error
+
+// BusMiddleware runs on every publish before delivery (like Express middleware). It may
+// validate, enrich or log the message; returning an error rejects the publish.
+type BusMiddleware func(msg *BusMessage, next func(msg *BusMessage) error) error
+
+// SubscribeOptions configures the delivery of a bus subscriber
+type SubscribeOptions struct {
+	// Name identifies the subscriber in dead letters
+	Name string
+	// QueueSize bounds the messages waiting for the subscriber (default DefaultBusQueueSize)
+	QueueSize int
+	// Workers is the number of goroutines handling messages (default 1, which keeps order)
+	Workers int
+	// MaxRetries is how often a failing message is retried before it is dead-lettered
+	MaxRetries int
+	// RetryDelay is the wait before the first retry; it doubles after each retry
+	RetryDelay time.Duration
+	// DropWhenFull dead-letters messages when the queue is full instead of blocking Publish
+	DropWhenFull bool
+	// DeadLetterTopic receives failed messages (default DeadLetterTopic)
+	DeadLetterTopic string
+}
+
+// TopicMetrics counts the messages of a topic
+type TopicMetrics struct {
+	Published    uint64
+	Delivered    uint64
+	Failed       uint64
+	Retried      uint64
+	DeadLettered uint64
+	Dropped      uint64
+	Subscribers  int
+}
+
+// topicCounters holds the live counters behind TopicMetrics
+type topicCounters struct {
+	published, delivered, failed, retried, deadLettered, dropped atomic.Uint64
+}
+
+// EventBus delivers messages between decoupled parts of an application. Every subscriber has
+// its own bounded queue and workers, so a slow handler does not hold up the others; failing
+// handlers are retried and then dead-lettered. Topics may be registered with a payload type,
+// and subscribers may use wildcard patterns like "user:*".
+type EventBus struct {
+	mu           sync.RWMutex
+	subscribers  map[string][]*busSubscriber
+	patterns     []*busSubscriber
+	payloadTypes map[string]reflect.Type
+	middleware   []BusMiddleware
+	metrics      map[string]*topicCounters
+	clock        timers.Clock
+	closed       bool
+	inflight     sync.WaitGroup
+	seq          atomic.Uint64
+}
+
+// NewEventBus creates a new EventBus. An optional clock replaces the default one for
+// timestamps and retry delays, e.g. a timers.FakeClock in tests.
+func NewEventBus(clock ...timers.Clock) *EventBus {
+	return &EventBus{
+		subscribers:  make(map[string][]*busSubscriber),
+		payloadTypes: make(map[string]reflect.Type),
+		metrics:      make(map[string]*topicCounters),
+		clock:        timers.Resolve(clock...),
+	}
+}
+
+// Use adds publish middleware, run in the order added
+func (eb *EventBus) Use(middleware ...BusMiddleware) *EventBus {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+
+	eb.middleware = append(eb.middleware, middleware...)
+	return eb
+}
+
+// Publish publishes a message to the subscribers of a topic. It returns the error of the
+// middleware, a ValidationError for a payload that does not match a registered topic, or
+// ErrBusClosed.
+func (eb *EventBus) Publish(topic string, payload interface{}) error {
+	return eb.publish(topic, payload, nil)
+}
+
+// RequestWith publishes a request and calls reply once: with the first value passed to
+// BusMessage.Reply, or with an error when every subscriber has finished without replying.
+// async.Request wraps it in a Promise.
+func (eb *EventBus) RequestWith(topic string, payload interface{}, reply func(value interface{}, err error)) {
+	r := &busReply{fn: reply}
+	if err := eb.publish(topic, payload, r); err != nil {
+		r.settle(nil, err)
+	} else if !r.dispatched() {
+		// A middleware dropped the request without an error
+		r.settle(nil, NewError(fmt.Sprintf("request to '%s' was not delivered", topic), NotFoundError).
+			WithData("topic", topic))
+	}
+}
+
+// PublishMessage publishes a prepared message, keeping its ID, headers and timestamp when set,
+// e.g. to republish a message received from another process
+func (eb *EventBus) PublishMessage(msg *BusMessage) error {
+	delivery := *msg
+	delivery.reply = nil
+	delivery.Attempt = 0
+	delivery.Headers = make(map[string]string, len(msg.Headers))
+	for key, value := range msg.Headers {
+		delivery.Headers[key] = value
+	}
+	return eb.run(&delivery)
+}
+
+// publish publishes a new message
+func (eb *EventBus) publish(topic string, payload interface{}, reply *busReply) error {
+	return eb.run(&BusMessage{Topic: topic, Payload: payload, reply: reply})
+}
+
+// run fills in the message metadata, runs the middleware and dispatches the message
+func (eb *EventBus) run(msg *BusMessage) error {
+	eb.mu.RLock()
+	if eb.closed {
+		eb.mu.RUnlock()
+		return ErrBusClosed
+	}
+	eb.inflight.Add(1)
+	middleware := eb.middleware
+	eb.mu.RUnlock()
+	defer eb.inflight.Done()
+
+	if msg.ID == "" {
+		msg.ID = strconv.FormatUint(eb.seq.Add(1), 10)
+	}
+	if msg.Headers == nil {
+		msg.Headers = make(map[string]string)
+	}
+	if msg.Timestamp.IsZero() {
+		msg.Timestamp = eb.clock.Now()
+	}
+
+	var next func(index int, msg *BusMessage) error
+	next = func(index int, msg *BusMessage) error {
+		if index == len(middleware) {
+			return eb.dispatch(msg)
+		}
+		return middleware[index](msg, func(msg *BusMessage) error {
+			return next(index+1, msg)
+		})
+	}
+	return next(0, msg)
+}
+
+// dispatch queues a message for every matching subscriber
+func (eb *EventBus) dispatch(msg *BusMessage) error {
+	if err := eb.checkPayload(msg.Topic, msg.Payload); err != nil {
+		return err
+	}
+
+	eb.mu.RLock()
+	targets := append([]*busSubscriber(nil), eb.subscribers[msg.Topic]...)
+	for _, s := range eb.patterns {
+		if MatchEvent(s.topic, msg.Topic) {
+			targets = append(targets, s)
+		}
+	}
+	eb.mu.RUnlock()
+
+	eb.counters(msg.Topic).published.Add(1)
+	if msg.reply != nil {
+		if len(targets) == 0 {
+			msg.reply.settle(nil, NewError(fmt.Sprintf("no subscriber for topic '%s'", msg.Topic), NotFoundError).
+				WithData("topic", msg.Topic))
+			return nil
+		}
+		msg.reply.expect(len(targets))
+	}
+	for _, s := range targets {
+		s.enqueue(msg)
+	}
+	return nil
+}
+
+// counters returns the counters of a topic, creating them on first use
+func (eb *EventBus) counters(topic string) *topicCounters {
+	eb.mu.RLock()
+	counters, exists := eb.metrics[topic]
+	eb.mu.RUnlock()
+	if exists {
+		return counters
+	}
+
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+
+	if counters, exists = eb.metrics[topic]; !exists {
+		counters = &topicCounters{}
+		eb.metrics[topic] = counters
+	}
+	return counters
+}
+
+// checkPayload validates a payload against the topic's registered type
+func (eb *EventBus) checkPayload(topic string, payload interface{}) error {
+	eb.mu.RLock()
+	expected, registered := eb.payloadTypes[topic]
+	eb.mu.RUnlock()
+
+	if !registered {
+		return nil
+	}
+	return checkPayloadType(topic, expected, payload)
+}
+
+// PayloadType returns the payload type registered for a topic, if any
+func (eb *EventBus) PayloadType(topic string) Optional[reflect.Type] {
+	eb.mu.RLock()
+	defer eb.mu.RUnlock()
+
+	if payload, exists := eb.payloadTypes[topic]; exists {
+		return Some(payload)
+	}
+	return None[reflect.Type]()
+}
+
+// DecodePayload decodes a JSON payload into the topic's registered type, or into plain JSON
+// values (maps, slices, float64, ...) for unregistered topics, as JSON.parse() would
+func (eb *EventBus) DecodePayload(topic string, data []byte) (interface{}, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	payloadType := eb.PayloadType(topic)
+	if payloadType.IsNone() {
+		var value interface{}
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	}
+	value := reflect.New(payloadType.Get())
+	if err := json.Unmarshal(data, value.Interface()); err != nil {
+		return nil, err
+	}
+	return value.Elem().Interface(), nil
+}
+
+// Subscribe subscribes a listener to the payloads of a topic or pattern
+func (eb *EventBus) Subscribe(topic string, listener EventListener[interface{}], options ...SubscribeOptions) *Subscription {
+	return eb.Handle(topic, func(msg *BusMessage) error {
+		listener(msg.Payload)
+		return nil
+	}, options...)
+}
+
+// Handle subscribes a handler to the messages of a topic or pattern
+func (eb *EventBus) Handle(topic string, handler BusHandler, options ...SubscribeOptions) *Subscription {
+	var opts SubscribeOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = DefaultBusQueueSize
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+	if opts.DeadLetterTopic == "" {
+		opts.DeadLetterTopic = DeadLetterTopic
+	}
+	if opts.Name == "" {
+		opts.Name = topic
+	}
+
+	s := &busSubscriber{
+		bus:      eb,
+		topic:    topic,
+		handler:  handler,
+		options:  opts,
+		queue:    make(chan *BusMessage, opts.QueueSize),
+		stop:     make(chan struct{}),
+		draining: make(chan struct{}),
+	}
+
+	eb.mu.Lock()
+	if isEventPattern(topic) {
+		eb.patterns = append(eb.patterns, s)
+	} else {
+		eb.subscribers[topic] = append(eb.subscribers[topic], s)
+	}
+	eb.mu.Unlock()
+
+	s.start()
+	return &Subscription{
+		unsubscribe: func() {
+			eb.remove(s)
+			s.close()
+		},
+		closed: s.closed,
+	}
+}
+
+// remove detaches a subscriber from its topic
+func (eb *EventBus) remove(s *busSubscriber) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+
+	without := func(list []*busSubscriber) []*busSubscriber {
+		for i, other := range list {
+			if other == s {
+				return append(list[:i:i], list[i+1:]...)
+			}
+		}
+		return list
+	}
+	if isEventPattern(s.topic) {
+		eb.patterns = without(eb.patterns)
+	} else if remaining := without(eb.subscribers[s.topic]); len(remaining) > 0 {
+		eb.subscribers[s.topic] = remaining
+	} else {
+		delete(eb.subscribers, s.topic)
+	}
+}
+
+// deadLetter publishes a failed message to the subscriber's dead-letter topic, or counts it as
+// dropped there once the bus is closed
+func (eb *EventBus) deadLetter(s *busSubscriber, msg *BusMessage, err error) {
+	eb.counters(msg.Topic).deadLettered.Add(1)
+	if msg.Topic == s.options.DeadLetterTopic {
+		fmt.Printf("Warning: dead letter handler %s failed: %v\n", s.options.Name, err)
+		return
+	}
+
+	// Close may already be draining the dead-letter subscribers, which would never handle it
+	eb.mu.RLock()
+	if eb.closed {
+		eb.mu.RUnlock()
+		eb.counters(s.options.DeadLetterTopic).dropped.Add(1)
+		return
+	}
+	eb.inflight.Add(1)
+	eb.mu.RUnlock()
+	defer eb.inflight.Done()
+
+	eb.dispatch(&BusMessage{
+		ID:        strconv.FormatUint(eb.seq.Add(1), 10),
+		Topic:     s.options.DeadLetterTopic,
+		Payload:   &DeadLetter{Message: msg, Subscriber: s.options.Name, Err: err},
+		Headers:   make(map[string]string),
+		Timestamp: eb.clock.Now(),
+	})
+}
+
+// Metrics returns the counters of a topic. Unlike publishing, it does not add the topic to
+// Topics.
+func (eb *EventBus) Metrics(topic string) TopicMetrics {
+	eb.mu.RLock()
+	counters, exists := eb.metrics[topic]
+	eb.mu.RUnlock()
+	if !exists {
+		counters = &topicCounters{}
+	}
+	return TopicMetrics{
+		Published:    counters.published.Load(),
+		Delivered:    counters.delivered.Load(),
+		Failed:       counters.failed.Load(),
+		Retried:      counters.retried.Load(),
+		DeadLettered: counters.deadLettered.Load(),
+		Dropped:      counters.dropped.Load(),
+		Subscribers:  eb.SubscriberCount(topic),
+	}
+}
+
+// Topics returns the topics that have subscribers or messages, sorted
+func (eb *EventBus) Topics() []string {
+	eb.mu.RLock()
+	defer eb.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	for topic := range eb.subscribers {
+		seen[topic] = true
+	}
+	for topic := range eb.metrics {
+		seen[topic] = true
+	}
+	topics := make([]string, 0, len(seen))
+	for topic := range seen {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+	return topics
+}
+
+// SubscriberCount returns the number of subscribers that receive a topic, including patterns
+func (eb *EventBus) SubscriberCount(topic string) int {
+	eb.mu.RLock()
+	defer eb.mu.RUnlock()
+
+	count := len(eb.subscribers[topic])
+	for _, s := range eb.patterns {
+		if MatchEvent(s.topic, topic) {
+			count++
+		}
+	}
+	return count
+}
+
+// Close rejects further publishes and waits until the queued messages have been handled
+func (eb *EventBus) Close() {
+	eb.mu.Lock()
+	if eb.closed {
+		eb.mu.Unlock()
+		return
+	}
+	eb.closed = true
+	eb.mu.Unlock()
+	eb.inflight.Wait()
+
+	eb.mu.RLock()
+	var all []*busSubscriber
+	for _, list := range eb.subscribers {
+		all = append(all, list...)
+	}
+	all = append(all, eb.patterns...)
+	eb.mu.RUnlock()
+
+	for _, s := range all {
+		s.drain()
+	}
+}
+
+// RegisterTopic declares the payload type of a topic; publishing another type to it fails
+// with a ValidationError
+func RegisterTopic[T any](eb *EventBus, key EventKey[T]) *EventBus {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+
+	if _, exists := eb.payloadTypes[key.name]; !exists {
+		eb.payloadTypes[key.name] = payloadType[T]()
+	}
+	return eb
+}
+
+// PublishTopic publishes a typed payload to a topic
+func PublishTopic[T any](eb *EventBus, key EventKey[T], payload T) error {
+	RegisterTopic(eb, key)
+	return eb.Publish(key.name, payload)
+}
+
+// SubscribeTopic subscribes a typed handler to a topic. The message gives access to headers,
+// the attempt number and Reply.
+func SubscribeTopic[T any](eb *EventBus, key EventKey[T], handler func(payload T, msg *BusMessage) error, options ...SubscribeOptions) *Subscription {
+	RegisterTopic(eb, key)
+	return eb.Handle(key.name, func(msg *BusMessage) error {
+		payload, ok := msg.Payload.(T)
+		if !ok && msg.Payload != nil {
+			return checkPayloadType(msg.Topic, payloadType[T](), msg.Payload)
+		}
+		return handler(payload, msg)
+	}, options...)
+}
+
+// busReply settles a request once, with the first reply or after every delivery has ended
+type busReply struct {
+	mu       sync.Mutex
+	fn       func(value interface{}, err error)
+	settled  bool
+	expected bool
+	pending  int
+	err      error
+}
+
+// expect sets the number of deliveries of the request
+func (r *busReply) expect(deliveries int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.expected = true
+	r.pending = deliveries
+}
+
+// dispatched reports whether the request reached dispatch
+func (r *busReply) dispatched() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.expected || r.settled
+}
+
+// settle calls the reply function unless it has been called
+func (r *busReply) settle(value interface{}, err error) {
+	r.mu.Lock()
+	if r.settled {
+		r.mu.Unlock()
+		return
+	}
+	r.settled = true
+	r.mu.Unlock()
+
+	r.fn(value, err)
+}
+
+// done records the end of a delivery; the last one settles an unanswered request
+func (r *busReply) done(topic string, err error) {
+	r.mu.Lock()
+	r.pending--
+	if err != nil {
+		r.err = err
+	}
+	last, lastErr := r.pending == 0, r.err
+	r.mu.Unlock()
+
+	if !last {
+		return
+	}
+	if lastErr == nil {
+		lastErr = NewError(fmt.Sprintf("no reply for topic '%s'", topic), NotFoundError).WithData("topic", topic)
+	}
+	r.settle(nil, lastErr)
+}
+
+// busSubscriber is one subscription of an EventBus with its queue and workers
+type busSubscriber struct {
+	bus       *EventBus
+	topic     string
+	handler   BusHandler
+	options   SubscribeOptions
+	queue     chan *BusMessage
+	stop      chan struct{}
+	draining  chan struct{}
+	stopOnce  sync.Once
+	drainOnce sync.Once
+	workers   sync.WaitGroup
+}
+
+// start starts the workers
+func (s *busSubscriber) start() {
+	s.workers.Add(s.options.Workers)
+	for i := 0; i < s.options.Workers; i++ {
+		go func() {
+			defer s.workers.Done()
+			for {
+				select {
+				case msg := <-s.queue:
+					s.handle(msg)
+				case <-s.stop:
+					return
+				case <-s.draining:
+					for {
+						select {
+						case msg := <-s.queue:
+							s.handle(msg)
+						default:
+							return
+						}
+					}
+				}
+			}
+		}()
+	}
+}
+
+// enqueue queues a message, blocking while the queue is full unless DropWhenFull is set
+func (s *busSubscriber) enqueue(msg *BusMessage) {
+	if s.options.DropWhenFull {
+		select {
+		case s.queue <- msg:
+		case <-s.stop:
+			s.abandon(msg, nil)
+		default:
+			s.bus.counters(msg.Topic).dropped.Add(1)
+			s.abandon(msg, ErrQueueFull)
+		}
+		return
+	}
+	select {
+	case s.queue <- msg:
+	case <-s.stop:
+		s.abandon(msg, nil)
+	}
+}
+
+// abandon gives up on a message that was never handled
+func (s *busSubscriber) abandon(msg *BusMessage, err error) {
+	if err != nil {
+		s.bus.deadLetter(s, msg, err)
+	}
+	if msg.reply != nil {
+		msg.reply.done(msg.Topic, err)
+	}
+}
+
+// handle delivers a message, retrying failures before dead-lettering it
+func (s *busSubscriber) handle(msg *BusMessage) {
+	counters := s.bus.counters(msg.Topic)
+	delay := s.options.RetryDelay
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		delivery := *msg
+		delivery.Attempt = attempt
+		if err = s.call(&delivery); err == nil {
+			counters.delivered.Add(1)
+			if msg.reply != nil {
+				msg.reply.done(msg.Topic, nil)
+			}
+			return
+		}
+		counters.failed.Add(1)
+		if attempt > s.options.MaxRetries || !s.wait(delay) {
+			break
+		}
+		counters.retried.Add(1)
+		delay *= 2
+	}
+	s.abandon(msg, err)
+}
+
+// call runs the handler, converting a panic into an error
+func (s *busSubscriber) call(msg *BusMessage) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = NewError(fmt.Sprintf("bus handler for '%s' panicked: %v", msg.Topic, r), InternalError).
+				WithData("topic", msg.Topic).
+				WithData("panic", r)
+		}
+	}()
+	return s.handler(msg)
+}
+
+// wait waits for a retry delay and reports false if the subscriber stopped meanwhile
+func (s *busSubscriber) wait(delay time.Duration) bool {
+	if delay <= 0 {
+		return !s.closed()
+	}
+	timer := s.bus.clock.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C():
+		return true
+	case <-s.stop:
+		return false
+	}
+}
+
+// close stops the workers, discarding queued messages
+func (s *busSubscriber) close() {
+	s.stopOnce.Do(func() {
+		close(s.stop)
+	})
+	for {
+		select {
+		case msg := <-s.queue:
+			s.abandon(msg, nil)
+		default:
+			return
+		}
+	}
+}
+
+// drain lets the workers handle the queued messages and waits for them to exit
+func (s *busSubscriber) drain() {
+	s.drainOnce.Do(func() {
+		close(s.draining)
+	})
+	s.workers.Wait()
+}
+
+// closed reports whether the subscriber was unsubscribed
+func (s *busSubscriber) closed() bool {
+	select {
+	case <-s.stop:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,91 @@
+package types
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEventBusMetricsDoesNotAddTopic(t *testing.T) {
+	eb := NewEventBus()
+	defer eb.Close()
+
+	if metrics := eb.Metrics("unknown"); metrics != (TopicMetrics{}) {
+		t.Fatalf("unexpected metrics %+v", metrics)
+	}
+	if topics := eb.Topics(); len(topics) != 0 {
+		t.Fatalf("Metrics added topics %v", topics)
+	}
+}
+
+func TestEventBusDropsDeadLettersAfterClose(t *testing.T) {
+	eb := NewEventBus()
+	deadLetters := make(chan interface{}, 1)
+	eb.Subscribe(DeadLetterTopic, func(payload interface{}) { deadLetters <- payload })
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	eb.Handle("jobs", func(msg *BusMessage) error {
+		close(started)
+		<-release
+		return errors.New("failed")
+	})
+	if err := eb.Publish("jobs", 1); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	closed := make(chan struct{})
+	go func() {
+		eb.Close()
+		close(closed)
+	}()
+	for !eb.isClosed() {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("Close blocked on a dead letter")
+	}
+	if dropped := eb.Metrics(DeadLetterTopic).Dropped; dropped != 1 {
+		t.Fatalf("dropped = %d, want 1", dropped)
+	}
+	if len(deadLetters) != 0 {
+		t.Fatal("dead letter was delivered after Close")
+	}
+}
+
+// isClosed reports whether Close has started
+func (eb *EventBus) isClosed() bool {
+	eb.mu.RLock()
+	defer eb.mu.RUnlock()
+	return eb.closed
+}
+
+func TestEventBusDecodePayload(t *testing.T) {
+	type point struct {
+		X, Y int
+	}
+	eb := NewEventBus()
+	defer eb.Close()
+	RegisterTopic(eb, NewEventKey[point]("point"))
+
+	if value, err := eb.DecodePayload("point", []byte(`{"X":1,"Y":2}`)); err != nil || value != (point{1, 2}) {
+		t.Fatalf("registered payload decoded as %#v, %v", value, err)
+	}
+	value, err := eb.DecodePayload("other", []byte(`{"x":[1,"a"]}`))
+	object, ok := value.(map[string]interface{})
+	if err != nil || !ok || !reflect.DeepEqual(object["x"], []interface{}{1.0, "a"}) {
+		t.Fatalf("unregistered payload decoded as %#v, %v", value, err)
+	}
+	if value, err := eb.DecodePayload("point", nil); value != nil || err != nil {
+		t.Fatalf("empty payload decoded as %#v, %v", value, err)
+	}
+	if _, err := eb.DecodePayload("point", []byte(`{"X":"one"}`)); err == nil {
+		t.Fatal("expected an error for a mistyped payload")
+	}
+}
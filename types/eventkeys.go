@@ -0,0 +1,306 @@
+package types
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"typescript-golang/timers"
+)
+
+// EventKey names an event whose payload has type T (like a key of an event map interface in
+// TypeScript). Declare keys once and share them between emitters and listeners:
+//
+//	var UserCreated = types.NewEventKey[UserCreatedEvent]("user:created")
+//
+// Every key for the same name must use the same payload type; using a key whose payload type
+// differs from the one an emitter already saw for the name panics.
+type EventKey[T any] struct {
+	name string
+}
+
+// NewEventKey creates an event key (like declaring a property of an event map in TypeScript)
+func NewEventKey[T any](name string) EventKey[T] {
+	return EventKey[T]{name: name}
+}
+
+// Name returns the event name
+func (k EventKey[T]) Name() string {
+	return k.name
+}
+
+// String returns the event name and payload type
+func (k EventKey[T]) String() string {
+	return fmt.Sprintf("%s(%s)", k.name, payloadType[T]())
+}
+
+// payloadType returns the reflect type of T, including interface types
+func payloadType[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// TypedEventEmitter is an EventEmitter whose events each have their own payload type (like
+// EventEmitter<EventMap> in TypeScript). Listeners and payloads for keyed events are checked at
+// compile time through On, Once, Off, Emit, EmitSync and WaitFor; plugins that only know event
+// names at runtime use the Dynamic methods, which check payloads when they are emitted.
+type TypedEventEmitter struct {
+	emitter      *EventEmitter[interface{}]
+	mu           sync.RWMutex
+	payloadTypes map[string]reflect.Type
+}
+
+// NewTypedEventEmitter creates a new TypedEventEmitter
+func NewTypedEventEmitter() *TypedEventEmitter {
+	return &TypedEventEmitter{
+		emitter:      NewEventEmitter[interface{}](),
+		payloadTypes: make(map[string]reflect.Type),
+	}
+}
+
+// declare records the payload type of an event the first time a key for it is used, and panics
+// with a ValidationError if the event was declared with another payload type
+func (e *TypedEventEmitter) declare(event string, payload reflect.Type) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	declared, exists := e.payloadTypes[event]
+	if !exists {
+		e.payloadTypes[event] = payload
+		return
+	}
+	if declared != payload {
+		panic(NewValidationError(fmt.Sprintf("event key '%s' has payload type %s, but the event was declared with %s", event, payload, declared)).
+			WithData("event", event))
+	}
+}
+
+// typedListener adapts a keyed listener to the untyped emitter
+func typedListener[T any](listener EventListener[T]) EventListener[interface{}] {
+	return func(event interface{}) {
+		if payload, ok := event.(T); ok {
+			listener(payload)
+		} else if event == nil {
+			var zero T
+			listener(zero)
+		}
+	}
+}
+
+// On adds a listener for a keyed event (like emitter.on() in TypeScript)
+func On[T any](e *TypedEventEmitter, key EventKey[T], listener EventListener[T], options ...ListenerOptions) *TypedEventEmitter {
+	e.declare(key.name, payloadType[T]())
+	e.emitter.addEntry(&listenerEntry[interface{}]{fn: typedListener(listener), key: listenerKey(listener), event: key.name, priority: listenerPriority(options)}, false, listenerSignal(options))
+	return e
+}
+
+// Once adds a one-time listener for a keyed event (like emitter.once() in TypeScript)
+func Once[T any](e *TypedEventEmitter, key EventKey[T], listener EventListener[T], options ...ListenerOptions) *TypedEventEmitter {
+	e.declare(key.name, payloadType[T]())
+	e.emitter.addEntry(&listenerEntry[interface{}]{fn: typedListener(listener), key: listenerKey(listener), event: key.name, once: true, priority: listenerPriority(options)}, false, listenerSignal(options))
+	return e
+}
+
+// Off removes a listener for a keyed event (like emitter.off() in TypeScript)
+func Off[T any](e *TypedEventEmitter, key EventKey[T], listener EventListener[T]) *TypedEventEmitter {
+	e.declare(key.name, payloadType[T]())
+	listenerID := listenerKey(listener)
+	e.emitter.removeEntries(key.name, func(entry *listenerEntry[interface{}]) bool {
+		return entry.key == listenerID
+	})
+	return e
+}
+
+// Emit triggers the listeners of a keyed event asynchronously (like emitter.emit() in TypeScript)
+func Emit[T any](e *TypedEventEmitter, key EventKey[T], payload T) bool {
+	e.declare(key.name, payloadType[T]())
+	return e.emitter.Emit(key.name, payload)
+}
+
+// EmitSync triggers the listeners of a keyed event before returning
+func EmitSync[T any](e *TypedEventEmitter, key EventKey[T], payload T) bool {
+	e.declare(key.name, payloadType[T]())
+	return e.emitter.EmitSync(key.name, payload)
+}
+
+// EmitWait triggers the listeners of a keyed event with the executor and waits for them to
+// finish, returning their failures like EventEmitter.EmitWait
+func EmitWait[T any](e *TypedEventEmitter, key EventKey[T], payload T) (bool, error) {
+	e.declare(key.name, payloadType[T]())
+	return e.emitter.EmitWait(key.name, payload)
+}
+
+// StartEmit hands the listeners of a keyed event to the executor and returns a function that
+// waits for them, like EventEmitter.StartEmit
+func StartEmit[T any](e *TypedEventEmitter, key EventKey[T], payload T) (bool, func() error) {
+	e.declare(key.name, payloadType[T]())
+	return e.emitter.StartEmit(key.name, payload)
+}
+
+// WaitFor waits for the next keyed event (like events.once() in Node.js), returning a
+// TimeoutError if the timeout (30 seconds by default) elapses first. The timeout uses the
+// emitter's clock.
+func WaitFor[T any](e *TypedEventEmitter, key EventKey[T], timeout ...time.Duration) (T, error) {
+	timeoutDuration := 30 * time.Second
+	if len(timeout) > 0 {
+		timeoutDuration = timeout[0]
+	}
+	e.declare(key.name, payloadType[T]())
+
+	var zero T
+	if payload, ok := <-e.emitter.waitFor(key.name, timeoutDuration); ok {
+		// Keyed and dynamic emits are checked against the declared type, so only nil differs
+		if value, ok := payload.(T); ok {
+			return value, nil
+		}
+		return zero, nil
+	}
+	return zero, NewError(fmt.Sprintf("timeout waiting for event '%s' after %v", key.name, timeoutDuration), TimeoutError).
+		WithData("event", key.name)
+}
+
+// OnDynamic adds a listener for an event or pattern known only by name, e.g. from a plugin. It
+// receives the payload of any keyed or dynamic emit of a matching event.
+func (e *TypedEventEmitter) OnDynamic(event string, listener EventListener[interface{}], options ...ListenerOptions) *TypedEventEmitter {
+	e.emitter.On(event, listener, options...)
+	return e
+}
+
+// OnceDynamic adds a one-time listener for an event or pattern known only by name
+func (e *TypedEventEmitter) OnceDynamic(event string, listener EventListener[interface{}], options ...ListenerOptions) *TypedEventEmitter {
+	e.emitter.Once(event, listener, options...)
+	return e
+}
+
+// OnAny adds a listener that receives every keyed and dynamic event with its name
+func (e *TypedEventEmitter) OnAny(listener AnyListener[interface{}]) *TypedEventEmitter {
+	e.emitter.OnAny(listener)
+	return e
+}
+
+// OffAny removes an any-listener, or all of them when called without one
+func (e *TypedEventEmitter) OffAny(listener ...AnyListener[interface{}]) *TypedEventEmitter {
+	e.emitter.OffAny(listener...)
+	return e
+}
+
+// OffDynamic removes a listener added with OnDynamic or OnceDynamic
+func (e *TypedEventEmitter) OffDynamic(event string, listener EventListener[interface{}]) *TypedEventEmitter {
+	e.emitter.Off(event, listener)
+	return e
+}
+
+// EmitDynamic emits an event known only by name. If the event has a key, the payload must
+// have the key's payload type; otherwise a ValidationError is returned and no listener is called.
+func (e *TypedEventEmitter) EmitDynamic(event string, payload interface{}) (bool, error) {
+	if err := e.checkPayload(event, payload); err != nil {
+		return false, err
+	}
+	return e.emitter.Emit(event, payload), nil
+}
+
+// EmitDynamicSync is EmitDynamic with listeners executed before returning
+func (e *TypedEventEmitter) EmitDynamicSync(event string, payload interface{}) (bool, error) {
+	if err := e.checkPayload(event, payload); err != nil {
+		return false, err
+	}
+	return e.emitter.EmitSync(event, payload), nil
+}
+
+// checkPayload validates a dynamic payload against the event's declared payload type
+func (e *TypedEventEmitter) checkPayload(event string, payload interface{}) error {
+	e.mu.RLock()
+	expected, declared := e.payloadTypes[event]
+	e.mu.RUnlock()
+
+	if !declared {
+		return nil
+	}
+	return checkPayloadType(event, expected, payload)
+}
+
+// checkPayloadType returns a ValidationError unless payload can be used as the expected type
+func checkPayloadType(event string, expected reflect.Type, payload interface{}) error {
+	if payload == nil {
+		switch expected.Kind() {
+		case reflect.Interface, reflect.Pointer, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+			return nil
+		}
+	} else if reflect.TypeOf(payload).AssignableTo(expected) {
+		return nil
+	}
+	return NewError(fmt.Sprintf("event '%s' expects a %s payload, got %T", event, expected, payload), ValidationError).
+		WithData("event", event)
+}
+
+// EmitError emits err as the "error" event, returning it if nobody handles it
+func (e *TypedEventEmitter) EmitError(err error) error {
+	return e.emitter.EmitError(err)
+}
+
+// OnError adds a handler for listener panics and "error" events
+func (e *TypedEventEmitter) OnError(handler func(err error)) *TypedEventEmitter {
+	e.emitter.OnError(handler)
+	return e
+}
+
+// OffError removes an error handler
+func (e *TypedEventEmitter) OffError(handler func(err error)) *TypedEventEmitter {
+	e.emitter.OffError(handler)
+	return e
+}
+
+// Errors returns a channel that receives listener panics and unhandled "error" events
+func (e *TypedEventEmitter) Errors() <-chan error {
+	return e.emitter.Errors()
+}
+
+// SetExecutor sets the executor that runs listeners for Emit and EmitWait
+func (e *TypedEventEmitter) SetExecutor(executor ListenerExecutor) *TypedEventEmitter {
+	e.emitter.SetExecutor(executor)
+	return e
+}
+
+// SetClock sets the clock that times WaitFor (nil restores timers.Default())
+func (e *TypedEventEmitter) SetClock(clock timers.Clock) *TypedEventEmitter {
+	e.emitter.SetClock(clock)
+	return e
+}
+
+// PayloadType returns the payload type declared for an event by its key, if any
+func (e *TypedEventEmitter) PayloadType(event string) Optional[reflect.Type] {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if payload, exists := e.payloadTypes[event]; exists {
+		return Some(payload)
+	}
+	return None[reflect.Type]()
+}
+
+// RemoveAllListeners removes all listeners for an event or all events
+func (e *TypedEventEmitter) RemoveAllListeners(event ...string) *TypedEventEmitter {
+	e.emitter.RemoveAllListeners(event...)
+	return e
+}
+
+// ListenerCount returns the number of keyed and dynamic listeners for an event
+func (e *TypedEventEmitter) ListenerCount(event string) int {
+	return e.emitter.ListenerCount(event)
+}
+
+// EventNames returns all event names that have listeners
+func (e *TypedEventEmitter) EventNames() []string {
+	return e.emitter.EventNames()
+}
+
+// SetMaxListeners sets the maximum number of listeners per event
+func (e *TypedEventEmitter) SetMaxListeners(max int) *TypedEventEmitter {
+	e.emitter.SetMaxListeners(max)
+	return e
+}
+
+// GetMaxListeners returns the maximum number of listeners per event
+func (e *TypedEventEmitter) GetMaxListeners() int {
+	return e.emitter.GetMaxListeners()
+}
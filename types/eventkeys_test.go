@@ -0,0 +1,95 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"typescript-golang/timers"
+)
+
+// expectPanic fails the test unless fn panics with a ValidationError
+func expectPanic(t *testing.T, name string, fn func()) {
+	t.Helper()
+	defer func() {
+		t.Helper()
+		err, _ := recover().(error)
+		if !IsErrorCode(err, ValidationError) {
+			t.Fatalf("%s: expected a ValidationError panic, got %v", name, err)
+		}
+	}()
+	fn()
+}
+
+func TestEventKeyPayloadMismatchPanics(t *testing.T) {
+	e := NewTypedEventEmitter()
+	userID := NewEventKey[int]("user")
+	userName := NewEventKey[string]("user")
+
+	calls := 0
+	On(e, userID, func(int) { calls++ })
+
+	expectPanic(t, "EmitSync", func() { EmitSync(e, userName, "oops") })
+	expectPanic(t, "On", func() { On(e, userName, func(string) {}) })
+	expectPanic(t, "Once", func() { Once(e, userName, func(string) {}) })
+	expectPanic(t, "WaitFor", func() { WaitFor(e, userName, time.Millisecond) })
+
+	if !EmitSync(e, userID, 1) || calls != 1 {
+		t.Fatalf("matching key: calls = %d", calls)
+	}
+	if _, err := e.EmitDynamicSync("user", "oops"); !IsErrorCode(err, ValidationError) {
+		t.Fatalf("dynamic emit with the wrong payload: %v", err)
+	}
+}
+
+func TestTypedWaitFor(t *testing.T) {
+	clock := timers.NewFakeClock()
+	e := NewTypedEventEmitter().SetClock(clock)
+	ready := NewEventKey[string]("ready")
+
+	results := make(chan string, 1)
+	go func() {
+		value, _ := WaitFor(e, ready, time.Second)
+		results <- value
+	}()
+	clock.BlockUntil(1)
+	EmitSync(e, ready, "go")
+	if value := <-results; value != "go" {
+		t.Fatalf("got %q", value)
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := WaitFor(e, ready, time.Second)
+		errs <- err
+	}()
+	clock.BlockUntil(1)
+	clock.Advance(time.Second)
+	if err := <-errs; !IsErrorCode(err, TimeoutError) {
+		t.Fatalf("expected a TimeoutError, got %v", err)
+	}
+	if count := e.ListenerCount("ready"); count != 0 {
+		t.Fatalf("timed out wait left %d listeners", count)
+	}
+}
+
+func TestEventKeyListenerSignal(t *testing.T) {
+	e := NewTypedEventEmitter()
+	ready := NewEventKey[int]("ready")
+	signal := &testSignal{}
+
+	calls := 0
+	On(e, ready, func(int) { calls++ }, ListenerOptions{Signal: signal})
+	Once(e, ready, func(int) { calls++ }, ListenerOptions{Signal: signal})
+	if count := e.ListenerCount("ready"); count != 2 {
+		t.Fatalf("ListenerCount = %d, want 2", count)
+	}
+
+	signal.abort()
+	if count := e.ListenerCount("ready"); count != 0 {
+		t.Fatalf("ListenerCount = %d after abort, want 0", count)
+	}
+	EmitSync(e, ready, 1)
+	if calls != 0 || signal.active() != 0 {
+		t.Fatalf("calls = %d, active signal listeners = %d", calls, signal.active())
+	}
+}
@@ -3,86 +3,311 @@ package types
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"typescript-golang/timers"
 )
 
 // EventListener represents a function that handles events
 type EventListener[T any] func(event T)
 
-// EventEmitter represents TypeScript's EventEmitter pattern
+// AnyListener handles every emitted event along with its name (like emitter.onAny() in EventEmitter2)
+type AnyListener[T any] func(event string, data T)
+
+// ListenerOptions configures how a listener is registered
+type ListenerOptions struct {
+	// Priority orders listeners: higher priorities run first, equal priorities run in
+	// registration order (default 0)
+	Priority int
+	// Signal removes the listener when it aborts (like the signal option of events.once() in
+	// Node.js); a listener is not added for a signal that has already aborted
+	Signal Signal
+}
+
+// listenerEntry is a registered listener. key identifies the listener passed by the caller,
+// which may differ from fn when the listener was wrapped.
+type listenerEntry[T any] struct {
+	fn       EventListener[T]
+	key      uintptr
+	event    string
+	once     bool
+	priority int
+	seq      int64
+	fired    atomic.Bool
+	// detach stops listening to the entry's signal; it is set and called with the lock held
+	detach  func()
+	removed bool
+}
+
+// runsBefore reports whether the entry is called before other
+func (entry *listenerEntry[T]) runsBefore(other *listenerEntry[T]) bool {
+	if entry.priority != other.priority {
+		return entry.priority > other.priority
+	}
+	return entry.seq < other.seq
+}
+
+// anyEntry is a registered AnyListener
+type anyEntry[T any] struct {
+	fn  AnyListener[T]
+	key uintptr
+}
+
+// EventEmitter represents TypeScript's EventEmitter pattern. Event names are namespaced with
+// ":" and listeners may subscribe to patterns: "*" matches one segment ("user:*") and "**"
+// matches any number of segments ("user:**", "**"), like EventEmitter2.
 type EventEmitter[T any] struct {
-	listeners    map[string][]EventListener[T]
-	onceListeners map[string][]EventListener[T]
+	listeners    map[string][]*listenerEntry[T]
+	wildcards    *patternTrie[T]
+	anyListeners []*anyEntry[T]
 	mu           sync.RWMutex
 	maxListeners int
+	seq          int64
+	executor     ListenerExecutor
+	onError      []*errorEntry
+	errors       chan error
+	clock        timers.Clock
+}
+
+// ErrorEvent is the event name with Node's "error" contract: emitting it when nobody listens
+// reports the error instead of dropping it
+const ErrorEvent = "error"
+
+// errorEntry is a registered error handler
+type errorEntry struct {
+	fn  func(err error)
+	key uintptr
 }
 
 // NewEventEmitter creates a new EventEmitter (like new EventEmitter() in TypeScript)
 func NewEventEmitter[T any]() *EventEmitter[T] {
 	return &EventEmitter[T]{
-		listeners:     make(map[string][]EventListener[T]),
-		onceListeners: make(map[string][]EventListener[T]),
-		maxListeners:  10, // Default max listeners like Node.js
+		listeners:    make(map[string][]*listenerEntry[T]),
+		wildcards:    &patternTrie[T]{},
+		maxListeners: 10, // Default max listeners like Node.js
+		executor:     GoroutineExecutor,
 	}
 }
 
-// On adds a listener for the specified event (like emitter.on() in TypeScript)
-func (ee *EventEmitter[T]) On(event string, listener EventListener[T]) *EventEmitter[T] {
+// listenerKey returns the identity used by Off to find a listener
+func listenerKey(listener interface{}) uintptr {
+	return reflect.ValueOf(listener).Pointer()
+}
+
+// listenerPriority returns the priority of the optional listener options
+func listenerPriority(options []ListenerOptions) int {
+	if len(options) > 0 {
+		return options[0].Priority
+	}
+	return 0
+}
+
+// listenerSignal returns the signal of the optional listener options
+func listenerSignal(options []ListenerOptions) Signal {
+	if len(options) > 0 {
+		return options[0].Signal
+	}
+	return nil
+}
+
+// On adds a listener for the specified event or pattern (like emitter.on() in TypeScript)
+func (ee *EventEmitter[T]) On(event string, listener EventListener[T], options ...ListenerOptions) *EventEmitter[T] {
+	ee.addEntry(&listenerEntry[T]{fn: listener, key: listenerKey(listener), event: event, priority: listenerPriority(options)}, false, listenerSignal(options))
+	return ee
+}
+
+// AddListener is an alias for On (like emitter.addListener() in Node.js)
+func (ee *EventEmitter[T]) AddListener(event string, listener EventListener[T], options ...ListenerOptions) *EventEmitter[T] {
+	return ee.On(event, listener, options...)
+}
+
+// Once adds a one-time listener (like emitter.once() in TypeScript)
+func (ee *EventEmitter[T]) Once(event string, listener EventListener[T], options ...ListenerOptions) *EventEmitter[T] {
+	ee.addEntry(&listenerEntry[T]{fn: listener, key: listenerKey(listener), event: event, once: true, priority: listenerPriority(options)}, false, listenerSignal(options))
+	return ee
+}
+
+// PrependListener adds a listener that runs before the listeners of the same priority that
+// are already registered (like emitter.prependListener() in Node.js)
+func (ee *EventEmitter[T]) PrependListener(event string, listener EventListener[T], options ...ListenerOptions) *EventEmitter[T] {
+	ee.addEntry(&listenerEntry[T]{fn: listener, key: listenerKey(listener), event: event, priority: listenerPriority(options)}, true, listenerSignal(options))
+	return ee
+}
+
+// PrependOnceListener adds a one-time listener that runs before the listeners of the same
+// priority that are already registered (like emitter.prependOnceListener() in Node.js)
+func (ee *EventEmitter[T]) PrependOnceListener(event string, listener EventListener[T], options ...ListenerOptions) *EventEmitter[T] {
+	ee.addEntry(&listenerEntry[T]{fn: listener, key: listenerKey(listener), event: event, once: true, priority: listenerPriority(options)}, true, listenerSignal(options))
+	return ee
+}
+
+// addEntry registers a listener entry, keeping the event's listeners in call order. An
+// optional signal removes the entry when it aborts.
+func (ee *EventEmitter[T]) addEntry(entry *listenerEntry[T], prepend bool, signal ...Signal) {
+	if len(signal) > 0 && signal[0] != nil {
+		if signal[0].Aborted() {
+			return
+		}
+		defer ee.attachSignal(entry, signal[0])
+	}
+
 	ee.mu.Lock()
 	defer ee.mu.Unlock()
-	
-	ee.listeners[event] = append(ee.listeners[event], listener)
-	
+
+	ee.seq++
+	entry.seq = ee.seq
+	if prepend {
+		entry.seq = -ee.seq
+	}
+
+	var entries []*listenerEntry[T]
+	node := (*patternTrie[T])(nil)
+	if isEventPattern(entry.event) {
+		node = ee.wildcards.node(entry.event, true)
+		entries = node.entries
+	} else {
+		entries = ee.listeners[entry.event]
+	}
+
+	i := len(entries)
+	for i > 0 && entry.runsBefore(entries[i-1]) {
+		i--
+	}
+	entries = append(entries, nil)
+	copy(entries[i+1:], entries[i:])
+	entries[i] = entry
+
+	if node != nil {
+		node.entries = entries
+	} else {
+		ee.listeners[entry.event] = entries
+	}
+
 	// Check max listeners
-	if len(ee.listeners[event]) > ee.maxListeners {
-		fmt.Printf("Warning: EventEmitter has %d listeners for event '%s'. This may indicate a memory leak.\n", 
-			len(ee.listeners[event]), event)
+	if ee.maxListeners > 0 && len(entries) > ee.maxListeners {
+		fmt.Printf("Warning: EventEmitter has %d listeners for event '%s'. This may indicate a memory leak.\n",
+			len(entries), entry.event)
 	}
-	
+}
+
+// attachSignal removes an added entry when signal aborts. It is called without the lock, since
+// an aborted signal calls its listener immediately.
+func (ee *EventEmitter[T]) attachSignal(entry *listenerEntry[T], signal Signal) {
+	detach := signal.OnAbort(func(error) {
+		ee.removeEntries(entry.event, func(other *listenerEntry[T]) bool {
+			return other == entry
+		})
+	})
+
+	ee.mu.Lock()
+	defer ee.mu.Unlock()
+
+	// The entry may have fired or been removed before the abort listener was registered
+	if entry.removed {
+		detach()
+	} else {
+		entry.detach = detach
+	}
+}
+
+// OnAny adds a listener that receives every event with its name (like emitter.onAny() in
+// EventEmitter2). Any-listeners run before the event's own listeners.
+func (ee *EventEmitter[T]) OnAny(listener AnyListener[T]) *EventEmitter[T] {
+	ee.mu.Lock()
+	defer ee.mu.Unlock()
+
+	ee.anyListeners = append(ee.anyListeners, &anyEntry[T]{fn: listener, key: listenerKey(listener)})
 	return ee
 }
 
-// AddListener is an alias for On (like emitter.addListener() in Node.js)
-func (ee *EventEmitter[T]) AddListener(event string, listener EventListener[T]) *EventEmitter[T] {
-	return ee.On(event, listener)
+// PrependAny adds an any-listener that runs before the existing ones (like emitter.prependAny() in EventEmitter2)
+func (ee *EventEmitter[T]) PrependAny(listener AnyListener[T]) *EventEmitter[T] {
+	ee.mu.Lock()
+	defer ee.mu.Unlock()
+
+	ee.anyListeners = append([]*anyEntry[T]{{fn: listener, key: listenerKey(listener)}}, ee.anyListeners...)
+	return ee
 }
 
-// Once adds a one-time listener (like emitter.once() in TypeScript)
-func (ee *EventEmitter[T]) Once(event string, listener EventListener[T]) *EventEmitter[T] {
+// OffAny removes an any-listener, or all of them when called without one (like emitter.offAny() in EventEmitter2)
+func (ee *EventEmitter[T]) OffAny(listener ...AnyListener[T]) *EventEmitter[T] {
 	ee.mu.Lock()
 	defer ee.mu.Unlock()
-	
-	ee.onceListeners[event] = append(ee.onceListeners[event], listener)
+
+	if len(listener) == 0 {
+		ee.anyListeners = nil
+		return ee
+	}
+	key := listenerKey(listener[0])
+	for i, entry := range ee.anyListeners {
+		if entry.key == key {
+			ee.anyListeners = append(ee.anyListeners[:i:i], ee.anyListeners[i+1:]...)
+			break
+		}
+	}
 	return ee
 }
 
-// Off removes a listener (like emitter.off() in TypeScript)
+// Off removes a listener from an event or pattern (like emitter.off() in TypeScript)
 func (ee *EventEmitter[T]) Off(event string, listener EventListener[T]) *EventEmitter[T] {
+	key := listenerKey(listener)
+	ee.removeEntries(event, func(entry *listenerEntry[T]) bool {
+		return entry.key == key
+	})
+	return ee
+}
+
+// removeEntries removes the first listener of an event or pattern that matches
+func (ee *EventEmitter[T]) removeEntries(event string, match func(entry *listenerEntry[T]) bool) {
 	ee.mu.Lock()
 	defer ee.mu.Unlock()
-	
-	// Remove from regular listeners
-	if listeners, exists := ee.listeners[event]; exists {
-		for i, l := range listeners {
-			if reflect.ValueOf(l).Pointer() == reflect.ValueOf(listener).Pointer() {
-				ee.listeners[event] = append(listeners[:i], listeners[i+1:]...)
-				break
+
+	ee.removeEntry(event, match)
+}
+
+// removeEntry removes the first listener that matches; the caller holds the lock
+func (ee *EventEmitter[T]) removeEntry(event string, match func(entry *listenerEntry[T]) bool) bool {
+	if isEventPattern(event) {
+		node := ee.wildcards.node(event, false)
+		if node == nil {
+			return false
+		}
+		for i, entry := range node.entries {
+			if match(entry) {
+				node.entries = append(node.entries[:i:i], node.entries[i+1:]...)
+				ee.wildcards.prune(strings.Split(event, EventDelimiter))
+				entry.release()
+				return true
 			}
 		}
+		return false
 	}
-	
-	// Remove from once listeners
-	if onceListeners, exists := ee.onceListeners[event]; exists {
-		for i, l := range onceListeners {
-			if reflect.ValueOf(l).Pointer() == reflect.ValueOf(listener).Pointer() {
-				ee.onceListeners[event] = append(onceListeners[:i], onceListeners[i+1:]...)
-				break
+
+	entries := ee.listeners[event]
+	for i, entry := range entries {
+		if match(entry) {
+			if len(entries) == 1 {
+				delete(ee.listeners, event)
+			} else {
+				ee.listeners[event] = append(entries[:i:i], entries[i+1:]...)
 			}
+			entry.release()
+			return true
 		}
 	}
-	
-	return ee
+	return false
+}
+
+// release marks a removed entry and stops listening to its signal; the caller holds the lock
+func (entry *listenerEntry[T]) release() {
+	entry.removed = true
+	if entry.detach != nil {
+		entry.detach()
+		entry.detach = nil
+	}
 }
 
 // RemoveListener is an alias for Off
@@ -90,228 +315,406 @@ func (ee *EventEmitter[T]) RemoveListener(event string, listener EventListener[T
 	return ee.Off(event, listener)
 }
 
-// RemoveAllListeners removes all listeners for an event or all events
+// RemoveAllListeners removes all listeners for an event or pattern, or for all events.
+// Any-listeners are only removed when no event is given.
 func (ee *EventEmitter[T]) RemoveAllListeners(event ...string) *EventEmitter[T] {
 	ee.mu.Lock()
 	defer ee.mu.Unlock()
-	
+
 	if len(event) == 0 {
 		// Remove all listeners for all events
-		ee.listeners = make(map[string][]EventListener[T])
-		ee.onceListeners = make(map[string][]EventListener[T])
+		for _, entries := range ee.listeners {
+			releaseEntries(entries)
+		}
+		ee.wildcards.each(releaseEntries[T])
+		ee.listeners = make(map[string][]*listenerEntry[T])
+		ee.wildcards = &patternTrie[T]{}
+		ee.anyListeners = nil
+	} else if isEventPattern(event[0]) {
+		// Remove all listeners for a specific pattern
+		if node := ee.wildcards.node(event[0], false); node != nil {
+			releaseEntries(node.entries)
+			node.entries = nil
+			ee.wildcards.prune(strings.Split(event[0], EventDelimiter))
+		}
 	} else {
 		// Remove all listeners for specific event
-		eventName := event[0]
-		delete(ee.listeners, eventName)
-		delete(ee.onceListeners, eventName)
+		releaseEntries(ee.listeners[event[0]])
+		delete(ee.listeners, event[0])
 	}
-	
+
 	return ee
 }
 
-// Emit triggers all listeners for the specified event (like emitter.emit() in TypeScript)
+// releaseEntries releases removed entries; the caller holds the lock
+func releaseEntries[T any](entries []*listenerEntry[T]) {
+	for _, entry := range entries {
+		entry.release()
+	}
+}
+
+// Emit triggers all listeners for the specified event (like emitter.emit() in TypeScript).
+// Listeners are handed to the executor in priority order; with the default executor they run
+// concurrently on their own goroutines.
 func (ee *EventEmitter[T]) Emit(event string, data T) bool {
-	ee.mu.Lock()
-	
-	// Get regular listeners
-	listeners := make([]EventListener[T], len(ee.listeners[event]))
-	copy(listeners, ee.listeners[event])
-	
-	// Get once listeners and remove them
-	onceListeners := make([]EventListener[T], len(ee.onceListeners[event]))
-	copy(onceListeners, ee.onceListeners[event])
-	delete(ee.onceListeners, event)
-	
-	ee.mu.Unlock()
-	
-	hadListeners := len(listeners) > 0 || len(onceListeners) > 0
-	
-	// Execute regular listeners
-	for _, listener := range listeners {
-		go listener(data) // Execute asynchronously like in Node.js
-	}
-	
-	// Execute once listeners
-	for _, listener := range onceListeners {
-		go listener(data)
-	}
-	
-	return hadListeners
-}
-
-// EmitSync emits event synchronously (all listeners execute before returning)
+	called, _ := ee.emit(event, data, ee.getExecutor(), false)
+	return called
+}
+
+// EmitSync emits event synchronously (all listeners execute in priority order before returning)
 func (ee *EventEmitter[T]) EmitSync(event string, data T) bool {
-	ee.mu.Lock()
-	
-	// Get regular listeners
-	listeners := make([]EventListener[T], len(ee.listeners[event]))
-	copy(listeners, ee.listeners[event])
-	
-	// Get once listeners and remove them
-	onceListeners := make([]EventListener[T], len(ee.onceListeners[event]))
-	copy(onceListeners, ee.onceListeners[event])
-	delete(ee.onceListeners, event)
-	
-	ee.mu.Unlock()
-	
-	hadListeners := len(listeners) > 0 || len(onceListeners) > 0
-	
-	// Execute regular listeners synchronously
-	for _, listener := range listeners {
-		listener(data)
-	}
-	
-	// Execute once listeners synchronously
-	for _, listener := range onceListeners {
-		listener(data)
-	}
-	
-	return hadListeners
-}
-
-// ListenerCount returns the number of listeners for an event
-func (ee *EventEmitter[T]) ListenerCount(event string) int {
-	ee.mu.RLock()
-	defer ee.mu.RUnlock()
-	
-	return len(ee.listeners[event]) + len(ee.onceListeners[event])
+	called, _ := ee.emit(event, data, InlineExecutor, false)
+	return called
+}
+
+// EmitWait emits an event with the executor and waits for every listener to finish. It returns
+// whether the event had listeners and the listeners' failures: the recovered panic of a single
+// listener, or an AggregateError for several. An unhandled "error" event returns its error.
+func (ee *EventEmitter[T]) EmitWait(event string, data T) (bool, error) {
+	called, wait := ee.StartEmit(event, data)
+	return called, wait()
+}
+
+// StartEmit is EmitWait split in two: the listeners are matched and handed to the executor
+// before StartEmit returns, and wait blocks until they finish and returns their failures
+func (ee *EventEmitter[T]) StartEmit(event string, data T) (bool, func() error) {
+	called, wait := ee.emit(event, data, ee.getExecutor(), true)
+	return called, func() error {
+		failures := wait()
+		switch len(failures) {
+		case 0:
+			return nil
+		case 1:
+			return failures[0]
+		default:
+			return NewAggregateError(fmt.Sprintf("%d listeners for event '%s' failed", len(failures), event), failures)
+		}
+	}
 }
 
-// EventNames returns all event names that have listeners
-func (ee *EventEmitter[T]) EventNames() []string {
-	ee.mu.RLock()
-	defer ee.mu.RUnlock()
-	
-	eventSet := make(map[string]bool)
-	
-	for event := range ee.listeners {
-		eventSet[event] = true
+// EmitError emits err as the "error" event (like emitter.emit('error', err) in Node.js). Error
+// handlers and, when T can hold the error, "error" listeners receive it. If nobody handles it,
+// EmitError returns err, where Node.js would throw.
+func (ee *EventEmitter[T]) EmitError(err error) error {
+	data, ok := interface{}(err).(T)
+	if !ok {
+		if !ee.handleError(err) {
+			return err
+		}
+		return nil
 	}
-	
-	for event := range ee.onceListeners {
-		eventSet[event] = true
+	_, wait := ee.emit(ErrorEvent, data, InlineExecutor, true)
+	if failures := wait(); len(failures) > 0 {
+		return failures[0]
 	}
-	
-	var events []string
-	for event := range eventSet {
-		events = append(events, event)
+	return nil
+}
+
+// emit hands the listeners matching an event to executor. Listener panics are recovered and
+// reported as errors. When collect is set, the returned function waits for every listener to
+// return and returns the failures; otherwise it returns nothing.
+func (ee *EventEmitter[T]) emit(event string, data T, executor ListenerExecutor, collect bool) (bool, func() []error) {
+	anyListeners, listeners := ee.matchListeners(event)
+	called := len(anyListeners) > 0 || len(listeners) > 0
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		failures []error
+	)
+
+	// Like Node.js, an "error" event that nobody listens for is not silently dropped
+	if event == ErrorEvent && len(listeners) == 0 {
+		err := errorPayload(data)
+		if ee.handleError(err) {
+			called = true
+		} else if collect {
+			failures = append(failures, err)
+		} else {
+			fmt.Printf("Warning: unhandled 'error' event: %v\n", err)
+		}
+	}
+
+	// Failures keep listener order regardless of which listener finishes first
+	slots := make([]error, len(anyListeners)+len(listeners))
+	run := func(slot int, call func()) {
+		wg.Add(1)
+		executor.Execute(func() {
+			defer wg.Done()
+			if err := invokeListener(event, call); err != nil {
+				// Waiting callers receive the failure, so it is only printed for plain emits
+				if collect {
+					ee.handleError(err)
+				} else {
+					ee.reportError(err)
+				}
+				mu.Lock()
+				slots[slot] = err
+				mu.Unlock()
+			}
+		})
+	}
+
+	// The executor may call the listeners later, so each closure gets its own copy
+	for i, listener := range anyListeners {
+		listener := listener
+		run(i, func() { listener(event, data) })
+	}
+	for i, listener := range listeners {
+		listener := listener
+		run(len(anyListeners)+i, func() { listener(data) })
+	}
+
+	return called, func() []error {
+		wg.Wait()
+		if !collect {
+			return nil
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for _, err := range slots {
+			if err != nil {
+				failures = append(failures, err)
+			}
+		}
+		return failures
 	}
-	
-	return events
 }
 
-// SetMaxListeners sets the maximum number of listeners per event
-func (ee *EventEmitter[T]) SetMaxListeners(max int) *EventEmitter[T] {
+// invokeListener calls a listener, converting a panic into an EnhancedError
+func invokeListener(event string, call func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = NewError(fmt.Sprintf("listener for event '%s' panicked: %v", event, r), InternalError).
+				WithData("event", event).
+				WithData("panic", r)
+		}
+	}()
+	call()
+	return nil
+}
+
+// errorPayload returns the error carried by an "error" event
+func errorPayload(data interface{}) error {
+	if err, ok := data.(error); ok && err != nil {
+		return err
+	}
+	return NewError(fmt.Sprintf("unhandled 'error' event: %v", data), InternalError).WithData("payload", data)
+}
+
+// OnError adds a handler for errors: listener panics and "error" events (like
+// emitter.on('error') in Node.js). Handlers run on the goroutine that hit the error.
+func (ee *EventEmitter[T]) OnError(handler func(err error)) *EventEmitter[T] {
 	ee.mu.Lock()
 	defer ee.mu.Unlock()
-	
-	ee.maxListeners = max
+
+	ee.onError = append(ee.onError, &errorEntry{fn: handler, key: listenerKey(handler)})
 	return ee
 }
 
-// GetMaxListeners returns the maximum number of listeners per event
-func (ee *EventEmitter[T]) GetMaxListeners() int {
-	ee.mu.RLock()
-	defer ee.mu.RUnlock()
-	
-	return ee.maxListeners
+// OffError removes an error handler
+func (ee *EventEmitter[T]) OffError(handler func(err error)) *EventEmitter[T] {
+	ee.mu.Lock()
+	defer ee.mu.Unlock()
+
+	key := listenerKey(handler)
+	for i, entry := range ee.onError {
+		if entry.key == key {
+			ee.onError = append(ee.onError[:i:i], ee.onError[i+1:]...)
+			break
+		}
+	}
+	return ee
 }
 
-// Observable represents TypeScript's Observable pattern (RxJS-like)
-type Observable[T any] struct {
-	emitter *EventEmitter[T]
+// Errors returns a channel that receives listener panics and unhandled "error" events as
+// errors. The channel buffers up to 64 errors; errors are dropped while it is full.
+func (ee *EventEmitter[T]) Errors() <-chan error {
+	ee.mu.Lock()
+	defer ee.mu.Unlock()
+
+	if ee.errors == nil {
+		ee.errors = make(chan error, 64)
+	}
+	return ee.errors
 }
 
-// NewObservable creates a new Observable
-func NewObservable[T any]() *Observable[T] {
-	return &Observable[T]{
-		emitter: NewEventEmitter[T](),
+// handleError delivers an error to the error handlers and the Errors channel and reports
+// whether anything received it
+func (ee *EventEmitter[T]) handleError(err error) bool {
+	ee.mu.RLock()
+	handlers := make([]func(error), len(ee.onError))
+	for i, entry := range ee.onError {
+		handlers[i] = entry.fn
+	}
+	errors := ee.errors
+	ee.mu.RUnlock()
+
+	for _, handler := range handlers {
+		if panicErr := invokeListener(ErrorEvent, func() { handler(err) }); panicErr != nil {
+			fmt.Printf("Warning: error handler panicked: %v\n", panicErr)
+		}
+	}
+	if errors != nil {
+		select {
+		case errors <- err:
+		default:
+		}
 	}
+	return len(handlers) > 0 || errors != nil
 }
 
-// Subscribe subscribes to the observable (like observable.subscribe() in RxJS)
-func (obs *Observable[T]) Subscribe(observer EventListener[T]) *Subscription {
-	obs.emitter.On("data", observer)
-	
-	return &Subscription{
-		unsubscribe: func() {
-			obs.emitter.Off("data", observer)
-		},
+// reportError handles a listener failure, printing it when nothing handles it
+func (ee *EventEmitter[T]) reportError(err error) {
+	if !ee.handleError(err) {
+		fmt.Printf("Warning: %v\n", err)
 	}
 }
 
-// Next emits the next value
-func (obs *Observable[T]) Next(value T) {
-	obs.emitter.Emit("data", value)
+// SetExecutor sets the executor that runs listeners for Emit and EmitWait (nil restores the
+// default goroutine-per-listener executor). EmitSync always runs listeners inline.
+func (ee *EventEmitter[T]) SetExecutor(executor ListenerExecutor) *EventEmitter[T] {
+	ee.mu.Lock()
+	defer ee.mu.Unlock()
+
+	if executor == nil {
+		executor = GoroutineExecutor
+	}
+	ee.executor = executor
+	return ee
 }
 
-// Error emits an error
-func (obs *Observable[T]) Error(err error) {
-	obs.emitter.Emit("error", *new(T)) // Placeholder for error event
+// SetClock sets the clock that times WaitFor and WaitForChan (nil restores timers.Default())
+func (ee *EventEmitter[T]) SetClock(clock timers.Clock) *EventEmitter[T] {
+	ee.mu.Lock()
+	defer ee.mu.Unlock()
+
+	ee.clock = clock
+	return ee
 }
 
-// Complete signals completion
-func (obs *Observable[T]) Complete() {
-	obs.emitter.Emit("complete", *new(T))
+// getClock returns the emitter's clock
+func (ee *EventEmitter[T]) getClock() timers.Clock {
+	ee.mu.RLock()
+	defer ee.mu.RUnlock()
+
+	return timers.Resolve(ee.clock)
 }
 
-// Subscription represents a subscription to an observable
-type Subscription struct {
-	unsubscribe func()
+// getExecutor returns the emitter's executor
+func (ee *EventEmitter[T]) getExecutor() ListenerExecutor {
+	ee.mu.RLock()
+	defer ee.mu.RUnlock()
+
+	return ee.executor
 }
 
-// Unsubscribe cancels the subscription
-func (s *Subscription) Unsubscribe() {
-	if s.unsubscribe != nil {
-		s.unsubscribe()
+// matchListeners returns the any-listeners and the listeners matching an event in call
+// order, and removes the matched once listeners
+func (ee *EventEmitter[T]) matchListeners(event string) ([]AnyListener[T], []EventListener[T]) {
+	ee.mu.RLock()
+	anyListeners := make([]AnyListener[T], len(ee.anyListeners))
+	for i, entry := range ee.anyListeners {
+		anyListeners[i] = entry.fn
+	}
+	entries := ee.matchEntries(event)
+	ee.mu.RUnlock()
+
+	listeners := make([]EventListener[T], 0, len(entries))
+	var fired []*listenerEntry[T]
+	for _, entry := range entries {
+		if entry.once {
+			// A concurrent emit may have matched the same once listener
+			if !entry.fired.CompareAndSwap(false, true) {
+				continue
+			}
+			fired = append(fired, entry)
+		}
+		listeners = append(listeners, entry.fn)
 	}
-}
 
-// Subject represents a subject (both observable and observer)
-type Subject[T any] struct {
-	*Observable[T]
-	subscribers []EventListener[T]
-	mu          sync.RWMutex
+	if len(fired) > 0 {
+		ee.mu.Lock()
+		for _, entry := range fired {
+			ee.removeEntry(entry.event, func(other *listenerEntry[T]) bool {
+				return other == entry
+			})
+		}
+		ee.mu.Unlock()
+	}
+
+	return anyListeners, listeners
 }
 
-// NewSubject creates a new Subject
-func NewSubject[T any]() *Subject[T] {
-	return &Subject[T]{
-		Observable:  NewObservable[T](),
-		subscribers: make([]EventListener[T], 0),
+// matchEntries returns the entries whose event or pattern matches event in call order; the
+// caller holds the lock
+func (ee *EventEmitter[T]) matchEntries(event string) []*listenerEntry[T] {
+	exact := ee.listeners[event]
+	if len(ee.wildcards.children) == 0 {
+		return append([]*listenerEntry[T](nil), exact...)
+	}
+
+	// Every list is already in call order, so merging them avoids sorting on each emit
+	nodes := ee.wildcards.match(strings.Split(event, EventDelimiter), nil)
+	lists := make([][]*listenerEntry[T], 0, len(nodes)+1)
+	lists = append(lists, exact)
+	for _, node := range nodes {
+		lists = append(lists, node.entries)
 	}
+	return mergeEntries(lists)
 }
 
-// Next emits a value to all subscribers
-func (s *Subject[T]) Next(value T) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	
-	for _, subscriber := range s.subscribers {
-		go subscriber(value)
+// ListenerCount returns the number of listeners an emit of the event would call, including
+// wildcard listeners. Passing a pattern counts the listeners registered for that pattern.
+func (ee *EventEmitter[T]) ListenerCount(event string) int {
+	ee.mu.RLock()
+	defer ee.mu.RUnlock()
+
+	if isEventPattern(event) {
+		if node := ee.wildcards.node(event, false); node != nil {
+			return len(node.entries)
+		}
+		return 0
 	}
+	return len(ee.matchEntries(event))
 }
 
-// Subscribe adds a subscriber
-func (s *Subject[T]) Subscribe(observer EventListener[T]) *Subscription {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	
-	s.subscribers = append(s.subscribers, observer)
-	
-	return &Subscription{
-		unsubscribe: func() {
-			s.mu.Lock()
-			defer s.mu.Unlock()
-			
-			for i, sub := range s.subscribers {
-				if reflect.ValueOf(sub).Pointer() == reflect.ValueOf(observer).Pointer() {
-					s.subscribers = append(s.subscribers[:i], s.subscribers[i+1:]...)
-					break
-				}
-			}
-		},
+// ListenerCountAny returns the number of any-listeners
+func (ee *EventEmitter[T]) ListenerCountAny() int {
+	ee.mu.RLock()
+	defer ee.mu.RUnlock()
+
+	return len(ee.anyListeners)
+}
+
+// EventNames returns all event names and patterns that have listeners
+func (ee *EventEmitter[T]) EventNames() []string {
+	ee.mu.RLock()
+	defer ee.mu.RUnlock()
+
+	var events []string
+	for event := range ee.listeners {
+		events = append(events, event)
 	}
+
+	return ee.wildcards.patterns(nil, events)
+}
+
+// SetMaxListeners sets the maximum number of listeners per event
+func (ee *EventEmitter[T]) SetMaxListeners(max int) *EventEmitter[T] {
+	ee.mu.Lock()
+	defer ee.mu.Unlock()
+
+	ee.maxListeners = max
+	return ee
+}
+
+// GetMaxListeners returns the maximum number of listeners per event
+func (ee *EventEmitter[T]) GetMaxListeners() int {
+	ee.mu.RLock()
+	defer ee.mu.RUnlock()
+
+	return ee.maxListeners
 }
 
 // Event represents a generic event with data
@@ -332,95 +735,80 @@ func NewEvent[T any](eventType string, data T, source interface{}) *Event[T] {
 	}
 }
 
-// EventBus represents a global event bus for decoupled communication
-type EventBus struct {
-	emitters map[string]*EventEmitter[interface{}]
-	mu       sync.RWMutex
-}
-
-// NewEventBus creates a new EventBus
-func NewEventBus() *EventBus {
-	return &EventBus{
-		emitters: make(map[string]*EventEmitter[interface{}]),
+// WaitForChan returns a channel that receives the next emission of an event, or is closed
+// without a value if the timeout (30 seconds by default) elapses first. The one-time listener is
+// removed on timeout, so waits that time out do not pile up on the emitter.
+func (ee *EventEmitter[T]) WaitForChan(event string, timeout ...time.Duration) <-chan T {
+	timeoutDuration := 30 * time.Second
+	if len(timeout) > 0 {
+		timeoutDuration = timeout[0]
 	}
+	return ee.waitFor(event, timeoutDuration)
 }
 
-// Publish publishes an event to the bus
-func (eb *EventBus) Publish(eventType string, data interface{}) {
-	eb.mu.RLock()
-	emitter, exists := eb.emitters[eventType]
-	eb.mu.RUnlock()
-	
-	if exists {
-		emitter.Emit("data", data)
-	}
-}
+// waitFor registers a one-time listener and settles the returned channel exactly once: with
+// the first emission, or by closing it and removing the listener when the timeout elapses
+func (ee *EventEmitter[T]) waitFor(event string, timeout time.Duration) <-chan T {
+	result := make(chan T, 1)
+	var (
+		mu      sync.Mutex
+		settled bool
+		timer   timers.Timer
+	)
 
-// Subscribe subscribes to events of a specific type
-func (eb *EventBus) Subscribe(eventType string, listener EventListener[interface{}]) *Subscription {
-	eb.mu.Lock()
-	defer eb.mu.Unlock()
-	
-	if _, exists := eb.emitters[eventType]; !exists {
-		eb.emitters[eventType] = NewEventEmitter[interface{}]()
+	entry := &listenerEntry[T]{event: event, once: true}
+	entry.fn = func(data T) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if settled {
+			return
+		}
+		settled = true
+		result <- data
+		close(result)
+		if timer != nil {
+			timer.Stop()
+		}
 	}
-	
-	eb.emitters[eventType].On("data", listener)
-	
-	return &Subscription{
-		unsubscribe: func() {
-			eb.mu.RLock()
-			defer eb.mu.RUnlock()
-			
-			if emitter, exists := eb.emitters[eventType]; exists {
-				emitter.Off("data", listener)
+	ee.addEntry(entry, false)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if !settled {
+		timer = ee.getClock().AfterFunc(timeout, func() {
+			mu.Lock()
+			if settled {
+				mu.Unlock()
+				return
 			}
-		},
+			settled = true
+			close(result)
+			mu.Unlock()
+
+			ee.removeEntries(event, func(other *listenerEntry[T]) bool {
+				return other == entry
+			})
+		})
 	}
+	return result
 }
 
-// WaitForChan waits for an event and returns a channel
-func (ee *EventEmitter[T]) WaitForChan(event string, timeout ...time.Duration) <-chan T {
-	resultChan := make(chan T, 1)
-	timeoutDuration := 30 * time.Second // Default timeout
-	
+// WaitFor waits for the next emission of an event, returning a TimeoutError if the timeout
+// (30 seconds by default) elapses first
+func (ee *EventEmitter[T]) WaitFor(event string, timeout ...time.Duration) (T, error) {
+	timeoutDuration := 30 * time.Second
 	if len(timeout) > 0 {
 		timeoutDuration = timeout[0]
 	}
-	
-	// Set up one-time listener
-	listener := func(data T) {
-		select {
-		case resultChan <- data:
-		default:
-		}
-	}
-	
-	ee.Once(event, listener)
-	
-	// Set up timeout
-	go func() {
-		time.Sleep(timeoutDuration)
-		close(resultChan)
-	}()
-	
-	return resultChan
-}
 
-// WaitFor waits for an event with optional timeout
-func (ee *EventEmitter[T]) WaitFor(event string, timeout ...time.Duration) (T, error) {
-	resultChan := ee.WaitForChan(event, timeout...)
-	
-	if result, ok := <-resultChan; ok {
+	if result, ok := <-ee.waitFor(event, timeoutDuration); ok {
 		return result, nil
 	}
-	
 	var zero T
-	timeoutDuration := 30 * time.Second
-	if len(timeout) > 0 {
-		timeoutDuration = timeout[0]
-	}
-	return zero, fmt.Errorf("timeout waiting for event '%s' after %v", event, timeoutDuration)
+	return zero, NewError(fmt.Sprintf("timeout waiting for event '%s' after %v", event, timeoutDuration), TimeoutError).
+		WithData("event", event)
 }
 
 // Pipe creates a pipeline of event transformations (like RxJS operators)
@@ -447,67 +835,119 @@ func (ep *EventPipe[T, U]) Subscribe(observer EventListener[U]) *Subscription {
 
 // Filter operator for event streams
 func Filter[T any](source *Observable[T], predicate func(T) bool) *Observable[T] {
-	result := NewObservable[T]()
-	
-	source.Subscribe(func(value T) {
-		if predicate(value) {
-			result.Next(value)
-		}
+	return NewObservableFrom(func(subscriber Subscriber[T]) Teardown {
+		subscribeTo(source, subscriber, Observer[T]{
+			Next: func(value T) {
+				if predicate(value) {
+					subscriber.Next(value)
+				}
+			},
+			Error:    subscriber.Error,
+			Complete: subscriber.Complete,
+		})
+		return nil
 	})
-	
-	return result
 }
 
-// Map operator for event streams  
+// Map operator for event streams
 func ObservableMap[T, U any](source *Observable[T], transformer func(T) U) *Observable[U] {
-	result := NewObservable[U]()
-	
-	source.Subscribe(func(value T) {
-		transformed := transformer(value)
-		result.Next(transformed)
+	return NewObservableFrom(func(subscriber Subscriber[U]) Teardown {
+		subscribeTo(source, subscriber, Observer[T]{
+			Next: func(value T) {
+				subscriber.Next(transformer(value))
+			},
+			Error:    subscriber.Error,
+			Complete: subscriber.Complete,
+		})
+		return nil
 	})
-	
-	return result
 }
 
-// Debounce operator - emits only after a pause in events
-func Debounce[T any](source *Observable[T], delay time.Duration) *Observable[T] {
-	result := NewObservable[T]()
-	var timer *time.Timer
-	var mu sync.Mutex
-	
-	source.Subscribe(func(value T) {
-		mu.Lock()
-		defer mu.Unlock()
-		
-		if timer != nil {
-			timer.Stop()
+// Debounce operator - emits only after a pause in events. A pending value is emitted
+// when the source completes.
+// An optional clock replaces the default one, e.g. a timers.FakeClock in tests.
+func Debounce[T any](source *Observable[T], delay time.Duration, clock ...timers.Clock) *Observable[T] {
+	c := timers.Resolve(clock...)
+	return NewObservableFrom(func(subscriber Subscriber[T]) Teardown {
+		var timer timers.Timer
+		var pending *T
+		var mu sync.Mutex
+
+		// flush stops the timer and returns the pending value, if any
+		flush := func() *T {
+			mu.Lock()
+			defer mu.Unlock()
+
+			if timer != nil {
+				timer.Stop()
+				timer = nil
+			}
+			value := pending
+			pending = nil
+			return value
 		}
-		
-		timer = time.AfterFunc(delay, func() {
-			result.Next(value)
+
+		subscribeTo(source, subscriber, Observer[T]{
+			Next: func(value T) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				if timer != nil {
+					timer.Stop()
+				}
+				pending = &value
+				timer = c.AfterFunc(delay, func() {
+					mu.Lock()
+					if pending != &value {
+						mu.Unlock()
+						return
+					}
+					pending = nil
+					mu.Unlock()
+					subscriber.Next(value)
+				})
+			},
+			Error: func(err error) {
+				flush()
+				subscriber.Error(err)
+			},
+			Complete: func() {
+				if value := flush(); value != nil {
+					subscriber.Next(*value)
+				}
+				subscriber.Complete()
+			},
 		})
+
+		return func() { flush() }
 	})
-	
-	return result
 }
 
-// Throttle operator - emits at most once per time period
-func Throttle[T any](source *Observable[T], interval time.Duration) *Observable[T] {
-	result := NewObservable[T]()
-	var lastEmit time.Time
-	var mu sync.Mutex
-	
-	source.Subscribe(func(value T) {
-		mu.Lock()
-		defer mu.Unlock()
-		
-		now := time.Now()
-		if now.Sub(lastEmit) >= interval {
-			lastEmit = now
-			result.Next(value)
-		}
+// Throttle operator - emits at most once per time period.
+// An optional clock replaces the default one, e.g. a timers.FakeClock in tests.
+func Throttle[T any](source *Observable[T], interval time.Duration, clock ...timers.Clock) *Observable[T] {
+	c := timers.Resolve(clock...)
+	return NewObservableFrom(func(subscriber Subscriber[T]) Teardown {
+		var lastEmit time.Time
+		var mu sync.Mutex
+
+		subscribeTo(source, subscriber, Observer[T]{
+			Next: func(value T) {
+				mu.Lock()
+				now := c.Now()
+				emit := lastEmit.IsZero() || now.Sub(lastEmit) >= interval
+				if emit {
+					lastEmit = now
+				}
+				mu.Unlock()
+
+				if emit {
+					subscriber.Next(value)
+				}
+			},
+			Error:    subscriber.Error,
+			Complete: subscriber.Complete,
+		})
+		return nil
 	})
-	
-	return result
-}
\ No newline at end of file
+}
@@ -0,0 +1,153 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"typescript-golang/timers"
+)
+
+// testSignal is a minimal Signal for tests
+type testSignal struct {
+	aborted   bool
+	listeners []func(reason error)
+}
+
+func (s *testSignal) Aborted() bool { return s.aborted }
+
+func (s *testSignal) OnAbort(listener func(reason error)) func() {
+	if s.aborted {
+		listener(nil)
+		return func() {}
+	}
+	i := len(s.listeners)
+	s.listeners = append(s.listeners, listener)
+	return func() { s.listeners[i] = nil }
+}
+
+func (s *testSignal) abort() {
+	s.aborted = true
+	for _, listener := range s.listeners {
+		if listener != nil {
+			listener(nil)
+		}
+	}
+}
+
+func (s *testSignal) active() int {
+	n := 0
+	for _, listener := range s.listeners {
+		if listener != nil {
+			n++
+		}
+	}
+	return n
+}
+
+func TestListenerSignalRemovesListener(t *testing.T) {
+	emitter := NewEventEmitter[int]()
+	signal := &testSignal{}
+	calls := 0
+	emitter.On("tick", func(int) { calls++ }, ListenerOptions{Signal: signal})
+	emitter.On("user:*", func(int) { calls++ }, ListenerOptions{Signal: signal})
+
+	emitter.EmitSync("tick", 1)
+	signal.abort()
+	emitter.EmitSync("tick", 2)
+	emitter.EmitSync("user:created", 3)
+
+	if calls != 1 || emitter.ListenerCount("tick") != 0 || emitter.ListenerCount("user:*") != 0 {
+		t.Fatalf("calls = %d, listeners = %d", calls, emitter.ListenerCount("tick"))
+	}
+
+	emitter.On("tick", func(int) { calls++ }, ListenerOptions{Signal: signal})
+	if emitter.ListenerCount("tick") != 0 {
+		t.Fatal("listener added with an aborted signal")
+	}
+}
+
+func TestListenerSignalDetachesOnRemoval(t *testing.T) {
+	emitter := NewEventEmitter[int]()
+	signal := &testSignal{}
+	emitter.Once("tick", func(int) {}, ListenerOptions{Signal: signal})
+	emitter.On("tock", func(int) {}, ListenerOptions{Signal: signal})
+	emitter.On("a:**", func(int) {}, ListenerOptions{Signal: signal})
+
+	emitter.EmitSync("tick", 1)
+	emitter.RemoveAllListeners()
+	if n := signal.active(); n != 0 {
+		t.Fatalf("removed listeners still listen to the signal (%d)", n)
+	}
+}
+
+func TestWaitForTimeoutRemovesListener(t *testing.T) {
+	clock := timers.NewFakeClock()
+	emitter := NewEventEmitter[int]().SetClock(clock)
+
+	results := make(chan error, 1)
+	go func() {
+		_, err := emitter.WaitFor("ready", time.Second)
+		results <- err
+	}()
+	clock.BlockUntil(1)
+	clock.Advance(time.Second)
+
+	if err := <-results; !IsErrorCode(err, TimeoutError) {
+		t.Fatalf("expected a TimeoutError, got %v", err)
+	}
+	if count := emitter.ListenerCount("ready"); count != 0 {
+		t.Fatalf("timed out wait left %d listeners", count)
+	}
+}
+
+func TestWaitForChan(t *testing.T) {
+	clock := timers.NewFakeClock()
+	emitter := NewEventEmitter[int]().SetClock(clock)
+
+	received := emitter.WaitForChan("ready", time.Second)
+	emitter.EmitSync("ready", 1)
+	emitter.EmitSync("ready", 2)
+	if v, ok := <-received; !ok || v != 1 {
+		t.Fatalf("got %v, %v", v, ok)
+	}
+	if _, ok := <-received; ok {
+		t.Fatal("channel not closed after the event")
+	}
+	if clock.TimerCount() != 0 {
+		t.Fatal("timer not stopped after the event")
+	}
+
+	expired := emitter.WaitForChan("later", time.Second)
+	clock.Advance(time.Second)
+	if _, ok := <-expired; ok {
+		t.Fatal("channel received a value after the timeout")
+	}
+	// Emitting after the timeout must not send on the closed channel
+	emitter.EmitSync("later", 3)
+}
+
+func TestWildcardListenersRunInCallOrder(t *testing.T) {
+	ee := NewEventEmitter[int]()
+	var calls []string
+	record := func(name string) EventListener[int] {
+		return func(int) {
+			calls = append(calls, name)
+		}
+	}
+	ee.On("user:created", record("exact"))
+	ee.On("user:*", record("wildcard"))
+	ee.On("**", record("globstar"), ListenerOptions{Priority: 1})
+	ee.On("user:**", record("user-globstar"))
+	ee.PrependListener("user:created", record("prepended"))
+
+	ee.EmitSync("user:created", 1)
+	want := []string{"globstar", "prepended", "exact", "wildcard", "user-globstar"}
+	if len(calls) != len(want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Fatalf("calls = %v, want %v", calls, want)
+		}
+	}
+}
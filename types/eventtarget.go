@@ -0,0 +1,460 @@
+package types
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Signal is an abort signal that can remove event listeners, satisfied by *async.AbortSignal
+type Signal interface {
+	Aborted() bool
+	// OnAbort registers a listener for the abort and returns a function that removes it
+	OnAbort(listener func(reason error)) func()
+}
+
+// EventPhase is the phase of a dispatch (like Event.eventPhase in the DOM)
+type EventPhase int
+
+const (
+	// PhaseNone means the event is not being dispatched
+	PhaseNone EventPhase = iota
+	// CapturingPhase runs capture listeners from the root down to the target's parent
+	CapturingPhase
+	// AtTarget runs the listeners of the target itself
+	AtTarget
+	// BubblingPhase runs listeners from the target's parent up to the root
+	BubblingPhase
+)
+
+// String returns the phase name
+func (p EventPhase) String() string {
+	switch p {
+	case CapturingPhase:
+		return "capturing"
+	case AtTarget:
+		return "at-target"
+	case BubblingPhase:
+		return "bubbling"
+	default:
+		return "none"
+	}
+}
+
+// DOMEvent is an event dispatched through a tree of EventTargets (like Event in the DOM).
+// Implement it by embedding *BaseEvent, as CustomEvent does.
+type DOMEvent interface {
+	Type() string
+	Bubbles() bool
+	Cancelable() bool
+	TimeStamp() time.Time
+	Target() EventTarget
+	CurrentTarget() EventTarget
+	EventPhase() EventPhase
+	ComposedPath() []EventTarget
+	StopPropagation()
+	StopImmediatePropagation()
+	PreventDefault()
+	DefaultPrevented() bool
+	base() *BaseEvent
+}
+
+// EventInit configures a new event (like EventInit in the DOM)
+type EventInit struct {
+	Bubbles    bool
+	Cancelable bool
+}
+
+// BaseEvent implements DOMEvent (like new Event(type, init) in the DOM). An event is dispatched
+// on one goroutine; listeners must not share it with other goroutines during the dispatch.
+type BaseEvent struct {
+	eventType        string
+	bubbles          bool
+	cancelable       bool
+	timeStamp        time.Time
+	target           EventTarget
+	currentTarget    EventTarget
+	phase            EventPhase
+	path             []EventTarget
+	dispatching      bool
+	stopped          bool
+	stoppedImmediate bool
+	defaultPrevented bool
+	inPassive        bool
+}
+
+// NewBaseEvent creates an event; without init it neither bubbles nor is cancelable
+func NewBaseEvent(eventType string, init ...EventInit) *BaseEvent {
+	e := &BaseEvent{eventType: eventType, timeStamp: time.Now()}
+	if len(init) > 0 {
+		e.bubbles = init[0].Bubbles
+		e.cancelable = init[0].Cancelable
+	}
+	return e
+}
+
+func (e *BaseEvent) base() *BaseEvent { return e }
+
+// Type returns the event type
+func (e *BaseEvent) Type() string { return e.eventType }
+
+// Bubbles reports whether the event runs the bubbling phase
+func (e *BaseEvent) Bubbles() bool { return e.bubbles }
+
+// Cancelable reports whether PreventDefault has an effect
+func (e *BaseEvent) Cancelable() bool { return e.cancelable }
+
+// TimeStamp returns the creation time of the event
+func (e *BaseEvent) TimeStamp() time.Time { return e.timeStamp }
+
+// Target returns the target the event was dispatched on
+func (e *BaseEvent) Target() EventTarget { return e.target }
+
+// CurrentTarget returns the target whose listeners are running, or nil outside a dispatch
+func (e *BaseEvent) CurrentTarget() EventTarget { return e.currentTarget }
+
+// EventPhase returns the current dispatch phase
+func (e *BaseEvent) EventPhase() EventPhase { return e.phase }
+
+// ComposedPath returns the targets of the dispatch from the target up to the root, or nil
+// outside a dispatch (like event.composedPath() in the DOM)
+func (e *BaseEvent) ComposedPath() []EventTarget {
+	if !e.dispatching {
+		return nil
+	}
+	return append([]EventTarget(nil), e.path...)
+}
+
+// StopPropagation stops the dispatch after the listeners of the current target
+func (e *BaseEvent) StopPropagation() {
+	e.stopped = true
+}
+
+// StopImmediatePropagation stops the dispatch after the running listener
+func (e *BaseEvent) StopImmediatePropagation() {
+	e.stopped = true
+	e.stoppedImmediate = true
+}
+
+// PreventDefault cancels a cancelable event, unless called from a passive listener
+func (e *BaseEvent) PreventDefault() {
+	if e.cancelable && !e.inPassive {
+		e.defaultPrevented = true
+	}
+}
+
+// DefaultPrevented reports whether PreventDefault canceled the event
+func (e *BaseEvent) DefaultPrevented() bool { return e.defaultPrevented }
+
+// CustomEvent is an event carrying typed data (like CustomEvent<T> in the DOM)
+type CustomEvent[T any] struct {
+	*BaseEvent
+	Detail T
+}
+
+// NewCustomEvent creates a CustomEvent (like new CustomEvent(type, { detail }) in the DOM)
+func NewCustomEvent[T any](eventType string, detail T, init ...EventInit) *CustomEvent[T] {
+	return &CustomEvent[T]{BaseEvent: NewBaseEvent(eventType, init...), Detail: detail}
+}
+
+// AddEventListenerOptions configures a listener (like AddEventListenerOptions in the DOM)
+type AddEventListenerOptions struct {
+	// Capture runs the listener in the capturing phase instead of the bubbling phase
+	Capture bool
+	// Once removes the listener before its first call
+	Once bool
+	// Passive ignores PreventDefault calls from the listener
+	Passive bool
+	// Signal removes the listener when it aborts
+	Signal Signal
+}
+
+// EventTarget receives events and propagates them along its parent chain (like EventTarget in
+// the DOM). Embed *BaseEventTarget to implement it.
+type EventTarget interface {
+	AddEventListener(eventType string, listener EventListener[DOMEvent], options ...AddEventListenerOptions) func()
+	RemoveEventListener(eventType string, listener EventListener[DOMEvent], capture ...bool)
+	DispatchEvent(event DOMEvent) bool
+	// ParentTarget returns the next target of the propagation path, or nil at the root
+	ParentTarget() EventTarget
+	// invoke runs the listeners of one target for a phase
+	invoke(event DOMEvent, capture bool)
+}
+
+// targetListener is one listener of a BaseEventTarget
+type targetListener struct {
+	fn      EventListener[DOMEvent]
+	key     uintptr
+	capture bool
+	once    bool
+	passive bool
+	removed bool
+	cleanup func()
+}
+
+// BaseEventTarget implements EventTarget with an optional parent. Components of a tree embed
+// it and call SetOwner so that listeners see the component as the event target.
+type BaseEventTarget struct {
+	mu        sync.RWMutex
+	parent    EventTarget
+	owner     interface{}
+	listeners map[string][]*targetListener
+}
+
+// NewEventTarget creates an EventTarget with an optional parent (like new EventTarget() in
+// the DOM)
+func NewEventTarget(parent ...EventTarget) *BaseEventTarget {
+	t := &BaseEventTarget{listeners: make(map[string][]*targetListener)}
+	if len(parent) > 0 {
+		t.parent = parent[0]
+	}
+	return t
+}
+
+// ParentTarget returns the parent target, or nil at the root
+func (t *BaseEventTarget) ParentTarget() EventTarget {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.parent
+}
+
+// SetParent moves the target under another parent, or to the root with nil
+func (t *BaseEventTarget) SetParent(parent EventTarget) *BaseEventTarget {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.parent = parent
+	return t
+}
+
+// Owner returns the value set with SetOwner, e.g. the component embedding the target
+func (t *BaseEventTarget) Owner() interface{} {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return t.owner
+}
+
+// SetOwner records the value embedding the target. If the owner implements EventTarget, events
+// dispatched on this target report the owner as Target and CurrentTarget.
+func (t *BaseEventTarget) SetOwner(owner interface{}) *BaseEventTarget {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.owner = owner
+	return t
+}
+
+// AddEventListener adds a listener (like target.addEventListener() in the DOM) and returns a
+// function that removes it. Go functions cannot be compared, so unlike in the DOM every call
+// adds a listener, even for a function that was already added.
+func (t *BaseEventTarget) AddEventListener(eventType string, listener EventListener[DOMEvent], options ...AddEventListenerOptions) func() {
+	var opts AddEventListenerOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+	if listener == nil || (opts.Signal != nil && opts.Signal.Aborted()) {
+		return func() {}
+	}
+
+	entry := &targetListener{fn: listener, key: listenerKey(listener), capture: opts.Capture, once: opts.Once, passive: opts.Passive}
+	t.mu.Lock()
+	t.listeners[eventType] = append(t.listeners[eventType], entry)
+	t.mu.Unlock()
+	remove := func() {
+		t.removeEntry(eventType, entry)
+	}
+
+	if opts.Signal != nil {
+		detach := opts.Signal.OnAbort(func(error) {
+			remove()
+		})
+		t.mu.Lock()
+		if entry.removed {
+			t.mu.Unlock()
+			detach()
+			return remove
+		}
+		entry.cleanup = detach
+		t.mu.Unlock()
+	}
+	return remove
+}
+
+// RemoveEventListener removes a listener added for the phase given by capture (default
+// bubbling), like target.removeEventListener() in the DOM. Listeners are matched by their
+// function code, which closures created by the same function literal share, so it removes the
+// first of them; the function returned by AddEventListener removes exactly one listener.
+func (t *BaseEventTarget) RemoveEventListener(eventType string, listener EventListener[DOMEvent], capture ...bool) {
+	useCapture := len(capture) > 0 && capture[0]
+	key := listenerKey(listener)
+
+	t.mu.RLock()
+	var match *targetListener
+	for _, entry := range t.listeners[eventType] {
+		if entry.key == key && entry.capture == useCapture {
+			match = entry
+			break
+		}
+	}
+	t.mu.RUnlock()
+
+	if match != nil {
+		t.removeEntry(eventType, match)
+	}
+}
+
+// removeEntry removes a listener entry and releases its signal
+func (t *BaseEventTarget) removeEntry(eventType string, entry *targetListener) {
+	t.mu.Lock()
+	if entry.removed {
+		t.mu.Unlock()
+		return
+	}
+	entry.removed = true
+	list := t.listeners[eventType]
+	for i, other := range list {
+		if other == entry {
+			list = append(list[:i:i], list[i+1:]...)
+			break
+		}
+	}
+	if len(list) > 0 {
+		t.listeners[eventType] = list
+	} else {
+		delete(t.listeners, eventType)
+	}
+	cleanup := entry.cleanup
+	t.mu.Unlock()
+
+	if cleanup != nil {
+		cleanup()
+	}
+}
+
+// ListenerCount returns the number of listeners for an event type
+func (t *BaseEventTarget) ListenerCount(eventType string) int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return len(t.listeners[eventType])
+}
+
+// DispatchEvent dispatches an event to this target through the capture, target and bubble
+// phases (like target.dispatchEvent() in the DOM). It returns false if the event is cancelable
+// and a listener called PreventDefault. An event that is already being dispatched is ignored.
+// Listener panics are recovered and reported, and do not stop the dispatch.
+func (t *BaseEventTarget) DispatchEvent(event DOMEvent) bool {
+	return Dispatch(t.self(), event)
+}
+
+// self returns the owner if it is an EventTarget, otherwise the target itself
+func (t *BaseEventTarget) self() EventTarget {
+	if owner, ok := t.Owner().(EventTarget); ok {
+		return owner
+	}
+	return t
+}
+
+// Dispatch dispatches an event to any EventTarget, e.g. one whose DispatchEvent is overridden
+func Dispatch(target EventTarget, event DOMEvent) bool {
+	e := event.base()
+	if e.dispatching {
+		return false
+	}
+
+	// Build the path from the target to the root, guarding against parent cycles
+	path := []EventTarget{target}
+	seen := map[EventTarget]bool{target: true}
+	for parent := target.ParentTarget(); parent != nil && !seen[parent]; parent = parent.ParentTarget() {
+		seen[parent] = true
+		path = append(path, parent)
+	}
+
+	e.dispatching = true
+	e.target = target
+	e.path = path
+
+	for i := len(path) - 1; i > 0 && !e.stopped; i-- {
+		e.phase = CapturingPhase
+		e.currentTarget = path[i]
+		path[i].invoke(event, true)
+	}
+	if !e.stopped {
+		e.phase = AtTarget
+		e.currentTarget = target
+		target.invoke(event, true)
+		if !e.stopped {
+			target.invoke(event, false)
+		}
+	}
+	if e.bubbles {
+		for i := 1; i < len(path) && !e.stopped; i++ {
+			e.phase = BubblingPhase
+			e.currentTarget = path[i]
+			path[i].invoke(event, false)
+		}
+	}
+
+	e.phase = PhaseNone
+	e.currentTarget = nil
+	e.path = nil
+	e.dispatching = false
+	e.stopped = false
+	e.stoppedImmediate = false
+	return !e.defaultPrevented
+}
+
+// invoke runs the listeners registered for a phase, in the order they were added
+func (t *BaseEventTarget) invoke(event DOMEvent, capture bool) {
+	e := event.base()
+
+	t.mu.RLock()
+	listeners := append([]*targetListener(nil), t.listeners[e.eventType]...)
+	t.mu.RUnlock()
+
+	for _, entry := range listeners {
+		if entry.capture != capture {
+			continue
+		}
+		t.mu.RLock()
+		removed := entry.removed
+		t.mu.RUnlock()
+		if removed {
+			continue
+		}
+		if entry.once {
+			t.removeEntry(e.eventType, entry)
+		}
+
+		e.inPassive = entry.passive
+		callDOMListener(entry.fn, event)
+		e.inPassive = false
+		if e.stoppedImmediate {
+			return
+		}
+	}
+}
+
+// callDOMListener calls a listener, reporting a panic instead of aborting the dispatch
+func callDOMListener(listener EventListener[DOMEvent], event DOMEvent) {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Printf("Warning: listener for event '%s' panicked: %v\n", event.Type(), r)
+		}
+	}()
+	listener(event)
+}
+
+// AddCustomEventListener adds a listener that receives the CustomEvent[T] events of a type
+// with their typed Detail; other events of the type are skipped. It returns a function that
+// removes the listener.
+func AddCustomEventListener[T any](target EventTarget, eventType string, listener func(event *CustomEvent[T]), options ...AddEventListenerOptions) func() {
+	wrapped := EventListener[DOMEvent](func(event DOMEvent) {
+		if custom, ok := event.(*CustomEvent[T]); ok {
+			listener(custom)
+		}
+	})
+	return target.AddEventListener(eventType, wrapped, options...)
+}
@@ -0,0 +1,59 @@
+package types
+
+import "testing"
+
+func TestEventTargetClosuresAreDistinctListeners(t *testing.T) {
+	target := NewEventTarget()
+	var calls []string
+	listen := func(name string) func() {
+		return target.AddEventListener("ping", func(DOMEvent) {
+			calls = append(calls, name)
+		})
+	}
+	removeFirst := listen("first")
+	listen("second")
+
+	target.DispatchEvent(NewBaseEvent("ping"))
+	if len(calls) != 2 {
+		t.Fatalf("calls = %v, want both closures", calls)
+	}
+
+	calls = nil
+	removeFirst()
+	removeFirst()
+	target.DispatchEvent(NewBaseEvent("ping"))
+	if len(calls) != 1 || calls[0] != "second" {
+		t.Fatalf("calls = %v, want [second]", calls)
+	}
+}
+
+func TestAddCustomEventListenerRemovesItsOwnListener(t *testing.T) {
+	target := NewEventTarget()
+	var details []int
+	listen := func(offset int) func() {
+		return AddCustomEventListener(target, "count", func(e *CustomEvent[int]) {
+			details = append(details, e.Detail+offset)
+		})
+	}
+	listen(0)
+	removeSecond := listen(100)
+
+	removeSecond()
+	target.DispatchEvent(NewCustomEvent("count", 1))
+	if len(details) != 1 || details[0] != 1 {
+		t.Fatalf("details = %v, want [1]", details)
+	}
+}
+
+func TestEventTargetSignalRemovesListener(t *testing.T) {
+	target := NewEventTarget()
+	signal := &testSignal{}
+	calls := 0
+	target.AddEventListener("ping", func(DOMEvent) { calls++ }, AddEventListenerOptions{Signal: signal})
+
+	signal.abort()
+	target.DispatchEvent(NewBaseEvent("ping"))
+	if calls != 0 || signal.active() != 0 {
+		t.Fatalf("calls = %d, active signal listeners = %d", calls, signal.active())
+	}
+}
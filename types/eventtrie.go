@@ -0,0 +1,156 @@
+package types
+
+import "strings"
+
+const (
+	// EventDelimiter separates the segments of namespaced event names like "user:created"
+	EventDelimiter = ":"
+	// EventWildcard matches exactly one segment of an event name, e.g. "user:*"
+	EventWildcard = "*"
+	// EventGlobstar matches any number of segments, including none, e.g. "user:**" or "**"
+	EventGlobstar = "**"
+)
+
+// isEventPattern reports whether an event name contains wildcard segments
+func isEventPattern(event string) bool {
+	for _, segment := range strings.Split(event, EventDelimiter) {
+		if segment == EventWildcard || segment == EventGlobstar {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchEvent reports whether an event name matches a pattern (like EventEmitter2's wildcard
+// matching). "*" matches one segment and "**" matches any number of segments.
+func MatchEvent(pattern, event string) bool {
+	return matchSegments(strings.Split(pattern, EventDelimiter), strings.Split(event, EventDelimiter))
+}
+
+// matchSegments matches split pattern and event segments
+func matchSegments(pattern, event []string) bool {
+	if len(pattern) == 0 {
+		return len(event) == 0
+	}
+	if pattern[0] == EventGlobstar {
+		for i := 0; i <= len(event); i++ {
+			if matchSegments(pattern[1:], event[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(event) == 0 || (pattern[0] != EventWildcard && pattern[0] != event[0]) {
+		return false
+	}
+	return matchSegments(pattern[1:], event[1:])
+}
+
+// patternTrie stores wildcard listeners by pattern segment, so an emit only visits the
+// patterns that can match its event name
+type patternTrie[T any] struct {
+	children map[string]*patternTrie[T]
+	entries  []*listenerEntry[T]
+}
+
+// node returns the node for a pattern, creating it when create is true
+func (t *patternTrie[T]) node(pattern string, create bool) *patternTrie[T] {
+	node := t
+	for _, segment := range strings.Split(pattern, EventDelimiter) {
+		child, exists := node.children[segment]
+		if !exists {
+			if !create {
+				return nil
+			}
+			if node.children == nil {
+				node.children = make(map[string]*patternTrie[T])
+			}
+			child = &patternTrie[T]{}
+			node.children[segment] = child
+		}
+		node = child
+	}
+	return node
+}
+
+// prune removes the nodes of a pattern that no longer hold listeners
+func (t *patternTrie[T]) prune(segments []string) bool {
+	if len(segments) > 0 {
+		if child, exists := t.children[segments[0]]; exists && child.prune(segments[1:]) {
+			delete(t.children, segments[0])
+		}
+	}
+	return len(t.entries) == 0 && len(t.children) == 0
+}
+
+// match appends every node with listeners whose pattern matches the event segments, once each
+func (t *patternTrie[T]) match(segments []string, out []*patternTrie[T]) []*patternTrie[T] {
+	if len(segments) == 0 {
+		if len(t.entries) > 0 && !containsNode(out, t) {
+			out = append(out, t)
+		}
+	} else {
+		if child, exists := t.children[segments[0]]; exists {
+			out = child.match(segments[1:], out)
+		}
+		if child, exists := t.children[EventWildcard]; exists {
+			out = child.match(segments[1:], out)
+		}
+	}
+	if child, exists := t.children[EventGlobstar]; exists {
+		for i := 0; i <= len(segments); i++ {
+			out = child.match(segments[i:], out)
+		}
+	}
+	return out
+}
+
+// containsNode reports whether nodes holds node; an emit matches few patterns, so a scan is
+// cheaper than a set
+func containsNode[T any](nodes []*patternTrie[T], node *patternTrie[T]) bool {
+	for _, other := range nodes {
+		if other == node {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeEntries merges listener lists that are each in call order into one list in call order
+func mergeEntries[T any](lists [][]*listenerEntry[T]) []*listenerEntry[T] {
+	total := 0
+	for _, list := range lists {
+		total += len(list)
+	}
+	merged := make([]*listenerEntry[T], 0, total)
+	for len(merged) < total {
+		next := -1
+		for i, list := range lists {
+			if len(list) > 0 && (next < 0 || list[0].runsBefore(lists[next][0])) {
+				next = i
+			}
+		}
+		merged = append(merged, lists[next][0])
+		lists[next] = lists[next][1:]
+	}
+	return merged
+}
+
+// each calls fn with the listeners of every node
+func (t *patternTrie[T]) each(fn func(entries []*listenerEntry[T])) {
+	fn(t.entries)
+	for _, child := range t.children {
+		child.each(fn)
+	}
+}
+
+// patterns appends the patterns that have listeners
+func (t *patternTrie[T]) patterns(prefix []string, out []string) []string {
+	if len(t.entries) > 0 {
+		out = append(out, strings.Join(prefix, EventDelimiter))
+	}
+	for segment, child := range t.children {
+		out = child.patterns(append(prefix[:len(prefix):len(prefix)], segment), out)
+	}
+	return out
+}
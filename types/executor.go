@@ -0,0 +1,81 @@
+package types
+
+import "sync"
+
+// ListenerExecutor runs the listener calls of an emit (like the scheduler behind an
+// EventEmitter). Execute may run the task inline, on a new goroutine or on a worker.
+type ListenerExecutor interface {
+	Execute(task func())
+}
+
+// ExecutorFunc adapts a function to a ListenerExecutor
+type ExecutorFunc func(task func())
+
+// Execute calls f(task)
+func (f ExecutorFunc) Execute(task func()) {
+	f(task)
+}
+
+var (
+	// GoroutineExecutor runs every listener call on its own goroutine (the EventEmitter default)
+	GoroutineExecutor ListenerExecutor = ExecutorFunc(func(task func()) { go task() })
+	// InlineExecutor runs listener calls one after another on the emitting goroutine
+	InlineExecutor ListenerExecutor = ExecutorFunc(func(task func()) { task() })
+)
+
+// WorkerPoolExecutor runs listener calls on a fixed number of worker goroutines, bounding the
+// concurrency of busy emitters. Execute blocks while the queue is full. Listeners must not wait
+// on emits that use the same pool, or the pool can deadlock.
+type WorkerPoolExecutor struct {
+	tasks  chan func()
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewWorkerPoolExecutor starts a pool of workers (at least one) with an optional queue size
+// (default 2 * workers)
+func NewWorkerPoolExecutor(workers int, queueSize ...int) *WorkerPoolExecutor {
+	if workers < 1 {
+		workers = 1
+	}
+	size := 2 * workers
+	if len(queueSize) > 0 && queueSize[0] >= 0 {
+		size = queueSize[0]
+	}
+
+	p := &WorkerPoolExecutor{tasks: make(chan func(), size)}
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer p.wg.Done()
+			for task := range p.tasks {
+				task()
+			}
+		}()
+	}
+	return p
+}
+
+// Execute queues a task. After Close, tasks run inline.
+func (p *WorkerPoolExecutor) Execute(task func()) {
+	p.mu.RLock()
+	if p.closed {
+		p.mu.RUnlock()
+		task()
+		return
+	}
+	p.tasks <- task
+	p.mu.RUnlock()
+}
+
+// Close stops the workers once the queued tasks have run
+func (p *WorkerPoolExecutor) Close() {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.tasks)
+	}
+	p.mu.Unlock()
+	p.wg.Wait()
+}
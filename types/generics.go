@@ -95,6 +95,14 @@ func (r Result[T, E]) Unwrap() T {
 	return r.value
 }
 
+// UnwrapErr returns the error (panics on success)
+func (r Result[T, E]) UnwrapErr() E {
+	if r.isOk {
+		panic(fmt.Sprintf("Result.UnwrapErr() called on value: %v", r.value))
+	}
+	return r.error
+}
+
 // UnwrapOr returns value or default on error
 func (r Result[T, E]) UnwrapOr(defaultValue T) T {
 	if r.isOk {
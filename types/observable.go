@@ -0,0 +1,450 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Observer receives the notifications of an Observable (like Observer in RxJS). Every
+// callback is optional. After Error or Complete no further notification is delivered.
+type Observer[T any] struct {
+	Next     func(value T)
+	Error    func(err error)
+	Complete func()
+}
+
+// Teardown releases the resources of a subscription (like TeardownLogic in RxJS)
+type Teardown func()
+
+// Subscriber is handed to the subscribe function of an Observable to push notifications to
+// one subscription (like Subscriber in RxJS). Notifications are delivered in order and never
+// concurrently, even when they are pushed from several goroutines; they are ignored once the
+// subscriber is closed by Error, Complete or Unsubscribe.
+type Subscriber[T any] interface {
+	Next(value T)
+	Error(err error)
+	Complete()
+	// Closed reports whether the subscriber no longer accepts notifications
+	Closed() bool
+	// Add registers a teardown that runs when the subscription ends, or immediately if it has
+	// already ended
+	Add(teardown Teardown)
+}
+
+// Observable represents TypeScript's Observable pattern (RxJS-like). Observables created with
+// NewObservableFrom are cold: each subscription runs the subscribe function. NewObservable
+// creates a hot observable whose Next, Error and Complete multicast to the current
+// subscribers; on cold observables those methods have no effect.
+type Observable[T any] struct {
+	subscribe func(subscriber Subscriber[T]) Teardown
+	hub       *multicast[T]
+}
+
+// NewObservableFrom creates a cold observable (like new Observable(subscribe) in RxJS). The
+// subscribe function runs once per subscription and returns the teardown that ends it, or
+// nil. A panic in subscribe is delivered as an error.
+func NewObservableFrom[T any](subscribe func(subscriber Subscriber[T]) Teardown) *Observable[T] {
+	return &Observable[T]{subscribe: subscribe}
+}
+
+// NewObservable creates a hot Observable that multicasts Next, Error and Complete to its
+// subscribers synchronously, in subscription order
+func NewObservable[T any]() *Observable[T] {
+	hub := &multicast[T]{}
+	return &Observable[T]{subscribe: hub.subscribe, hub: hub}
+}
+
+// Subscribe subscribes to the values of the observable (like observable.subscribe(next) in RxJS)
+func (obs *Observable[T]) Subscribe(observer EventListener[T]) *Subscription {
+	return obs.SubscribeObserver(Observer[T]{Next: observer})
+}
+
+// SubscribeObserver subscribes to values, errors and completion (like
+// observable.subscribe(observer) in RxJS)
+func (obs *Observable[T]) SubscribeObserver(observer Observer[T]) *Subscription {
+	return obs.subscribeWith(newSubscriber(observer))
+}
+
+// subscribeWith runs the subscribe function for a subscriber
+func (obs *Observable[T]) subscribeWith(s *subscriber[T]) *Subscription {
+	teardown, err := runSubscribe(obs.subscribe, Subscriber[T](s))
+	if err != nil {
+		s.Error(err)
+	}
+	s.Add(teardown)
+	return &Subscription{unsubscribe: s.unsubscribe, closed: s.Closed}
+}
+
+// subscribeTo subscribes an operator's observer to source as part of the parent subscription:
+// ending the parent unsubscribes from source, even while source is still emitting
+// synchronously, and ending the inner subscription releases it from the parent
+func subscribeTo[T, P any](source *Observable[T], parent Subscriber[P], observer Observer[T]) *Subscription {
+	s := newSubscriber(observer)
+	if owner, ok := parent.(*subscriber[P]); ok {
+		s.Add(owner.register(s.unsubscribe))
+	} else {
+		parent.Add(s.unsubscribe)
+	}
+	return source.subscribeWith(s)
+}
+
+// runSubscribe calls a subscribe function, converting a panic into an error
+func runSubscribe[T any](subscribe func(Subscriber[T]) Teardown, s Subscriber[T]) (teardown Teardown, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = NewError(fmt.Sprintf("observable subscribe panicked: %v", r), InternalError).WithData("panic", r)
+		}
+	}()
+	return subscribe(s), nil
+}
+
+// Next emits the next value to the subscribers of a hot observable
+func (obs *Observable[T]) Next(value T) {
+	if obs.hub != nil {
+		obs.hub.next(value)
+	}
+}
+
+// Error ends a hot observable with an error
+func (obs *Observable[T]) Error(err error) {
+	if obs.hub != nil {
+		obs.hub.stop(err, false)
+	}
+}
+
+// Complete ends a hot observable successfully
+func (obs *Observable[T]) Complete() {
+	if obs.hub != nil {
+		obs.hub.stop(nil, true)
+	}
+}
+
+// Subscription represents a subscription to an observable
+type Subscription struct {
+	unsubscribe func()
+	closed      func() bool
+}
+
+// Unsubscribe cancels the subscription and runs its teardown
+func (s *Subscription) Unsubscribe() {
+	if s.unsubscribe != nil {
+		s.unsubscribe()
+	}
+}
+
+// Closed reports whether the subscription has ended by unsubscribing, error or completion
+func (s *Subscription) Closed() bool {
+	if s.closed != nil {
+		return s.closed()
+	}
+	return false
+}
+
+// subscriber is the Subscriber of one subscription. Notifications are queued and delivered by
+// whichever goroutine is not already delivering, so they never overlap and keep their order.
+type subscriber[T any] struct {
+	observer     Observer[T]
+	mu           sync.Mutex
+	stopped      bool
+	unsubscribed bool
+	delivering   bool
+	queue        []func()
+	teardowns    map[uint64]Teardown
+	nextID       uint64
+}
+
+// newSubscriber creates a subscriber for an observer
+func newSubscriber[T any](observer Observer[T]) *subscriber[T] {
+	return &subscriber[T]{observer: observer}
+}
+
+// Next delivers a value
+func (s *subscriber[T]) Next(value T) {
+	s.deliver(s.enqueueNext(value))
+}
+
+// Error delivers an error and ends the subscription
+func (s *subscriber[T]) Error(err error) {
+	s.deliver(s.enqueueError(err))
+}
+
+// Complete delivers completion and ends the subscription
+func (s *subscriber[T]) Complete() {
+	s.deliver(s.enqueueComplete())
+}
+
+// enqueueNext queues a value without delivering it, so a caller holding its own lock can
+// order notifications and deliver them after unlocking
+func (s *subscriber[T]) enqueueNext(value T) bool {
+	return s.enqueue(false, func() {
+		if s.observer.Next != nil {
+			s.observer.Next(value)
+		}
+	})
+}
+
+// enqueueError queues an error without delivering it
+func (s *subscriber[T]) enqueueError(err error) bool {
+	return s.enqueue(true, func() {
+		if s.observer.Error != nil {
+			s.observer.Error(err)
+		} else {
+			fmt.Printf("Warning: unhandled observable error: %v\n", err)
+		}
+		s.unsubscribe()
+	})
+}
+
+// enqueueComplete queues completion without delivering it
+func (s *subscriber[T]) enqueueComplete() bool {
+	return s.enqueue(true, func() {
+		if s.observer.Complete != nil {
+			s.observer.Complete()
+		}
+		s.unsubscribe()
+	})
+}
+
+// enqueue queues a notification and reports whether it was accepted
+func (s *subscriber[T]) enqueue(terminal bool, notify func()) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stopped {
+		return false
+	}
+	if terminal {
+		s.stopped = true
+	}
+	s.queue = append(s.queue, notify)
+	return true
+}
+
+// deliver delivers the queued notifications unless another call is doing so
+func (s *subscriber[T]) deliver(queued bool) {
+	s.mu.Lock()
+	if !queued || s.delivering {
+		s.mu.Unlock()
+		return
+	}
+
+	s.delivering = true
+	for len(s.queue) > 0 && !s.unsubscribed {
+		next := s.queue[0]
+		s.queue = s.queue[1:]
+		s.mu.Unlock()
+		next()
+		s.mu.Lock()
+	}
+	s.queue = nil
+	s.delivering = false
+	s.mu.Unlock()
+}
+
+// Closed reports whether the subscriber no longer accepts notifications
+func (s *subscriber[T]) Closed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.stopped || s.unsubscribed
+}
+
+// Add registers a teardown
+func (s *subscriber[T]) Add(teardown Teardown) {
+	s.register(teardown)
+}
+
+// register adds a teardown and returns a function that removes it again without running it
+func (s *subscriber[T]) register(teardown Teardown) Teardown {
+	if teardown == nil {
+		return func() {}
+	}
+	s.mu.Lock()
+	if s.unsubscribed {
+		s.mu.Unlock()
+		teardown()
+		return func() {}
+	}
+	if s.teardowns == nil {
+		s.teardowns = make(map[uint64]Teardown)
+	}
+	s.nextID++
+	id := s.nextID
+	s.teardowns[id] = teardown
+	s.mu.Unlock()
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		delete(s.teardowns, id)
+	}
+}
+
+// unsubscribe ends the subscription and runs its teardowns once, newest first
+func (s *subscriber[T]) unsubscribe() {
+	s.mu.Lock()
+	if s.unsubscribed {
+		s.mu.Unlock()
+		return
+	}
+	s.unsubscribed = true
+	s.stopped = true
+	ids := make([]uint64, 0, len(s.teardowns))
+	for id := range s.teardowns {
+		ids = append(ids, id)
+	}
+	teardowns := s.teardowns
+	s.teardowns = nil
+	s.mu.Unlock()
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] > ids[j] })
+	for _, id := range ids {
+		teardowns[id]()
+	}
+}
+
+// multicast pushes notifications to every current subscriber of a hot observable. After Error
+// or Complete, late subscribers receive the same terminal notification. Notifications are
+// queued for all subscribers under one lock, so every subscriber sees the same order even
+// when Next is called concurrently.
+type multicast[T any] struct {
+	mu          sync.Mutex
+	subscribers []*subscriber[T]
+	stopped     bool
+	err         error
+	policy      multicastPolicy[T]
+}
+
+// multicastPolicy decides what a multicast stores and replays, for the Subject variants. Its
+// methods are called with the multicast's lock held.
+type multicastPolicy[T any] interface {
+	// record stores a value and reports whether it is delivered to the current subscribers
+	record(value T) bool
+	// replay returns the values a new subscriber receives before anything else
+	replay(stopped bool, err error) []T
+	// flush returns the values delivered to the current subscribers just before completion
+	flush() []T
+}
+
+// asSubscriber returns the internal subscriber behind s, wrapping other implementations
+func asSubscriber[T any](s Subscriber[T]) *subscriber[T] {
+	if sub, ok := s.(*subscriber[T]); ok {
+		return sub
+	}
+	sub := newSubscriber(Observer[T]{Next: s.Next, Error: s.Error, Complete: s.Complete})
+	s.Add(sub.unsubscribe)
+	return sub
+}
+
+// enqueueStop queues the terminal notification of a stopped multicast
+func enqueueStop[T any](s *subscriber[T], err error) bool {
+	if err != nil {
+		return s.enqueueError(err)
+	}
+	return s.enqueueComplete()
+}
+
+// subscribe adds a subscriber after queueing the values the policy replays
+func (m *multicast[T]) subscribe(subscriber Subscriber[T]) Teardown {
+	s := asSubscriber(subscriber)
+
+	m.mu.Lock()
+	queued := false
+	if m.policy != nil {
+		for _, value := range m.policy.replay(m.stopped, m.err) {
+			queued = s.enqueueNext(value) || queued
+		}
+	}
+	if m.stopped {
+		queued = enqueueStop(s, m.err) || queued
+		m.mu.Unlock()
+		s.deliver(queued)
+		return nil
+	}
+	m.subscribers = append(m.subscribers, s)
+	m.mu.Unlock()
+	s.deliver(queued)
+
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		for i, other := range m.subscribers {
+			if other == s {
+				m.subscribers = append(m.subscribers[:i:i], m.subscribers[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// next delivers a value to every current subscriber
+func (m *multicast[T]) next(value T) {
+	m.mu.Lock()
+	if m.stopped || (m.policy != nil && !m.policy.record(value)) {
+		m.mu.Unlock()
+		return
+	}
+	targets := append([]*subscriber[T](nil), m.subscribers...)
+	queued := make([]bool, len(targets))
+	for i, s := range targets {
+		queued[i] = s.enqueueNext(value)
+	}
+	m.mu.Unlock()
+
+	for i, s := range targets {
+		s.deliver(queued[i])
+	}
+}
+
+// stop ends the multicast with an error or completion
+func (m *multicast[T]) stop(err error, complete bool) {
+	m.mu.Lock()
+	if m.stopped {
+		m.mu.Unlock()
+		return
+	}
+	m.stopped = true
+	if !complete {
+		m.err = err
+		if m.err == nil {
+			m.err = NewError("observable error", InternalError)
+		}
+	}
+	var final []T
+	if complete && m.policy != nil {
+		final = m.policy.flush()
+	}
+	targets := m.subscribers
+	m.subscribers = nil
+	queued := make([]bool, len(targets))
+	for i, s := range targets {
+		for _, value := range final {
+			queued[i] = s.enqueueNext(value) || queued[i]
+		}
+		queued[i] = enqueueStop(s, m.err) || queued[i]
+	}
+	m.mu.Unlock()
+
+	for i, s := range targets {
+		s.deliver(queued[i])
+	}
+}
+
+// Subject represents a subject (both observable and observer): values passed to Next are
+// multicast to the current subscribers (like Subject in RxJS)
+type Subject[T any] struct {
+	*Observable[T]
+}
+
+// NewSubject creates a new Subject
+func NewSubject[T any]() *Subject[T] {
+	return &Subject[T]{Observable: NewObservable[T]()}
+}
+
+// AsObservable returns the subject as a plain Observable (like subject.asObservable() in RxJS)
+func (s *Subject[T]) AsObservable() *Observable[T] {
+	return NewObservableFrom(s.subscribe)
+}
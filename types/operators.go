@@ -0,0 +1,894 @@
+package types
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"typescript-golang/timers"
+)
+
+// Operator transforms an observable into another one (like OperatorFunction in RxJS)
+type Operator[T, R any] func(source *Observable[T]) *Observable[R]
+
+// Bind turns an operator function with one argument into an Operator for Pipe, e.g.
+// Bind(Take[int], 3) or Bind(Filter[int], isEven)
+func Bind[T, A, R any](operator func(source *Observable[T], arg A) *Observable[R], arg A) Operator[T, R] {
+	return func(source *Observable[T]) *Observable[R] {
+		return operator(source, arg)
+	}
+}
+
+// Pipe applies operators that keep the value type, in order (like observable.pipe() in RxJS)
+func (obs *Observable[T]) Pipe(operators ...Operator[T, T]) *Observable[T] {
+	result := obs
+	for _, operator := range operators {
+		result = operator(result)
+	}
+	return result
+}
+
+// Pipe2 applies two operators that may change the value type (like observable.pipe(a, b) in RxJS)
+func Pipe2[A, B, C any](source *Observable[A], op1 Operator[A, B], op2 Operator[B, C]) *Observable[C] {
+	return op2(op1(source))
+}
+
+// Pipe3 applies three operators that may change the value type
+func Pipe3[A, B, C, D any](source *Observable[A], op1 Operator[A, B], op2 Operator[B, C], op3 Operator[C, D]) *Observable[D] {
+	return op3(op2(op1(source)))
+}
+
+// Pipe4 applies four operators that may change the value type
+func Pipe4[A, B, C, D, E any](source *Observable[A], op1 Operator[A, B], op2 Operator[B, C], op3 Operator[C, D], op4 Operator[D, E]) *Observable[E] {
+	return op4(op3(op2(op1(source))))
+}
+
+// ObservableOf creates an observable that emits the values and completes (like of() in RxJS)
+func ObservableOf[T any](values ...T) *Observable[T] {
+	return NewObservableFrom(func(subscriber Subscriber[T]) Teardown {
+		for _, value := range values {
+			if subscriber.Closed() {
+				return nil
+			}
+			subscriber.Next(value)
+		}
+		subscriber.Complete()
+		return nil
+	})
+}
+
+// ThrowError creates an observable that errors immediately (like throwError() in RxJS)
+func ThrowError[T any](err error) *Observable[T] {
+	return NewObservableFrom(func(subscriber Subscriber[T]) Teardown {
+		subscriber.Error(err)
+		return nil
+	})
+}
+
+// Interval creates an observable that emits 0, 1, 2, ... every period (like interval() in RxJS).
+// An optional clock replaces the default one, e.g. a timers.FakeClock in tests.
+func Interval(period time.Duration, clock ...timers.Clock) *Observable[int] {
+	c := timers.Resolve(clock...)
+	return NewObservableFrom(func(subscriber Subscriber[int]) Teardown {
+		var mu sync.Mutex
+		var timer timers.Timer
+		var stopped bool
+		count := 0
+
+		var tick func()
+		tick = func() {
+			mu.Lock()
+			if stopped {
+				mu.Unlock()
+				return
+			}
+			value := count
+			count++
+			timer = c.AfterFunc(period, tick)
+			mu.Unlock()
+			subscriber.Next(value)
+		}
+
+		mu.Lock()
+		timer = c.AfterFunc(period, tick)
+		mu.Unlock()
+
+		return func() {
+			mu.Lock()
+			defer mu.Unlock()
+
+			stopped = true
+			timer.Stop()
+		}
+	})
+}
+
+// Merge emits the values of every source as they arrive and completes when all of them have
+// completed (like merge() in RxJS)
+func Merge[T any](sources ...*Observable[T]) *Observable[T] {
+	return NewObservableFrom(func(subscriber Subscriber[T]) Teardown {
+		var mu sync.Mutex
+		active := len(sources)
+		if active == 0 {
+			subscriber.Complete()
+			return nil
+		}
+
+		for _, source := range sources {
+			subscribeTo(source, subscriber, Observer[T]{
+				Next:  subscriber.Next,
+				Error: subscriber.Error,
+				Complete: func() {
+					mu.Lock()
+					active--
+					done := active == 0
+					mu.Unlock()
+					if done {
+						subscriber.Complete()
+					}
+				},
+			})
+		}
+		return nil
+	})
+}
+
+// Concat subscribes to the sources one after another (like concat() in RxJS)
+func Concat[T any](sources ...*Observable[T]) *Observable[T] {
+	return NewObservableFrom(func(subscriber Subscriber[T]) Teardown {
+		var subscribeNext func(index int)
+		subscribeNext = func(index int) {
+			if index == len(sources) {
+				subscriber.Complete()
+				return
+			}
+			subscribeTo(sources[index], subscriber, Observer[T]{
+				Next:     subscriber.Next,
+				Error:    subscriber.Error,
+				Complete: func() { subscribeNext(index + 1) },
+			})
+		}
+		subscribeNext(0)
+		return nil
+	})
+}
+
+// CombineLatest emits the latest value of every source whenever one of them emits, once all
+// of them have emitted (like combineLatest() in RxJS)
+func CombineLatest[T any](sources ...*Observable[T]) *Observable[[]T] {
+	return NewObservableFrom(func(subscriber Subscriber[[]T]) Teardown {
+		var mu sync.Mutex
+		latest := make([]T, len(sources))
+		seen := make([]bool, len(sources))
+		missing := len(sources)
+		active := len(sources)
+		if active == 0 {
+			subscriber.Complete()
+			return nil
+		}
+
+		for i, source := range sources {
+			i := i
+			subscribeTo(source, subscriber, Observer[T]{
+				Next: func(value T) {
+					mu.Lock()
+					if !seen[i] {
+						seen[i] = true
+						missing--
+					}
+					latest[i] = value
+					var values []T
+					if missing == 0 {
+						values = append([]T(nil), latest...)
+					}
+					mu.Unlock()
+					if values != nil {
+						subscriber.Next(values)
+					}
+				},
+				Error: subscriber.Error,
+				Complete: func() {
+					mu.Lock()
+					active--
+					// A source that completes without a value means nothing can be emitted
+					done := active == 0 || !seen[i]
+					mu.Unlock()
+					if done {
+						subscriber.Complete()
+					}
+				},
+			})
+		}
+		return nil
+	})
+}
+
+// Zip emits the n-th values of all sources together (like zip() in RxJS). It completes once a
+// completed source has no buffered values left.
+func Zip[T any](sources ...*Observable[T]) *Observable[[]T] {
+	return NewObservableFrom(func(subscriber Subscriber[[]T]) Teardown {
+		var mu sync.Mutex
+		buffers := make([][]T, len(sources))
+		completed := make([]bool, len(sources))
+		if len(sources) == 0 {
+			subscriber.Complete()
+			return nil
+		}
+
+		// exhausted reports whether a completed source has run out of values; mu must be held
+		exhausted := func() bool {
+			for i := range sources {
+				if completed[i] && len(buffers[i]) == 0 {
+					return true
+				}
+			}
+			return false
+		}
+
+		for i, source := range sources {
+			i := i
+			subscribeTo(source, subscriber, Observer[T]{
+				Next: func(value T) {
+					mu.Lock()
+					buffers[i] = append(buffers[i], value)
+					var values []T
+					ready := true
+					for _, buffer := range buffers {
+						if len(buffer) == 0 {
+							ready = false
+							break
+						}
+					}
+					if ready {
+						values = make([]T, len(buffers))
+						for j := range buffers {
+							values[j] = buffers[j][0]
+							buffers[j] = buffers[j][1:]
+						}
+					}
+					done := exhausted()
+					mu.Unlock()
+
+					if values != nil {
+						subscriber.Next(values)
+					}
+					if done {
+						subscriber.Complete()
+					}
+				},
+				Error: subscriber.Error,
+				Complete: func() {
+					mu.Lock()
+					completed[i] = true
+					done := exhausted()
+					mu.Unlock()
+					if done {
+						subscriber.Complete()
+					}
+				},
+			})
+		}
+		return nil
+	})
+}
+
+// WithLatestFrom combines every value of source with the latest value of other (like
+// withLatestFrom() in RxJS). Values arriving before other has emitted are dropped.
+func WithLatestFrom[T, U, R any](source *Observable[T], other *Observable[U], combine func(value T, latest U) R) *Observable[R] {
+	return NewObservableFrom(func(subscriber Subscriber[R]) Teardown {
+		var mu sync.Mutex
+		var latest U
+		var ready bool
+
+		subscribeTo(other, subscriber, Observer[U]{
+			Next: func(value U) {
+				mu.Lock()
+				latest, ready = value, true
+				mu.Unlock()
+			},
+			Error: subscriber.Error,
+		})
+		subscribeTo(source, subscriber, Observer[T]{
+			Next: func(value T) {
+				mu.Lock()
+				current, ok := latest, ready
+				mu.Unlock()
+				if ok {
+					subscriber.Next(combine(value, current))
+				}
+			},
+			Error:    subscriber.Error,
+			Complete: subscriber.Complete,
+		})
+		return nil
+	})
+}
+
+// flattenMode selects how the *Map operators handle a new inner observable
+type flattenMode int
+
+const (
+	// flattenMerge runs inner observables concurrently, queueing beyond the concurrency limit
+	flattenMerge flattenMode = iota
+	// flattenExhaust ignores source values while an inner observable is active
+	flattenExhaust
+	// flattenSwitch unsubscribes from the active inner observable
+	flattenSwitch
+)
+
+// flatten implements MergeMap, ConcatMap, ExhaustMap and SwitchMap
+func flatten[T, R any](source *Observable[T], project func(value T) *Observable[R], concurrency int, mode flattenMode) *Observable[R] {
+	return NewObservableFrom(func(subscriber Subscriber[R]) Teardown {
+		var (
+			mu         sync.Mutex
+			active     int
+			queued     []T
+			sourceDone bool
+			current    *Subscription
+			generation int
+		)
+
+		var subscribeInner func(value T, gen int)
+
+		// innerDone starts a queued value or completes once everything has finished
+		innerDone := func() {
+			mu.Lock()
+			active--
+			var next []T
+			if len(queued) > 0 {
+				next = queued[:1]
+				queued = queued[1:]
+				active++
+			}
+			done := sourceDone && active == 0
+			mu.Unlock()
+
+			if next != nil {
+				subscribeInner(next[0], 0)
+			} else if done {
+				subscriber.Complete()
+			}
+		}
+
+		subscribeInner = func(value T, gen int) {
+			// isCurrent drops notifications from inner observables that were switched away from
+			isCurrent := func() bool {
+				if mode != flattenSwitch {
+					return true
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				return gen == generation
+			}
+
+			inner, err := runProject(project, value)
+			if err != nil {
+				subscriber.Error(err)
+				return
+			}
+			subscription := subscribeTo(inner, subscriber, Observer[R]{
+				Next: func(value R) {
+					if isCurrent() {
+						subscriber.Next(value)
+					}
+				},
+				Error: func(err error) {
+					if isCurrent() {
+						subscriber.Error(err)
+					}
+				},
+				Complete: func() {
+					if isCurrent() {
+						innerDone()
+					}
+				},
+			})
+
+			if mode == flattenSwitch {
+				mu.Lock()
+				if gen == generation {
+					current = subscription
+				}
+				mu.Unlock()
+			}
+		}
+
+		subscribeTo(source, subscriber, Observer[T]{
+			Next: func(value T) {
+				mu.Lock()
+				switch mode {
+				case flattenSwitch:
+					previous := current
+					current = nil
+					generation++
+					gen := generation
+					if active == 0 {
+						active = 1
+					}
+					mu.Unlock()
+					if previous != nil {
+						previous.Unsubscribe()
+					}
+					subscribeInner(value, gen)
+				case flattenExhaust:
+					if active > 0 {
+						mu.Unlock()
+						return
+					}
+					active++
+					mu.Unlock()
+					subscribeInner(value, 0)
+				default:
+					if concurrency > 0 && active >= concurrency {
+						queued = append(queued, value)
+						mu.Unlock()
+						return
+					}
+					active++
+					mu.Unlock()
+					subscribeInner(value, 0)
+				}
+			},
+			Error: subscriber.Error,
+			Complete: func() {
+				mu.Lock()
+				sourceDone = true
+				done := active == 0 && len(queued) == 0
+				mu.Unlock()
+				if done {
+					subscriber.Complete()
+				}
+			},
+		})
+		return nil
+	})
+}
+
+// runProject calls the project function of a flattening operator, converting a panic into an
+// error
+func runProject[T, R any](project func(value T) *Observable[R], value T) (inner *Observable[R], err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = NewError(fmt.Sprintf("project function panicked: %v", r), InternalError).WithData("panic", r)
+		}
+	}()
+	return project(value), nil
+}
+
+// MergeMap maps every value to an inner observable and merges their values (like mergeMap()
+// in RxJS). An optional concurrency limits the active inner observables; further values wait.
+func MergeMap[T, R any](source *Observable[T], project func(value T) *Observable[R], concurrency ...int) *Observable[R] {
+	limit := 0
+	if len(concurrency) > 0 {
+		limit = concurrency[0]
+	}
+	return flatten(source, project, limit, flattenMerge)
+}
+
+// ConcatMap maps every value to an inner observable and subscribes to them one at a time, in
+// order (like concatMap() in RxJS)
+func ConcatMap[T, R any](source *Observable[T], project func(value T) *Observable[R]) *Observable[R] {
+	return flatten(source, project, 1, flattenMerge)
+}
+
+// ExhaustMap maps a value to an inner observable and ignores values until it completes (like
+// exhaustMap() in RxJS)
+func ExhaustMap[T, R any](source *Observable[T], project func(value T) *Observable[R]) *Observable[R] {
+	return flatten(source, project, 1, flattenExhaust)
+}
+
+// SwitchMap maps every value to an inner observable, unsubscribing from the previous one
+// (like switchMap() in RxJS)
+func SwitchMap[T, R any](source *Observable[T], project func(value T) *Observable[R]) *Observable[R] {
+	return flatten(source, project, 1, flattenSwitch)
+}
+
+// Scan emits every intermediate result of the accumulator (like scan() in RxJS)
+func Scan[T, A any](source *Observable[T], accumulator func(acc A, value T) A, seed A) *Observable[A] {
+	return NewObservableFrom(func(subscriber Subscriber[A]) Teardown {
+		var mu sync.Mutex
+		acc := seed
+
+		subscribeTo(source, subscriber, Observer[T]{
+			Next: func(value T) {
+				mu.Lock()
+				acc = accumulator(acc, value)
+				result := acc
+				mu.Unlock()
+				subscriber.Next(result)
+			},
+			Error:    subscriber.Error,
+			Complete: subscriber.Complete,
+		})
+		return nil
+	})
+}
+
+// Reduce emits the final result of the accumulator when the source completes (like reduce()
+// in RxJS). An empty source emits the seed.
+func Reduce[T, A any](source *Observable[T], accumulator func(acc A, value T) A, seed A) *Observable[A] {
+	return NewObservableFrom(func(subscriber Subscriber[A]) Teardown {
+		var mu sync.Mutex
+		acc := seed
+
+		subscribeTo(source, subscriber, Observer[T]{
+			Next: func(value T) {
+				mu.Lock()
+				acc = accumulator(acc, value)
+				mu.Unlock()
+			},
+			Error: subscriber.Error,
+			Complete: func() {
+				mu.Lock()
+				result := acc
+				mu.Unlock()
+				subscriber.Next(result)
+				subscriber.Complete()
+			},
+		})
+		return nil
+	})
+}
+
+// BufferTime collects values and emits them every span, including empty buffers (like
+// bufferTime() in RxJS). The last buffer is emitted when the source completes.
+// An optional clock replaces the default one, e.g. a timers.FakeClock in tests.
+func BufferTime[T any](source *Observable[T], span time.Duration, clock ...timers.Clock) *Observable[[]T] {
+	c := timers.Resolve(clock...)
+	return NewObservableFrom(func(subscriber Subscriber[[]T]) Teardown {
+		var mu sync.Mutex
+		var timer timers.Timer
+		var stopped bool
+		buffer := []T{}
+
+		// take returns the current buffer and starts a new one
+		take := func() []T {
+			mu.Lock()
+			defer mu.Unlock()
+
+			values := buffer
+			buffer = []T{}
+			return values
+		}
+
+		var tick func()
+		tick = func() {
+			mu.Lock()
+			if stopped {
+				mu.Unlock()
+				return
+			}
+			timer = c.AfterFunc(span, tick)
+			mu.Unlock()
+			subscriber.Next(take())
+		}
+		mu.Lock()
+		timer = c.AfterFunc(span, tick)
+		mu.Unlock()
+
+		subscribeTo(source, subscriber, Observer[T]{
+			Next: func(value T) {
+				mu.Lock()
+				buffer = append(buffer, value)
+				mu.Unlock()
+			},
+			Error: subscriber.Error,
+			Complete: func() {
+				subscriber.Next(take())
+				subscriber.Complete()
+			},
+		})
+
+		return func() {
+			mu.Lock()
+			defer mu.Unlock()
+
+			stopped = true
+			timer.Stop()
+		}
+	})
+}
+
+// BufferCount emits values in buffers of size (like bufferCount() in RxJS). A new buffer
+// starts every `every` values (default size); remaining buffers are emitted on completion.
+func BufferCount[T any](source *Observable[T], size int, every ...int) *Observable[[]T] {
+	if size < 1 {
+		size = 1
+	}
+	startEvery := size
+	if len(every) > 0 && every[0] > 0 {
+		startEvery = every[0]
+	}
+
+	return NewObservableFrom(func(subscriber Subscriber[[]T]) Teardown {
+		var mu sync.Mutex
+		var buffers [][]T
+		count := 0
+
+		subscribeTo(source, subscriber, Observer[T]{
+			Next: func(value T) {
+				mu.Lock()
+				if count%startEvery == 0 {
+					buffers = append(buffers, nil)
+				}
+				count++
+				var full [][]T
+				open := buffers[:0]
+				for _, buffer := range buffers {
+					buffer = append(buffer, value)
+					if len(buffer) == size {
+						full = append(full, buffer)
+					} else {
+						open = append(open, buffer)
+					}
+				}
+				buffers = open
+				mu.Unlock()
+
+				for _, buffer := range full {
+					subscriber.Next(buffer)
+				}
+			},
+			Error: subscriber.Error,
+			Complete: func() {
+				mu.Lock()
+				remaining := buffers
+				buffers = nil
+				mu.Unlock()
+
+				for _, buffer := range remaining {
+					if len(buffer) > 0 {
+						subscriber.Next(buffer)
+					}
+				}
+				subscriber.Complete()
+			},
+		})
+		return nil
+	})
+}
+
+// WindowTime splits the source into a new window observable every span (like windowTime() in
+// RxJS). Windows are hot: subscribe to them when they are emitted.
+// An optional clock replaces the default one, e.g. a timers.FakeClock in tests.
+func WindowTime[T any](source *Observable[T], span time.Duration, clock ...timers.Clock) *Observable[*Observable[T]] {
+	c := timers.Resolve(clock...)
+	return NewObservableFrom(func(subscriber Subscriber[*Observable[T]]) Teardown {
+		var mu sync.Mutex
+		var timer timers.Timer
+		var stopped bool
+		window := NewSubject[T]()
+		subscriber.Next(window.AsObservable())
+
+		var tick func()
+		tick = func() {
+			next := NewSubject[T]()
+			mu.Lock()
+			if stopped {
+				mu.Unlock()
+				return
+			}
+			previous := window
+			window = next
+			timer = c.AfterFunc(span, tick)
+			mu.Unlock()
+
+			previous.Complete()
+			subscriber.Next(next.AsObservable())
+		}
+		mu.Lock()
+		timer = c.AfterFunc(span, tick)
+		mu.Unlock()
+
+		// currentWindow returns the open window
+		currentWindow := func() *Subject[T] {
+			mu.Lock()
+			defer mu.Unlock()
+			return window
+		}
+
+		subscribeTo(source, subscriber, Observer[T]{
+			Next: func(value T) {
+				currentWindow().Next(value)
+			},
+			Error: func(err error) {
+				currentWindow().Error(err)
+				subscriber.Error(err)
+			},
+			Complete: func() {
+				currentWindow().Complete()
+				subscriber.Complete()
+			},
+		})
+
+		return func() {
+			mu.Lock()
+			defer mu.Unlock()
+
+			stopped = true
+			timer.Stop()
+		}
+	})
+}
+
+// DistinctUntilChanged drops values equal to the previous one (like distinctUntilChanged() in
+// RxJS). Values are compared with reflect.DeepEqual unless an equal function is given.
+func DistinctUntilChanged[T any](source *Observable[T], equal ...func(previous, current T) bool) *Observable[T] {
+	same := func(previous, current T) bool {
+		return reflect.DeepEqual(previous, current)
+	}
+	if len(equal) > 0 && equal[0] != nil {
+		same = equal[0]
+	}
+
+	return NewObservableFrom(func(subscriber Subscriber[T]) Teardown {
+		var mu sync.Mutex
+		var previous T
+		first := true
+
+		subscribeTo(source, subscriber, Observer[T]{
+			Next: func(value T) {
+				mu.Lock()
+				emit := first || !same(previous, value)
+				first = false
+				previous = value
+				mu.Unlock()
+				if emit {
+					subscriber.Next(value)
+				}
+			},
+			Error:    subscriber.Error,
+			Complete: subscriber.Complete,
+		})
+		return nil
+	})
+}
+
+// Take emits the first count values and completes (like take() in RxJS)
+func Take[T any](source *Observable[T], count int) *Observable[T] {
+	return NewObservableFrom(func(subscriber Subscriber[T]) Teardown {
+		if count <= 0 {
+			subscriber.Complete()
+			return nil
+		}
+
+		var mu sync.Mutex
+		taken := 0
+		subscribeTo(source, subscriber, Observer[T]{
+			Next: func(value T) {
+				mu.Lock()
+				taken++
+				n := taken
+				mu.Unlock()
+				if n <= count {
+					subscriber.Next(value)
+				}
+				if n == count {
+					subscriber.Complete()
+				}
+			},
+			Error:    subscriber.Error,
+			Complete: subscriber.Complete,
+		})
+		return nil
+	})
+}
+
+// TakeUntil emits values until notifier emits (like takeUntil() in RxJS)
+func TakeUntil[T, N any](source *Observable[T], notifier *Observable[N]) *Observable[T] {
+	return NewObservableFrom(func(subscriber Subscriber[T]) Teardown {
+		subscribeTo(notifier, subscriber, Observer[N]{
+			Next:  func(N) { subscriber.Complete() },
+			Error: subscriber.Error,
+		})
+		if subscriber.Closed() {
+			return nil
+		}
+		subscribeTo(source, subscriber, Observer[T]{
+			Next:     subscriber.Next,
+			Error:    subscriber.Error,
+			Complete: subscriber.Complete,
+		})
+		return nil
+	})
+}
+
+// Skip drops the first count values (like skip() in RxJS)
+func Skip[T any](source *Observable[T], count int) *Observable[T] {
+	return NewObservableFrom(func(subscriber Subscriber[T]) Teardown {
+		var mu sync.Mutex
+		skipped := 0
+
+		subscribeTo(source, subscriber, Observer[T]{
+			Next: func(value T) {
+				mu.Lock()
+				skip := skipped < count
+				if skip {
+					skipped++
+				}
+				mu.Unlock()
+				if !skip {
+					subscriber.Next(value)
+				}
+			},
+			Error:    subscriber.Error,
+			Complete: subscriber.Complete,
+		})
+		return nil
+	})
+}
+
+// StartWith emits the values before the source's values (like startWith() in RxJS)
+func StartWith[T any](source *Observable[T], values ...T) *Observable[T] {
+	return Concat(ObservableOf(values...), source)
+}
+
+// CatchError replaces an erroring source with the observable returned by handler (like
+// catchError() in RxJS). Returning caught resubscribes to the source.
+func CatchError[T any](source *Observable[T], handler func(err error, caught *Observable[T]) *Observable[T]) *Observable[T] {
+	var caught *Observable[T]
+	caught = NewObservableFrom(func(subscriber Subscriber[T]) Teardown {
+		subscribeTo(source, subscriber, Observer[T]{
+			Next: subscriber.Next,
+			Error: func(err error) {
+				subscribeTo(handler(err, caught), subscriber, Observer[T]{
+					Next:     subscriber.Next,
+					Error:    subscriber.Error,
+					Complete: subscriber.Complete,
+				})
+			},
+			Complete: subscriber.Complete,
+		})
+		return nil
+	})
+	return caught
+}
+
+// RetryWhen resubscribes to the source when the notifier emits after an error (like
+// retryWhen() in RxJS). The notifier receives the source's errors; when it completes or
+// errors, so does the result.
+func RetryWhen[T, N any](source *Observable[T], notifier func(errors *Observable[error]) *Observable[N]) *Observable[T] {
+	return NewObservableFrom(func(subscriber Subscriber[T]) Teardown {
+		errors := NewSubject[error]()
+		var subscribeSource func()
+
+		subscribeTo(notifier(errors.AsObservable()), subscriber, Observer[N]{
+			Next:     func(N) { subscribeSource() },
+			Error:    subscriber.Error,
+			Complete: subscriber.Complete,
+		})
+
+		subscribeSource = func() {
+			subscribeTo(source, subscriber, Observer[T]{
+				Next:     subscriber.Next,
+				Error:    errors.Next,
+				Complete: subscriber.Complete,
+			})
+		}
+		if !subscriber.Closed() {
+			subscribeSource()
+		}
+		return nil
+	})
+}
+
+// ShareReplay shares one subscription to the source between all subscribers and replays the
+// last bufferSize values to late subscribers (like shareReplay() in RxJS); zero or negative
+// replays every value. The source stays subscribed once connected.
+func ShareReplay[T any](source *Observable[T], bufferSize int) *Observable[T] {
+	subject := NewReplaySubject[T](bufferSize, 0)
+	var connect sync.Once
+
+	return NewObservableFrom(func(subscriber Subscriber[T]) Teardown {
+		teardown := subject.subscribe(subscriber)
+		connect.Do(func() {
+			source.SubscribeObserver(Observer[T]{
+				Next:     subject.Next,
+				Error:    subject.Error,
+				Complete: subject.Complete,
+			})
+		})
+		return teardown
+	})
+}
@@ -0,0 +1,146 @@
+package types_test
+
+import (
+	"testing"
+
+	marbles "typescript-golang/testing"
+	"typescript-golang/types"
+)
+
+// expectMarbles flushes the scheduler and fails the test unless the recording matches
+func expectMarbles[T any](t *testing.T, recording *marbles.MarbleRecording[T], expected string) {
+	t.Helper()
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatal(r)
+		}
+	}()
+	recording.ToBe(expected)
+}
+
+func TestSwitchMapMarbles(t *testing.T) {
+	s := marbles.NewMarbleScheduler()
+	source := marbles.Cold[string](s, "-a---e---|")
+	inner := map[string]string{"a": "-b-c-d|", "e": "-f-g|"}
+
+	result := types.SwitchMap(source, func(value string) *types.Observable[string] {
+		return marbles.Cold[string](s, inner[value])
+	})
+	expectMarbles(t, marbles.Record(s, result), "--b-c-f-g|")
+}
+
+func TestMergeMapConcurrencyMarbles(t *testing.T) {
+	s := marbles.NewMarbleScheduler()
+	source := marbles.Cold[string](s, "-a-b-c|")
+
+	result := types.MergeMap(source, func(value string) *types.Observable[string] {
+		return marbles.Cold(s, "--v---|", map[string]string{"v": value})
+	}, 2)
+	// c waits for a to complete at frame 7 before it is subscribed
+	expectMarbles(t, marbles.Record(s, result), "---a-b---c---|")
+}
+
+func TestExhaustMapMarbles(t *testing.T) {
+	s := marbles.NewMarbleScheduler()
+	source := marbles.Cold[string](s, "-a-b---c|")
+
+	result := types.ExhaustMap(source, func(value string) *types.Observable[string] {
+		return marbles.Cold(s, "--v-|", map[string]string{"v": value})
+	})
+	expectMarbles(t, marbles.Record(s, result), "---a-----c-|")
+}
+
+func TestBufferTimeMarbles(t *testing.T) {
+	s := marbles.NewMarbleScheduler()
+	source := marbles.Cold[string](s, "-ab-c--d|")
+
+	result := types.BufferTime(source, s.Frames(3), s.Clock)
+	buffers := map[string][]string{"x": {"a", "b"}, "y": {"c"}, "z": {"d"}}
+	expectMarbles(t, marbles.Record(s, result, buffers), "---x--y-(z|)")
+}
+
+func TestWindowTimeMarbles(t *testing.T) {
+	s := marbles.NewMarbleScheduler()
+	source := marbles.Cold[string](s, "-ab-c--d|")
+
+	windows := types.WindowTime(source, s.Frames(3), s.Clock)
+	result := types.MergeMap(windows, func(window *types.Observable[string]) *types.Observable[string] {
+		return types.Reduce(window, func(acc, value string) string { return acc + value }, "")
+	})
+	joined := map[string]string{"x": "ab", "y": "c", "z": "d"}
+	expectMarbles(t, marbles.Record(s, result, joined), "---x--y-(z|)")
+}
+
+func TestCombineLatestMarbles(t *testing.T) {
+	s := marbles.NewMarbleScheduler()
+	first := marbles.Cold[string](s, "-a---c|")
+	second := marbles.Cold[string](s, "--b-d---|")
+
+	result := types.CombineLatest(first, second)
+	pairs := map[string][]string{"x": {"a", "b"}, "y": {"a", "d"}, "z": {"c", "d"}}
+	expectMarbles(t, marbles.Record(s, result, pairs), "--x-yz--|")
+}
+
+func TestZipMarbles(t *testing.T) {
+	s := marbles.NewMarbleScheduler()
+	first := marbles.Cold[string](s, "-a-c-|")
+	second := marbles.Cold[string](s, "--b---d-e|")
+
+	result := types.Zip(first, second)
+	pairs := map[string][]string{"x": {"a", "b"}, "y": {"c", "d"}}
+	// first has completed and has no values left once c is paired
+	expectMarbles(t, marbles.Record(s, result, pairs), "--x---(y|)")
+}
+
+func TestRetryWhenMarbles(t *testing.T) {
+	s := marbles.NewMarbleScheduler()
+	source := marbles.Cold[string](s, "-a-#")
+
+	result := types.RetryWhen(source, func(errors *types.Observable[error]) *types.Observable[string] {
+		delayed := types.MergeMap(errors, func(error) *types.Observable[string] {
+			return marbles.Cold[string](s, "--r|")
+		})
+		return types.Take(delayed, 2)
+	})
+	expectMarbles(t, marbles.Record(s, result), "-a----a---|")
+}
+
+func TestShareReplayMarbles(t *testing.T) {
+	s := marbles.NewMarbleScheduler()
+	subscriptions := 0
+	cold := marbles.Cold[string](s, "-a-b-c|")
+	source := types.NewObservableFrom(func(subscriber types.Subscriber[string]) types.Teardown {
+		subscriptions++
+		subscription := cold.SubscribeObserver(types.Observer[string]{
+			Next:     subscriber.Next,
+			Error:    subscriber.Error,
+			Complete: subscriber.Complete,
+		})
+		return subscription.Unsubscribe
+	})
+	shared := types.ShareReplay(source, 2)
+
+	first := marbles.Record(s, shared)
+	var late *marbles.MarbleRecording[string]
+	s.Clock.AfterFunc(s.Frames(4), func() {
+		late = marbles.Record(s, shared)
+	})
+	expectMarbles(t, first, "-a-b-c|")
+	expectMarbles(t, late, "----(ab)c|")
+	if subscriptions != 1 {
+		t.Fatalf("source subscribed %d times, want 1", subscriptions)
+	}
+}
+
+func TestFlattenProjectPanicErrors(t *testing.T) {
+	var err error
+	result := types.MergeMap(types.ObservableOf(1), func(int) *types.Observable[int] {
+		panic("boom")
+	})
+	result.SubscribeObserver(types.Observer[int]{
+		Error: func(e error) { err = e },
+	})
+	if !types.IsErrorCode(err, types.InternalError) {
+		t.Fatalf("expected an InternalError, got %v", err)
+	}
+}
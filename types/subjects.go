@@ -0,0 +1,151 @@
+package types
+
+import (
+	"time"
+
+	"typescript-golang/timers"
+)
+
+// newPolicySubject creates a Subject whose multicast stores and replays values by policy
+func newPolicySubject[T any](policy multicastPolicy[T]) *Subject[T] {
+	hub := &multicast[T]{policy: policy}
+	return &Subject[T]{Observable: &Observable[T]{subscribe: hub.subscribe, hub: hub}}
+}
+
+// BehaviorSubject is a Subject with a current value that new subscribers receive first (like
+// BehaviorSubject in RxJS)
+type BehaviorSubject[T any] struct {
+	*Subject[T]
+	state *behaviorPolicy[T]
+}
+
+// behaviorPolicy keeps the current value of a BehaviorSubject
+type behaviorPolicy[T any] struct {
+	value T
+}
+
+func (p *behaviorPolicy[T]) record(value T) bool {
+	p.value = value
+	return true
+}
+
+func (p *behaviorPolicy[T]) replay(stopped bool, err error) []T {
+	if stopped {
+		return nil
+	}
+	return []T{p.value}
+}
+
+func (p *behaviorPolicy[T]) flush() []T {
+	return nil
+}
+
+// NewBehaviorSubject creates a BehaviorSubject with an initial value
+func NewBehaviorSubject[T any](initial T) *BehaviorSubject[T] {
+	state := &behaviorPolicy[T]{value: initial}
+	return &BehaviorSubject[T]{Subject: newPolicySubject[T](state), state: state}
+}
+
+// GetValue returns the current value (like behaviorSubject.getValue() in RxJS)
+func (s *BehaviorSubject[T]) GetValue() T {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	return s.state.value
+}
+
+// ReplaySubject is a Subject that replays buffered values to new subscribers, even after it has
+// ended (like ReplaySubject in RxJS)
+type ReplaySubject[T any] struct {
+	*Subject[T]
+}
+
+// replayPolicy buffers the values of a ReplaySubject
+type replayPolicy[T any] struct {
+	bufferSize int
+	windowTime time.Duration
+	clock      timers.Clock
+	values     []T
+	times      []time.Time
+}
+
+func (p *replayPolicy[T]) record(value T) bool {
+	p.values = append(p.values, value)
+	if p.windowTime > 0 {
+		p.times = append(p.times, p.clock.Now())
+	}
+	p.trim()
+	return true
+}
+
+func (p *replayPolicy[T]) replay(stopped bool, err error) []T {
+	p.trim()
+	return append([]T(nil), p.values...)
+}
+
+func (p *replayPolicy[T]) flush() []T {
+	return nil
+}
+
+// trim drops values beyond the buffer size or older than the window time
+func (p *replayPolicy[T]) trim() {
+	drop := 0
+	if p.bufferSize > 0 && len(p.values) > p.bufferSize {
+		drop = len(p.values) - p.bufferSize
+	}
+	if p.windowTime > 0 {
+		now := p.clock.Now()
+		for drop < len(p.times) && now.Sub(p.times[drop]) > p.windowTime {
+			drop++
+		}
+		p.times = p.times[drop:]
+	}
+	p.values = p.values[drop:]
+}
+
+// NewReplaySubject creates a ReplaySubject that replays up to bufferSize values no older than
+// windowTime; zero or negative values mean no limit. An optional clock replaces the default one
+// for the window time, e.g. a timers.FakeClock in tests.
+func NewReplaySubject[T any](bufferSize int, windowTime time.Duration, clock ...timers.Clock) *ReplaySubject[T] {
+	return &ReplaySubject[T]{Subject: newPolicySubject[T](&replayPolicy[T]{
+		bufferSize: bufferSize,
+		windowTime: windowTime,
+		clock:      timers.Resolve(clock...),
+	})}
+}
+
+// AsyncSubject is a Subject that only emits its last value, when it completes (like
+// AsyncSubject in RxJS). Late subscribers receive the same value and completion.
+type AsyncSubject[T any] struct {
+	*Subject[T]
+}
+
+// asyncPolicy keeps the last value of an AsyncSubject
+type asyncPolicy[T any] struct {
+	last     T
+	hasValue bool
+}
+
+func (p *asyncPolicy[T]) record(value T) bool {
+	p.last, p.hasValue = value, true
+	return false
+}
+
+func (p *asyncPolicy[T]) replay(stopped bool, err error) []T {
+	if stopped && err == nil && p.hasValue {
+		return []T{p.last}
+	}
+	return nil
+}
+
+func (p *asyncPolicy[T]) flush() []T {
+	if p.hasValue {
+		return []T{p.last}
+	}
+	return nil
+}
+
+// NewAsyncSubject creates an AsyncSubject
+func NewAsyncSubject[T any]() *AsyncSubject[T] {
+	return &AsyncSubject[T]{Subject: newPolicySubject[T](&asyncPolicy[T]{})}
+}
@@ -0,0 +1,174 @@
+package types
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"typescript-golang/timers"
+)
+
+// collect subscribes to an observable and returns the values it received so far
+func collect[T any](obs *Observable[T]) func() []T {
+	var mu sync.Mutex
+	var values []T
+	obs.Subscribe(func(value T) {
+		mu.Lock()
+		values = append(values, value)
+		mu.Unlock()
+	})
+	return func() []T {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]T(nil), values...)
+	}
+}
+
+func TestBehaviorSubjectLateSubscriberGetsCurrentValue(t *testing.T) {
+	s := NewBehaviorSubject(0)
+	early := collect(s.Observable)
+	s.Next(1)
+	s.Next(2)
+
+	late := collect(s.Observable)
+	s.Next(3)
+	if got := early(); !reflect.DeepEqual(got, []int{0, 1, 2, 3}) {
+		t.Fatalf("early subscriber got %v", got)
+	}
+	if got := late(); !reflect.DeepEqual(got, []int{2, 3}) {
+		t.Fatalf("late subscriber got %v, want the current value first", got)
+	}
+	if s.GetValue() != 3 {
+		t.Fatalf("GetValue() = %d, want 3", s.GetValue())
+	}
+
+	// After completion nothing is replayed
+	s.Complete()
+	if got := collect(s.Observable)(); len(got) != 0 {
+		t.Fatalf("subscriber after completion got %v", got)
+	}
+}
+
+func TestReplaySubjectBufferSize(t *testing.T) {
+	s := NewReplaySubject[int](2, 0)
+	for i := 1; i <= 4; i++ {
+		s.Next(i)
+	}
+	if got := collect(s.Observable)(); !reflect.DeepEqual(got, []int{3, 4}) {
+		t.Fatalf("replayed %v, want the last 2 values", got)
+	}
+
+	// Values are still replayed after completion
+	s.Complete()
+	if got := collect(s.Observable)(); !reflect.DeepEqual(got, []int{3, 4}) {
+		t.Fatalf("replayed %v after completion", got)
+	}
+}
+
+func TestReplaySubjectWindowTime(t *testing.T) {
+	clock := timers.NewFakeClock()
+	s := NewReplaySubject[string](0, time.Second, clock)
+	s.Next("a")
+	clock.Advance(500 * time.Millisecond)
+	s.Next("b")
+	clock.Advance(500 * time.Millisecond)
+	s.Next("c")
+
+	// "a" is exactly one window old and is still replayed
+	if got := collect(s.Observable)(); !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Fatalf("replayed %v, want a b c", got)
+	}
+	clock.Advance(time.Millisecond)
+	if got := collect(s.Observable)(); !reflect.DeepEqual(got, []string{"b", "c"}) {
+		t.Fatalf("replayed %v, want b c", got)
+	}
+	clock.Advance(time.Second)
+	if got := collect(s.Observable)(); len(got) != 0 {
+		t.Fatalf("replayed %v after the window", got)
+	}
+}
+
+func TestReplaySubjectBufferAndWindow(t *testing.T) {
+	clock := timers.NewFakeClock()
+	s := NewReplaySubject[int](2, time.Second, clock)
+	s.Next(1)
+	s.Next(2)
+	clock.Advance(800 * time.Millisecond)
+	s.Next(3)
+	clock.Advance(300 * time.Millisecond)
+	// 1 is beyond the buffer and 2 is outside the window
+	if got := collect(s.Observable)(); !reflect.DeepEqual(got, []int{3}) {
+		t.Fatalf("replayed %v, want [3]", got)
+	}
+}
+
+func TestAsyncSubjectEmitsOnlyOnComplete(t *testing.T) {
+	s := NewAsyncSubject[int]()
+	early := collect(s.Observable)
+	s.Next(1)
+	s.Next(2)
+	if got := early(); len(got) != 0 {
+		t.Fatalf("emitted %v before completion", got)
+	}
+
+	completed := false
+	s.SubscribeObserver(Observer[int]{Complete: func() { completed = true }})
+	s.Complete()
+	if got := early(); !reflect.DeepEqual(got, []int{2}) || !completed {
+		t.Fatalf("emitted %v, completed = %v, want the last value then completion", got, completed)
+	}
+	if got := collect(s.Observable)(); !reflect.DeepEqual(got, []int{2}) {
+		t.Fatalf("late subscriber got %v, want [2]", got)
+	}
+
+	// An error discards the last value
+	failed := NewAsyncSubject[int]()
+	failed.Next(1)
+	failed.Error(NewError("failed"))
+	var values []int
+	var err error
+	failed.SubscribeObserver(Observer[int]{
+		Next:  func(value int) { values = append(values, value) },
+		Error: func(e error) { err = e },
+	})
+	if len(values) != 0 || err == nil {
+		t.Fatalf("got %v, %v after an error, want only the error", values, err)
+	}
+}
+
+func TestSubjectsConcurrentNext(t *testing.T) {
+	const workers, values = 8, 200
+	behavior := NewBehaviorSubject(-1)
+	replay := NewReplaySubject[int](workers*values, 0)
+	first, second := collect(replay.Observable), collect(replay.Observable)
+	current := collect(behavior.Observable)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < values; i++ {
+				replay.Next(w*values + i)
+				behavior.Next(w*values + i)
+				behavior.GetValue()
+			}
+		}(w)
+	}
+	// Subscribe while values are being pushed
+	late := make(chan func() []int, 1)
+	go func() { late <- collect(replay.Observable) }()
+	wg.Wait()
+
+	got := first()
+	if len(got) != workers*values || !reflect.DeepEqual(got, second()) {
+		t.Fatalf("subscribers got %d and %d values in different orders", len(got), len(second()))
+	}
+	if replayed := (<-late)(); !reflect.DeepEqual(replayed, got) {
+		t.Fatalf("a subscriber joining mid-stream got %d values, want all %d in order", len(replayed), len(got))
+	}
+	if seen := current(); seen[len(seen)-1] != behavior.GetValue() {
+		t.Fatalf("last value %d, current value %d", seen[len(seen)-1], behavior.GetValue())
+	}
+}
@@ -0,0 +1,231 @@
+package types
+
+import "sync"
+
+// Weak references are built on the weak package and runtime.AddCleanup with Go 1.24 and later,
+// and on runtime.SetFinalizer before. The finalizer implementation keeps WeakRef targets alive,
+// since only the weak package can dereference a weak pointer safely, and keys and targets must
+// not have finalizers of their own. In both, a target must be the start of a heap allocation
+// and should not be a tiny pointer-free object (under 16 bytes), which the runtime may batch
+// with others and collect late.
+
+// WeakRef holds a reference that does not keep its target alive (like WeakRef in TypeScript)
+type WeakRef[T any] struct {
+	target weakValue[T]
+}
+
+// NewWeakRef creates a weak reference to target (like new WeakRef(target) in TypeScript)
+func NewWeakRef[T any](target *T) *WeakRef[T] {
+	return &WeakRef[T]{target: makeWeakValue(target)}
+}
+
+// Deref returns the target, or nil once it has been garbage collected (like weakRef.deref()
+// in TypeScript)
+func (r *WeakRef[T]) Deref() *T {
+	return r.target.value()
+}
+
+// weakEntry is a WeakMap value with the function that cancels its removal
+type weakEntry[V any] struct {
+	value V
+	stop  func()
+}
+
+// WeakMap maps objects to values without keeping the objects alive (like WeakMap in
+// TypeScript). An entry is removed after its key is garbage collected. Values must not
+// reference their keys, or the keys are never collected.
+type WeakMap[K, V any] struct {
+	mu      sync.Mutex
+	entries map[weakHandle[K]]*weakEntry[V]
+}
+
+// NewWeakMap creates a new WeakMap (like new WeakMap() in TypeScript)
+func NewWeakMap[K, V any]() *WeakMap[K, V] {
+	return &WeakMap[K, V]{
+		entries: make(map[weakHandle[K]]*weakEntry[V]),
+	}
+}
+
+// Set sets a value for an object key (like weakMap.set() in TypeScript)
+func (wm *WeakMap[K, V]) Set(key *K, value V) *WeakMap[K, V] {
+	handle := makeWeakHandle(key)
+
+	wm.mu.Lock()
+	defer wm.mu.Unlock()
+
+	if entry, exists := wm.entries[handle]; exists {
+		entry.value = value
+		return wm
+	}
+	// The cleanup holds the map weakly, so live keys do not keep a dropped map alive
+	self := makeWeakValue(wm)
+	entry := &weakEntry[V]{value: value}
+	entry.stop = onCollect(key, func() {
+		if m := self.value(); m != nil {
+			m.remove(handle, entry)
+		}
+	})
+	wm.entries[handle] = entry
+	return wm
+}
+
+// remove deletes an entry if it is still the current one for its key
+func (wm *WeakMap[K, V]) remove(handle weakHandle[K], entry *weakEntry[V]) {
+	wm.mu.Lock()
+	defer wm.mu.Unlock()
+
+	if wm.entries[handle] == entry {
+		delete(wm.entries, handle)
+	}
+}
+
+// Get retrieves a value by object key (like weakMap.get() in TypeScript)
+func (wm *WeakMap[K, V]) Get(key *K) Optional[V] {
+	handle, ok := lookupWeakHandle(key)
+	if !ok {
+		return None[V]()
+	}
+
+	wm.mu.Lock()
+	defer wm.mu.Unlock()
+
+	if entry, exists := wm.entries[handle]; exists {
+		return Some(entry.value)
+	}
+	return None[V]()
+}
+
+// Has checks if an object key exists (like weakMap.has() in TypeScript)
+func (wm *WeakMap[K, V]) Has(key *K) bool {
+	return wm.Get(key).IsSome()
+}
+
+// Delete removes an entry by object key (like weakMap.delete() in TypeScript)
+func (wm *WeakMap[K, V]) Delete(key *K) bool {
+	handle, ok := lookupWeakHandle(key)
+	if !ok {
+		return false
+	}
+
+	wm.mu.Lock()
+	entry, exists := wm.entries[handle]
+	delete(wm.entries, handle)
+	wm.mu.Unlock()
+
+	if exists {
+		entry.stop()
+	}
+	return exists
+}
+
+// WeakSet holds objects without keeping them alive (like WeakSet in TypeScript). An object is
+// removed after it is garbage collected.
+type WeakSet[T any] struct {
+	m *WeakMap[T, struct{}]
+}
+
+// NewWeakSet creates a new WeakSet (like new WeakSet() in TypeScript)
+func NewWeakSet[T any]() *WeakSet[T] {
+	return &WeakSet[T]{m: NewWeakMap[T, struct{}]()}
+}
+
+// Add adds an object to the set (like weakSet.add() in TypeScript)
+func (ws *WeakSet[T]) Add(value *T) *WeakSet[T] {
+	ws.m.Set(value, struct{}{})
+	return ws
+}
+
+// Has checks if an object is in the set (like weakSet.has() in TypeScript)
+func (ws *WeakSet[T]) Has(value *T) bool {
+	return ws.m.Has(value)
+}
+
+// Delete removes an object from the set (like weakSet.delete() in TypeScript)
+func (ws *WeakSet[T]) Delete(value *T) bool {
+	return ws.m.Delete(value)
+}
+
+// FinalizationRegistry calls a cleanup callback with a held value after a registered object is
+// garbage collected (like FinalizationRegistry in TypeScript). The callback runs on a runtime
+// goroutine and should return quickly.
+type FinalizationRegistry[T, H any] struct {
+	mu      sync.Mutex
+	cleanup func(heldValue H)
+	tokens  map[interface{}][]*registration
+}
+
+// registration is one Register call with an unregister token
+type registration struct {
+	stop func()
+}
+
+// NewFinalizationRegistry creates a registry (like new FinalizationRegistry(cleanup) in
+// TypeScript)
+func NewFinalizationRegistry[T, H any](cleanup func(heldValue H)) *FinalizationRegistry[T, H] {
+	return &FinalizationRegistry[T, H]{
+		cleanup: cleanup,
+		tokens:  make(map[interface{}][]*registration),
+	}
+}
+
+// Register calls the cleanup callback with heldValue after target is garbage collected (like
+// registry.register() in TypeScript). An optional comparable unregister token allows
+// Unregister; the registry holds the token and heldValue strongly, so neither may be or
+// reference target.
+func (r *FinalizationRegistry[T, H]) Register(target *T, heldValue H, unregisterToken ...interface{}) {
+	var token interface{}
+	if len(unregisterToken) > 0 {
+		token = unregisterToken[0]
+	}
+
+	// Like in JavaScript, a registry that is no longer reachable calls no more callbacks
+	self := makeWeakValue(r)
+	reg := &registration{}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reg.stop = onCollect(target, func() {
+		if registry := self.value(); registry != nil {
+			registry.finalize(token, reg, heldValue)
+		}
+	})
+	if token != nil {
+		r.tokens[token] = append(r.tokens[token], reg)
+	}
+}
+
+// finalize drops a registration whose target was collected and calls the cleanup callback
+func (r *FinalizationRegistry[T, H]) finalize(token interface{}, reg *registration, heldValue H) {
+	if token != nil {
+		r.mu.Lock()
+		regs := r.tokens[token]
+		for i, other := range regs {
+			if other == reg {
+				regs = append(regs[:i:i], regs[i+1:]...)
+				break
+			}
+		}
+		if len(regs) > 0 {
+			r.tokens[token] = regs
+		} else {
+			delete(r.tokens, token)
+		}
+		r.mu.Unlock()
+	}
+	r.cleanup(heldValue)
+}
+
+// Unregister cancels the pending registrations made with a token (like registry.unregister()
+// in TypeScript). It reports whether any registration was cancelled.
+func (r *FinalizationRegistry[T, H]) Unregister(token interface{}) bool {
+	r.mu.Lock()
+	regs := r.tokens[token]
+	delete(r.tokens, token)
+	r.mu.Unlock()
+
+	for _, reg := range regs {
+		reg.stop()
+	}
+	return len(regs) > 0
+}
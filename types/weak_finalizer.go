@@ -0,0 +1,113 @@
+//go:build !go1.24
+
+package types
+
+import (
+	"runtime"
+	"sync"
+	"unsafe"
+)
+
+// trackedObject holds the collection callbacks of an object with a finalizer
+type trackedObject struct {
+	id        uint64
+	callbacks map[uint64]func()
+	next      uint64
+}
+
+var (
+	trackedMu      sync.Mutex
+	trackedObjects = make(map[uintptr]*trackedObject)
+	trackedID      uint64
+)
+
+// weakHandle identifies an object without keeping it alive. The id tells apart objects that
+// reuse the address of a collected one. A handle is only compared, never dereferenced.
+type weakHandle[T any] struct {
+	addr uintptr
+	id   uint64
+}
+
+// track sets a finalizer on target the first time it is seen. Objects passed to it must not
+// have a finalizer of their own.
+func track[T any](target *T) (uintptr, *trackedObject) {
+	addr := uintptr(unsafe.Pointer(target))
+	if object, exists := trackedObjects[addr]; exists {
+		return addr, object
+	}
+	trackedID++
+	object := &trackedObject{id: trackedID, callbacks: make(map[uint64]func())}
+	trackedObjects[addr] = object
+	runtime.SetFinalizer(target, func(*T) {
+		trackedMu.Lock()
+		delete(trackedObjects, addr)
+		callbacks := make([]func(), 0, len(object.callbacks))
+		for _, fn := range object.callbacks {
+			callbacks = append(callbacks, fn)
+		}
+		object.callbacks = nil
+		trackedMu.Unlock()
+
+		for _, fn := range callbacks {
+			fn()
+		}
+	})
+	return addr, object
+}
+
+// makeWeakHandle returns the handle of an object
+func makeWeakHandle[T any](target *T) weakHandle[T] {
+	trackedMu.Lock()
+	defer trackedMu.Unlock()
+
+	addr, object := track(target)
+	return weakHandle[T]{addr: addr, id: object.id}
+}
+
+// lookupWeakHandle returns the handle of an object that was tracked before, without setting a
+// finalizer; ok is false for an untracked object, which no weak collection can hold
+func lookupWeakHandle[T any](target *T) (handle weakHandle[T], ok bool) {
+	trackedMu.Lock()
+	defer trackedMu.Unlock()
+
+	addr := uintptr(unsafe.Pointer(target))
+	object, exists := trackedObjects[addr]
+	if !exists {
+		return weakHandle[T]{}, false
+	}
+	return weakHandle[T]{addr: addr, id: object.id}, true
+}
+
+// weakValue stands in for a dereferenceable weak pointer. Before Go 1.24 the address of a
+// collected object cannot be turned back into a pointer safely, so it holds the object strongly.
+type weakValue[T any] struct {
+	target *T
+}
+
+// makeWeakValue returns a weakValue for an object
+func makeWeakValue[T any](target *T) weakValue[T] {
+	return weakValue[T]{target: target}
+}
+
+// value returns the object
+func (v weakValue[T]) value() *T {
+	return v.target
+}
+
+// onCollect calls fn on the finalizer goroutine after target becomes unreachable, and returns a
+// function that cancels the call. fn must not reference target, or it is never collected.
+func onCollect[T any](target *T, fn func()) func() {
+	trackedMu.Lock()
+	defer trackedMu.Unlock()
+
+	_, object := track(target)
+	object.next++
+	key := object.next
+	object.callbacks[key] = fn
+	return func() {
+		trackedMu.Lock()
+		defer trackedMu.Unlock()
+
+		delete(object.callbacks, key)
+	}
+}
@@ -0,0 +1,23 @@
+//go:build !go1.24
+
+package types
+
+import (
+	"testing"
+	"unsafe"
+)
+
+func TestWeakMapLookupsDoNotTrackKeys(t *testing.T) {
+	wm := NewWeakMap[weakTarget, int]()
+	key := &weakTarget{}
+	wm.Get(key)
+	wm.Has(key)
+	wm.Delete(key)
+
+	trackedMu.Lock()
+	_, tracked := trackedObjects[uintptr(unsafe.Pointer(key))]
+	trackedMu.Unlock()
+	if tracked {
+		t.Fatal("a lookup set a finalizer on the key")
+	}
+}
@@ -0,0 +1,47 @@
+//go:build go1.24
+
+package types
+
+import (
+	"runtime"
+	"weak"
+)
+
+// weakHandle identifies an object without keeping it alive. Handles of the same object are
+// equal, and a new object at a reused address gets a different handle.
+type weakHandle[T any] struct {
+	pointer weak.Pointer[T]
+}
+
+// makeWeakHandle returns the handle of an object
+func makeWeakHandle[T any](target *T) weakHandle[T] {
+	return weakHandle[T]{pointer: weak.Make(target)}
+}
+
+// lookupWeakHandle returns the handle of an object for a lookup; weak pointers need no
+// tracking, so it always succeeds
+func lookupWeakHandle[T any](target *T) (weakHandle[T], bool) {
+	return makeWeakHandle(target), true
+}
+
+// weakValue is a weakHandle that can be dereferenced
+type weakValue[T any] struct {
+	weakHandle[T]
+}
+
+// makeWeakValue returns a dereferenceable weak pointer to an object
+func makeWeakValue[T any](target *T) weakValue[T] {
+	return weakValue[T]{makeWeakHandle(target)}
+}
+
+// value returns the object, or nil once it has been garbage collected
+func (h weakHandle[T]) value() *T {
+	return h.pointer.Value()
+}
+
+// onCollect calls fn on a runtime goroutine after target becomes unreachable, and returns a
+// function that cancels the call. fn must not reference target, or it is never collected.
+func onCollect[T any](target *T, fn func()) func() {
+	cleanup := runtime.AddCleanup(target, func(fn func()) { fn() }, fn)
+	return cleanup.Stop
+}
@@ -0,0 +1,80 @@
+package types
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+// weakTarget is large enough and holds a pointer, so the runtime does not batch it with other
+// tiny allocations
+type weakTarget struct {
+	name    *string
+	payload [64]byte
+}
+
+// collectUntil runs the garbage collector until cond holds or a few seconds have passed
+func collectUntil(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	for i := 0; i < 200; i++ {
+		runtime.GC()
+		if cond() {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("%s was not collected", what)
+}
+
+func TestWeakMapDropsCollectedKeys(t *testing.T) {
+	wm := NewWeakMap[weakTarget, int]()
+	kept := &weakTarget{}
+	wm.Set(kept, 1)
+	func() {
+		wm.Set(&weakTarget{}, 2)
+	}()
+
+	collectUntil(t, "the WeakMap key", func() bool {
+		wm.mu.Lock()
+		defer wm.mu.Unlock()
+		return len(wm.entries) == 1
+	})
+	if value := wm.Get(kept); value.IsNone() || value.Get() != 1 {
+		t.Fatalf("live key lost its value: %v", value)
+	}
+	runtime.KeepAlive(kept)
+}
+
+func TestWeakMapLookupOfUnknownKey(t *testing.T) {
+	wm := NewWeakMap[weakTarget, int]()
+	key := &weakTarget{}
+	if wm.Has(key) || wm.Get(key).IsSome() || wm.Delete(key) {
+		t.Fatal("unknown key found")
+	}
+	wm.Set(key, 1)
+	if !wm.Delete(key) || wm.Has(key) {
+		t.Fatal("Delete did not remove the key")
+	}
+}
+
+func TestFinalizationRegistryCallsCleanup(t *testing.T) {
+	held := make(chan string, 1)
+	registry := NewFinalizationRegistry[weakTarget](func(value string) { held <- value })
+	func() {
+		registry.Register(&weakTarget{}, "collected")
+	}()
+
+	var got string
+	collectUntil(t, "the registered target", func() bool {
+		select {
+		case got = <-held:
+			return true
+		default:
+			return false
+		}
+	})
+	if got != "collected" {
+		t.Fatalf("held value = %q", got)
+	}
+	runtime.KeepAlive(registry)
+}
@@ -5,6 +5,8 @@ import (
 	"reflect"
 	"runtime"
 	"time"
+
+	"typescript-golang/timers"
 )
 
 // cacheEntry represents a cached value with expiry time
@@ -122,7 +124,8 @@ func Memoize[T any](fn T) T {
 	return wrapper.Interface().(T)
 }
 
-// Retry decorator that retries function on failure (like @retry in TypeScript)
+// Retry decorator that retries function on failure (like @retry in TypeScript).
+// For cancellable retries with backoff and jitter use async.RetryPromise.
 func Retry[T any](maxAttempts int, delay time.Duration) Decorator[T] {
 	return func(fn T) T {
 		fnValue := reflect.ValueOf(fn)
@@ -167,8 +170,10 @@ func Retry[T any](maxAttempts int, delay time.Duration) Decorator[T] {
 	}
 }
 
-// RateLimit decorator that limits function call frequency
-func RateLimit[T any](callsPerSecond int) Decorator[T] {
+// RateLimit decorator that limits function call frequency.
+// An optional clock replaces the default one, e.g. a timers.FakeClock in tests.
+func RateLimit[T any](callsPerSecond int, clock ...timers.Clock) Decorator[T] {
+	c := timers.Resolve(clock...)
 	return func(fn T) T {
 		fnValue := reflect.ValueOf(fn)
 		if fnValue.Kind() != reflect.Func {
@@ -179,14 +184,14 @@ func RateLimit[T any](callsPerSecond int) Decorator[T] {
 		lastCall := time.Time{}
 		
 		wrapper := reflect.MakeFunc(fnValue.Type(), func(args []reflect.Value) []reflect.Value {
-			now := time.Now()
-			if elapsed := now.Sub(lastCall); elapsed < interval {
+			now := c.Now()
+			if elapsed := now.Sub(lastCall); !lastCall.IsZero() && elapsed < interval {
 				sleep := interval - elapsed
 				fmt.Printf("[RATE_LIMIT] Rate limited, sleeping for %v\n", sleep)
-				time.Sleep(sleep)
+				c.Sleep(sleep)
 			}
 			
-			lastCall = time.Now()
+			lastCall = c.Now()
 			return fnValue.Call(args)
 		})
 
@@ -249,8 +254,10 @@ func Deprecated[T any](message string) Decorator[T] {
 	}
 }
 
-// Cache decorator with TTL (Time To Live)
-func CacheWithTTL[T any](ttl time.Duration) Decorator[T] {
+// Cache decorator with TTL (Time To Live).
+// An optional clock replaces the default one, e.g. a timers.FakeClock in tests.
+func CacheWithTTL[T any](ttl time.Duration, clock ...timers.Clock) Decorator[T] {
+	c := timers.Resolve(clock...)
 	return func(fn T) T {
 		fnValue := reflect.ValueOf(fn)
 		if fnValue.Kind() != reflect.Func {
@@ -261,7 +268,7 @@ func CacheWithTTL[T any](ttl time.Duration) Decorator[T] {
 		
 		wrapper := reflect.MakeFunc(fnValue.Type(), func(args []reflect.Value) []reflect.Value {
 			key := fmt.Sprintf("%v", args)
-			now := time.Now()
+			now := c.Now()
 			
 			// Check cache and TTL
 			if entry, exists := cache[key]; exists && now.Before(entry.expiry) {